@@ -21,6 +21,8 @@ import (
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"github.com/yhonda-ohishi-pub-dev/go_auth/pkg/authmiddleware"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/alerting"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/api"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/certs"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/cloudflare"
@@ -28,10 +30,13 @@ import (
 	grpcserver "github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/grpc"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/handlers"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/loadbalancer"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/monitor"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/poller"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
 	pb "github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/proto"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/secrets"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/storage"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/systray"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/tunnel"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/update"
@@ -237,20 +242,21 @@ func main() {
 	}
 	log.Printf("Version manager initialized")
 
-	// Initialize process manager
+	// Initialize process and update managers; the update manager is set
+	// before Initialize starts the supervisor, which reads it
 	processManager := process.NewManager(cfg, certManager, secretManager)
 	processManager.SetVersionManager(versionManager)
-	if err := processManager.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize process manager: %v", err)
-	}
-	log.Printf("Process manager initialized with %d processes", len(cfg.Processes))
-
-	// Initialize update manager
 	updateManager, err := update.NewManager(processManager, versionManager, *binariesDir)
 	if err != nil {
 		log.Fatalf("Failed to create update manager: %v", err)
 	}
 	processManager.SetUpdateManager(updateManager)
+	if err := processManager.Initialize(); err != nil {
+		log.Fatalf("Failed to initialize process manager: %v", err)
+	}
+	log.Printf("Process manager initialized with %d processes", len(cfg.Processes))
+
+	updateManager.RestoreDeployments()
 	log.Printf("Update manager initialized (binaries: %s)", *binariesDir)
 
 	// Start configured processes
@@ -263,6 +269,18 @@ func main() {
 		}
 	}
 
+	// Start metrics history sampler
+	historyDir := ""
+	if cfg.Metrics.Persist {
+		historyDir = *dataDir
+	}
+	metricsStore := storage.NewTimeSeriesStore(cfg.Metrics.SampleInterval, historyDir)
+	if err := metricsStore.Load(); err != nil {
+		log.Printf("Warning: Failed to load metrics history: %v", err)
+	}
+	sampler := monitor.NewSampler(processManager, metricsStore, cfg.Metrics.SampleInterval)
+	sampler.Start()
+
 	// Initialize gRPC server with dynamic port if needed
 	grpcPort := cfg.Server.GRPCPort
 	grpcAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, grpcPort)
@@ -289,6 +307,8 @@ func main() {
 
 	grpcSrv := grpc.NewServer()
 	grpcServiceServer := grpcserver.NewServer(processManager, updateManager, repositoryList)
+	grpcServiceServer.SetMetricsStore(metricsStore)
+	grpcServiceServer.SetSampler(sampler)
 	pb.RegisterProcessManagerServer(grpcSrv, grpcServiceServer)
 
 	// Register TunnelService (for gRPC-Web access via Cloudflare Tunnel)
@@ -322,6 +342,8 @@ func main() {
 
 	// Initialize REST API server with webhook routes and gRPC-Web
 	apiServer := api.NewServer(processManager, updateManager)
+	apiServer.SetMetricsStore(metricsStore)
+	apiServer.SetSampler(sampler)
 	mux := http.NewServeMux()
 	mux.Handle("/api/", apiServer)
 	mux.HandleFunc("/webhook/github", webhookHandler.HandleGitHubWebhook)
@@ -331,6 +353,10 @@ func main() {
 		w.Write([]byte(`{"status":"healthy"}`))
 	})
 
+	// Prometheus metrics endpoint
+	monitor.RegisterCollector(processManager, updateManager, sampler)
+	mux.Handle("/metrics", metrics.Handler())
+
 	// gRPC レジストリエンドポイント - プロキシ可能なプロセス一覧を返す
 	registryHandler := handlers.NewRegistryHandler(processManager, cfg.Server.Host, cfg.Server.Port)
 	mux.HandleFunc("/api/registry", registryHandler.GetRegistry)
@@ -342,6 +368,18 @@ func main() {
 	// gRPC-Web プロキシハンドラー - ネイティブgRPCバックエンドへのプロキシ
 	grpcProxyHandler := handlers.NewGrpcProxyHandler(processManager)
 
+	// Access log of proxied calls (load balancers and /proxy/)
+	var accessLogger *accesslog.Logger
+	if cfg.AccessLog != nil {
+		accessLogger, err = accesslog.NewLogger(cfg.AccessLog)
+		if err != nil {
+			log.Fatalf("Failed to open access log: %v", err)
+		}
+		grpcProxyHandler.SetAccessLogger(accessLogger)
+		apiServer.SetAccessLogger(accessLogger)
+		log.Printf("Access log enabled (%s, sample rate %g)", cfg.AccessLog.Path, cfg.AccessLog.SampleRate)
+	}
+
 	// Graceful shutdown endpoint (for replacing running instances)
 	mux.HandleFunc("/shutdown", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
@@ -367,10 +405,15 @@ func main() {
 		if err != nil {
 			log.Fatalf("Failed to create load balancer manager: %v", err)
 		}
+		lbManager.SetCertManager(certManager)
+		lbManager.SetAccessLogger(accessLogger)
 		if err := lbManager.Start(); err != nil {
 			log.Fatalf("Failed to start load balancers: %v", err)
 		}
 		log.Printf("Load balancers initialized and started")
+		apiServer.SetLoadBalancerManager(lbManager)
+		grpcServiceServer.SetLoadBalancerManager(lbManager)
+		updateManager.SetLoadBalancerManager(lbManager)
 	}
 
 	// Start Cloudflare Tunnel if enabled
@@ -382,6 +425,23 @@ func main() {
 		}
 	}
 
+	// Initialize alerting if configured (rules are evaluated by the process supervisor)
+	if cfg.Alerting != nil {
+		alertEngine, err := alerting.NewEngine(cfg.Alerting, processManager)
+		if err != nil {
+			log.Fatalf("Failed to create alert engine: %v", err)
+		}
+		alertEngine.SetUpdateManager(updateManager)
+		alertEngine.SetSampler(sampler)
+		if tunnelManager != nil {
+			alertEngine.SetTunnelManager(tunnelManager)
+		}
+		processManager.SetAlertEvaluator(alertEngine)
+		grpcServiceServer.SetAlertEngine(alertEngine)
+		apiServer.SetAlertEngine(alertEngine)
+		log.Printf("Alerting initialized with %d rules and %d notifiers", len(cfg.Alerting.Rules), len(cfg.Alerting.Notifiers))
+	}
+
 	// Create base handler with CORS and gRPC-Web support
 	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Add CORS headers for all requests
@@ -587,6 +647,14 @@ func main() {
 		log.Printf("HTTP server shutdown error: %v", err)
 	}
 
+	// Flush the access log once no more calls are proxied
+	if err := accessLogger.Close(); err != nil {
+		log.Printf("Access log close error: %v", err)
+	}
+
+	// Stop metrics sampler (saves history)
+	sampler.Stop()
+
 	// Shutdown all processes
 	if err := processManager.Shutdown(); err != nil {
 		log.Printf("Process manager shutdown error: %v", err)
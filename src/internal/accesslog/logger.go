@@ -0,0 +1,217 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// queueSize bounds the entries waiting to be written; entries beyond it are
+// dropped so a slow disk never holds up proxied calls
+const queueSize = 4096
+
+var (
+	accessLogEntries = metrics.NewCounterVec("gowinproc_access_log_entries_total",
+		"Access log entries recorded", "source")
+	accessLogDropped = metrics.NewCounterVec("gowinproc_access_log_dropped_total",
+		"Access log entries dropped because the writer fell behind")
+)
+
+// Sources of entries
+const (
+	SourceLoadBalancer = "loadbalancer"
+	SourceGrpcProxy    = "grpc_proxy"
+)
+
+// Entry is the record of one proxied call
+type Entry struct {
+	Time          time.Time         `json:"time"`
+	Source        string            `json:"source"`
+	Balancer      string            `json:"balancer,omitempty"`
+	Method        string            `json:"method"` // gRPC method, or "GET /path" for HTTP
+	Route         string            `json:"route,omitempty"`
+	Process       string            `json:"process,omitempty"`
+	Instance      string            `json:"instance,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Backend       string            `json:"backend,omitempty"`
+	Code          string            `json:"code"` // gRPC status code, or HTTP status for HTTP
+	Failed        bool              `json:"failed,omitempty"`
+	RequestBytes  int64             `json:"request_bytes"`
+	ResponseBytes int64             `json:"response_bytes"`
+	DurationMs    float64           `json:"duration_ms"`
+	Peer          string            `json:"peer,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// Filter selects entries returned by Recent; empty fields match anything
+type Filter struct {
+	Source   string
+	Balancer string
+	Process  string
+	Method   string // substring
+	Code     string
+	Failed   bool // only failed calls
+}
+
+// Logger writes access log entries as JSON lines to a rotating file and keeps
+// the most recent ones in memory. A nil Logger discards everything.
+type Logger struct {
+	sampleRate   float64
+	metadataKeys []string
+
+	mu     sync.Mutex
+	recent []Entry // ring buffer
+	next   int
+	full   bool
+	closed bool
+
+	queue chan Entry
+	file  *rotatingFile
+	done  chan struct{}
+}
+
+// NewLogger opens the log file and starts the writer
+func NewLogger(cfg *models.AccessLogConfig) (*Logger, error) {
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("access_log.sample_rate must be between 0 and 1")
+	}
+	file, err := openRotatingFile(cfg.Path, int64(cfg.MaxSizeMB)*1024*1024, cfg.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(cfg.MetadataKeys))
+	for i, k := range cfg.MetadataKeys {
+		keys[i] = strings.ToLower(k)
+	}
+
+	l := &Logger{
+		sampleRate:   cfg.SampleRate,
+		metadataKeys: keys,
+		recent:       make([]Entry, cfg.Recent),
+		queue:        make(chan Entry, queueSize),
+		file:         file,
+		done:         make(chan struct{}),
+	}
+	go l.run()
+	return l, nil
+}
+
+// Sampled reports whether a call should be logged. Failed calls always are;
+// callers check before building the entry.
+func (l *Logger) Sampled(failed bool) bool {
+	if l == nil {
+		return false
+	}
+	return failed || l.sampleRate >= 1 || rand.Float64() < l.sampleRate
+}
+
+// Metadata returns the configured metadata keys present in a call. get
+// returns the values sent for a (lower case) key.
+func (l *Logger) Metadata(get func(key string) []string) map[string]string {
+	var md map[string]string
+	for _, key := range l.metadataKeys {
+		if values := get(key); len(values) > 0 {
+			if md == nil {
+				md = make(map[string]string, len(l.metadataKeys))
+			}
+			md[key] = strings.Join(values, ",")
+		}
+	}
+	return md
+}
+
+// Log records an entry; it is written to the file in the background
+func (l *Logger) Log(e Entry) {
+	if l == nil {
+		return
+	}
+	accessLogEntries.Inc(e.Source)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.recent) > 0 {
+		l.recent[l.next] = e
+		l.next = (l.next + 1) % len(l.recent)
+		if l.next == 0 {
+			l.full = true
+		}
+	}
+	if l.closed {
+		return
+	}
+
+	select {
+	case l.queue <- e:
+	default:
+		accessLogDropped.Inc()
+	}
+}
+
+// Recent returns up to limit of the most recent entries matching the filter,
+// newest first
+func (l *Logger) Recent(limit int, f Filter) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.recent)
+	}
+	entries := make([]Entry, 0, min(limit, n))
+	for i := 1; i <= n && len(entries) < limit; i++ {
+		e := l.recent[(l.next-i+len(l.recent))%len(l.recent)]
+		if f.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (f Filter) matches(e Entry) bool {
+	switch {
+	case f.Source != "" && e.Source != f.Source,
+		f.Balancer != "" && e.Balancer != f.Balancer,
+		f.Process != "" && e.Process != f.Process,
+		f.Method != "" && !strings.Contains(e.Method, f.Method),
+		f.Code != "" && !strings.EqualFold(e.Code, f.Code),
+		f.Failed && !e.Failed:
+		return false
+	}
+	return true
+}
+
+// run writes queued entries until Close
+func (l *Logger) run() {
+	defer close(l.done)
+	for e := range l.queue {
+		line, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		if err := l.file.write(append(line, '\n')); err != nil {
+			log.Printf("Access log: %v", err)
+		}
+	}
+}
+
+// Close writes the queued entries and closes the file. Entries logged
+// afterwards are only kept in memory.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	l.closed = true
+	close(l.queue)
+	l.mu.Unlock()
+	<-l.done
+	return l.file.close()
+}
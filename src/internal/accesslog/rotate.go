@@ -0,0 +1,91 @@
+package accesslog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// rotatingFile appends to a file and renames it to <path>.1 when it reaches
+// maxBytes, shifting older files up to <path>.<maxBackups>
+type rotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	f    *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxBytes int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create access log directory: %w", err)
+	}
+	r := &rotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open access log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat access log: %w", err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// write appends a line, rotating first if it would exceed the size limit
+func (r *rotatingFile) write(line []byte) error {
+	if r.f == nil {
+		// A previous rotation failed to reopen the file; try again
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(line)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := r.f.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write access log: %w", err)
+	}
+	return nil
+}
+
+// rotate closes the current file, shifts the backups and opens a new file
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	r.f = nil
+
+	// Windows cannot rename onto an existing file, so remove the oldest first
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.maxBackups > 0 {
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate access log: %w", err)
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return fmt.Errorf("failed to rotate access log: %w", err)
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) close() error {
+	if r.f == nil {
+		return nil
+	}
+	return r.f.Close()
+}
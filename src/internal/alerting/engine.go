@@ -0,0 +1,515 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/github"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/monitor"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/tunnel"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/update"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// Alert states
+const (
+	StatePending  = "pending"
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// counterWindow is how long counter history is kept for increase() variables
+const counterWindow = time.Hour + 5*time.Minute
+
+// processVariables are evaluated once per process; rules using any of them are per-process rules
+var processVariables = map[string]bool{
+	"status":                true, // running, degraded, failed, stopped or scaled_to_zero
+	"instances":             true,
+	"running_instances":     true,
+	"failed_instances":      true,
+	"restarts":              true, // automatic restarts since startup
+	"restarts_15m":          true,
+	"restarts_1h":           true,
+	"health_failures":       true, // highest consecutive health check failures of any instance
+	"health_failures_total": true,
+	"unhealthy_instances":   true,
+	"cpu_percent":           true, // sum over instances
+	"memory_bytes":          true,
+	"memory_mb":             true,
+	"threads":               true,
+	"update_failed":         true,
+	"update_in_progress":    true,
+	"update_stage":          true,
+}
+
+// globalVariables describe the server itself
+var globalVariables = map[string]bool{
+	"tunnel_enabled":          true,
+	"tunnel_down":             true,
+	"github_api_errors_total": true,
+	"github_api_errors_15m":   true,
+	"github_api_errors_1h":    true,
+}
+
+var (
+	alertsActive = metrics.NewGaugeVec("gowinproc_alerts_active",
+		"Number of active alerts by severity and state", "severity", "state")
+	alertNotifications = metrics.NewCounterVec("gowinproc_alert_notifications_total",
+		"Alert notifications by notifier and result", "notifier", "result")
+)
+
+// Alert is an active (pending or firing) alert instance
+type Alert struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Severity     string    `json:"severity"`
+	Process      string    `json:"process,omitempty"`
+	Summary      string    `json:"summary,omitempty"`
+	Expr         string    `json:"expr"`
+	Value        string    `json:"value,omitempty"` // Variables of the expression at the last evaluation
+	State        string    `json:"state"`
+	ActiveSince  time.Time `json:"active_since"`
+	FiredAt      time.Time `json:"fired_at,omitempty"`
+	ResolvedAt   time.Time `json:"resolved_at,omitempty"`
+	LastNotified time.Time `json:"last_notified,omitempty"`
+	Silenced     bool      `json:"silenced"`
+}
+
+// labels returns the labels silences match against
+func (a *Alert) labels() map[string]string {
+	return map[string]string{
+		"alertname": a.Name,
+		"process":   a.Process,
+		"severity":  a.Severity,
+	}
+}
+
+// rule is a compiled alert rule
+type rule struct {
+	cfg        models.AlertRule
+	expr       *expression
+	perProcess bool
+	processes  map[string]bool
+	notifiers  []Notifier
+	lastError  string
+}
+
+// counterPoint is a sample of a monotonically increasing counter
+type counterPoint struct {
+	t     time.Time
+	value float64
+}
+
+// Engine evaluates alert rules and sends notifications
+type Engine struct {
+	rules          []*rule
+	repeatInterval time.Duration
+
+	procMgr   *process.Manager
+	updateMgr *update.Manager
+	sampler   *monitor.Sampler
+	tunnelMgr *tunnel.Manager
+
+	counters map[string][]counterPoint
+	alerts   map[string]*Alert
+	silences map[string]*Silence
+	nextID   int
+	mu       sync.Mutex
+}
+
+// NewEngine compiles the alert rules and notifiers of the configuration
+func NewEngine(cfg *models.AlertingConfig, procMgr *process.Manager) (*Engine, error) {
+	notifiers := make(map[string]Notifier)
+	var all []Notifier
+	for _, nc := range cfg.Notifiers {
+		n, err := newNotifier(nc)
+		if err != nil {
+			return nil, err
+		}
+		notifiers[nc.Name] = n
+		all = append(all, n)
+	}
+
+	e := &Engine{
+		repeatInterval: cfg.RepeatInterval,
+		procMgr:        procMgr,
+		counters:       make(map[string][]counterPoint),
+		alerts:         make(map[string]*Alert),
+		silences:       make(map[string]*Silence),
+	}
+
+	for i, rc := range cfg.Rules {
+		expr, err := compileExpr(rc.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: invalid expression: %w", rc.Name, err)
+		}
+		if err := expr.check(processVariables, globalVariables); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rc.Name, err)
+		}
+
+		r := &rule{cfg: rc, expr: expr, perProcess: expr.uses(processVariables) || len(rc.Processes) > 0}
+		if len(rc.Processes) > 0 {
+			r.processes = make(map[string]bool)
+			for _, name := range rc.Processes {
+				r.processes[name] = true
+			}
+		}
+		if len(rc.Notifiers) == 0 {
+			r.notifiers = all
+		} else {
+			for _, name := range rc.Notifiers {
+				n, ok := notifiers[name]
+				if !ok {
+					return nil, fmt.Errorf("rule %s: unknown notifier %q", rc.Name, name)
+				}
+				r.notifiers = append(r.notifiers, n)
+			}
+		}
+		e.rules = append(e.rules, r)
+		log.Printf("[Alerting] Rule %d: %s (%s, for %v): %s", i, rc.Name, rc.Severity, rc.For, rc.Expr)
+	}
+
+	for _, sc := range cfg.Silences {
+		if _, err := e.AddSilence(Silence{
+			Matchers: sc.Matchers,
+			StartsAt: sc.StartsAt,
+			EndsAt:   sc.EndsAt,
+			Comment:  sc.Comment,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// SetUpdateManager sets the update manager used for update_* variables
+func (e *Engine) SetUpdateManager(updateMgr *update.Manager) {
+	e.updateMgr = updateMgr
+}
+
+// SetSampler sets the sampler used for resource usage variables
+func (e *Engine) SetSampler(sampler *monitor.Sampler) {
+	e.sampler = sampler
+}
+
+// SetTunnelManager sets the tunnel manager used for tunnel_* variables
+func (e *Engine) SetTunnelManager(tunnelMgr *tunnel.Manager) {
+	e.tunnelMgr = tunnelMgr
+}
+
+// Evaluate evaluates all rules, updates alert states and sends notifications
+func (e *Engine) Evaluate(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	processes := e.procMgr.ListProcesses()
+	e.recordCounters(now, processes)
+
+	global := e.globalVars(now)
+	procVars := make(map[string]map[string]interface{})
+	seen := make(map[string]bool)
+
+	for _, r := range e.rules {
+		if !r.perProcess {
+			e.evaluateRule(r, "", global, now, seen)
+			continue
+		}
+		for _, name := range processes {
+			if r.processes != nil && !r.processes[name] {
+				continue
+			}
+			vars, ok := procVars[name]
+			if !ok {
+				vars = e.processVars(name, now)
+				for k, v := range global {
+					vars[k] = v
+				}
+				procVars[name] = vars
+			}
+			e.evaluateRule(r, name, vars, now, seen)
+		}
+	}
+
+	// Alerts whose process is gone or whose rule didn't match resolve
+	for id, alert := range e.alerts {
+		if !seen[id] {
+			e.resolve(id, alert, now)
+		}
+	}
+
+	e.updateGauges()
+}
+
+// evaluateRule evaluates a rule for one process (or globally) and updates its alert
+func (e *Engine) evaluateRule(r *rule, processName string, vars map[string]interface{}, now time.Time, seen map[string]bool) {
+	id := r.cfg.Name
+	if processName != "" {
+		id += "/" + processName
+	}
+
+	matched, err := r.expr.match(vars)
+	if err != nil {
+		if msg := err.Error(); msg != r.lastError {
+			log.Printf("[Alerting] Rule %s: evaluation failed: %v", r.cfg.Name, err)
+			r.lastError = msg
+		}
+		// Keep the current state rather than resolving on missing data
+		if _, ok := e.alerts[id]; ok {
+			seen[id] = true
+		}
+		return
+	}
+	r.lastError = ""
+
+	if !matched {
+		return
+	}
+	seen[id] = true
+
+	alert, ok := e.alerts[id]
+	if !ok {
+		alert = &Alert{
+			ID:          id,
+			Name:        r.cfg.Name,
+			Severity:    r.cfg.Severity,
+			Process:     processName,
+			Summary:     strings.ReplaceAll(r.cfg.Summary, "{{process}}", processName),
+			Expr:        r.cfg.Expr,
+			State:       StatePending,
+			ActiveSince: now,
+		}
+		e.alerts[id] = alert
+	}
+	alert.Value = describe(r.expr, vars)
+	alert.Silenced = e.silenced(alert, now)
+
+	if alert.State == StatePending && now.Sub(alert.ActiveSince) >= r.cfg.For {
+		alert.State = StateFiring
+		alert.FiredAt = now
+		log.Printf("[Alerting] FIRING %s (%s): %s", id, alert.Severity, alert.Value)
+	}
+
+	if alert.State != StateFiring || alert.Silenced {
+		return
+	}
+
+	// De-duplicate: notify once when firing starts, then every repeat interval
+	if alert.LastNotified.IsZero() || now.Sub(alert.LastNotified) >= e.repeatInterval {
+		alert.LastNotified = now
+		e.send(r.notifiers, StateFiring, alert)
+	}
+}
+
+// resolve removes an alert and notifies if it had fired
+func (e *Engine) resolve(id string, alert *Alert, now time.Time) {
+	delete(e.alerts, id)
+	if alert.State != StateFiring {
+		return
+	}
+
+	alert.State = StateResolved
+	alert.ResolvedAt = now
+	log.Printf("[Alerting] RESOLVED %s after %v", id, now.Sub(alert.ActiveSince).Round(time.Second))
+
+	if alert.LastNotified.IsZero() {
+		return // never notified (e.g. silenced the whole time)
+	}
+	for _, r := range e.rules {
+		if r.cfg.Name == alert.Name {
+			e.send(r.notifiers, StateResolved, alert)
+			break
+		}
+	}
+}
+
+// send delivers a notification to notifiers in the background
+func (e *Engine) send(notifiers []Notifier, status string, alert *Alert) {
+	copied := *alert
+	n := Notification{Status: status, Alert: &copied, SentAt: time.Now()}
+
+	for _, notifier := range notifiers {
+		go func(notifier Notifier) {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+
+			if err := notifier.Notify(ctx, n); err != nil {
+				alertNotifications.Inc(notifier.Name(), "error")
+				log.Printf("[Alerting] Failed to notify %s about %s: %v", notifier.Name(), n.Alert.ID, err)
+				return
+			}
+			alertNotifications.Inc(notifier.Name(), "sent")
+		}(notifier)
+	}
+}
+
+// updateGauges refreshes the active alert gauges
+func (e *Engine) updateGauges() {
+	alertsActive.Reset()
+	for _, alert := range e.alerts {
+		alertsActive.Inc(alert.Severity, alert.State)
+	}
+}
+
+// ActiveAlerts returns all pending and firing alerts sorted by ID
+func (e *Engine) ActiveAlerts() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+	return alerts
+}
+
+// --- Variables ---
+
+// recordCounters samples counters used by increase() variables
+func (e *Engine) recordCounters(now time.Time, processes []string) {
+	add := func(key string, value float64) {
+		points := append(e.counters[key], counterPoint{t: now, value: value})
+		for len(points) > 1 && now.Sub(points[0].t) > counterWindow {
+			points = points[1:]
+		}
+		e.counters[key] = points
+	}
+
+	live := make(map[string]bool)
+	for _, name := range processes {
+		live["restarts/"+name] = true
+		add("restarts/"+name, float64(e.procMgr.GetRestartCount(name)))
+	}
+	live["github_errors"] = true
+	add("github_errors", github.ErrorCount())
+
+	for key := range e.counters {
+		if !live[key] {
+			delete(e.counters, key)
+		}
+	}
+}
+
+// increase returns how much a counter grew over the window
+func (e *Engine) increase(key string, window time.Duration, now time.Time) float64 {
+	points := e.counters[key]
+	if len(points) == 0 {
+		return 0
+	}
+
+	base := points[0]
+	for _, p := range points {
+		if p.t.After(now.Add(-window)) {
+			break
+		}
+		base = p
+	}
+	if delta := points[len(points)-1].value - base.value; delta > 0 {
+		return delta
+	}
+	return 0
+}
+
+// globalVars returns the server-wide variables
+func (e *Engine) globalVars(now time.Time) map[string]interface{} {
+	vars := map[string]interface{}{
+		"tunnel_enabled":          e.tunnelMgr != nil,
+		"tunnel_down":             e.tunnelMgr != nil && !e.tunnelMgr.IsRunning(),
+		"github_api_errors_total": github.ErrorCount(),
+		"github_api_errors_15m":   e.increase("github_errors", 15*time.Minute, now),
+		"github_api_errors_1h":    e.increase("github_errors", time.Hour, now),
+	}
+	return vars
+}
+
+// processVars returns the variables of a process
+func (e *Engine) processVars(name string, now time.Time) map[string]interface{} {
+	vars := make(map[string]interface{})
+
+	instances, _ := e.procMgr.GetProcessStatus(name)
+	var running, failed int
+	for _, inst := range instances {
+		switch inst.GetStatus() {
+		case models.StatusRunning:
+			running++
+		case models.StatusFailed:
+			failed++
+		}
+	}
+
+	status := "stopped"
+	switch {
+	case e.procMgr.IsScaledToZero(name):
+		status = "scaled_to_zero"
+	case running > 0 && running == len(instances):
+		status = "running"
+	case running > 0:
+		status = "degraded"
+	case failed > 0:
+		status = "failed"
+	}
+
+	vars["status"] = status
+	vars["instances"] = float64(len(instances))
+	vars["running_instances"] = float64(running)
+	vars["failed_instances"] = float64(failed)
+	vars["restarts"] = float64(e.procMgr.GetRestartCount(name))
+	vars["restarts_15m"] = e.increase("restarts/"+name, 15*time.Minute, now)
+	vars["restarts_1h"] = e.increase("restarts/"+name, time.Hour, now)
+
+	health := e.procMgr.GetHealthStatus(name)
+	vars["health_failures"] = float64(health.ConsecutiveFailures)
+	vars["health_failures_total"] = float64(health.TotalFailures)
+	vars["unhealthy_instances"] = float64(health.UnhealthyInstances)
+
+	if e.sampler != nil {
+		total := monitor.Total(name, e.sampler.Latest(name))
+		vars["cpu_percent"] = total.CPUPercent
+		vars["memory_bytes"] = float64(total.MemoryBytes)
+		vars["memory_mb"] = float64(total.MemoryBytes) / (1024 * 1024)
+		vars["threads"] = float64(total.Threads)
+	}
+
+	vars["update_failed"] = false
+	vars["update_in_progress"] = false
+	vars["update_stage"] = ""
+	if e.updateMgr != nil {
+		if st, exists := e.updateMgr.GetUpdateStatus(name); exists {
+			vars["update_failed"] = st.Stage == "failed"
+			vars["update_in_progress"] = !st.Completed
+			vars["update_stage"] = st.Stage
+		}
+	}
+
+	return vars
+}
+
+// describe formats the variables referenced by an expression
+func describe(expr *expression, vars map[string]interface{}) string {
+	names := make([]string, 0, len(expr.idents))
+	for name := range expr.idents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		switch v := vars[name].(type) {
+		case float64:
+			parts = append(parts, fmt.Sprintf("%s=%g", name, v))
+		case string:
+			parts = append(parts, fmt.Sprintf("%s=%q", name, v))
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%v", name, v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,173 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// notifyTimeout bounds a single notification attempt
+const notifyTimeout = 15 * time.Second
+
+// Notification is sent to notifiers when an alert fires, repeats or resolves
+type Notification struct {
+	Status string    `json:"status"` // "firing" or "resolved"
+	Alert  *Alert    `json:"alert"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// Notifier delivers alert notifications to an external channel
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, n Notification) error
+}
+
+// newNotifier creates a notifier from its configuration
+func newNotifier(cfg models.NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "webhook":
+		return &webhookNotifier{name: cfg.Name, url: cfg.URL, headers: cfg.Headers}, nil
+	case "slack":
+		return &slackNotifier{name: cfg.Name, url: cfg.URL}, nil
+	case "email":
+		if cfg.SMTP == nil {
+			return nil, fmt.Errorf("notifier %s: smtp configuration is required", cfg.Name)
+		}
+		return &emailNotifier{name: cfg.Name, cfg: *cfg.SMTP}, nil
+	}
+	return nil, fmt.Errorf("notifier %s: unknown type %q", cfg.Name, cfg.Type)
+}
+
+// title returns a one-line description of a notification
+func (n Notification) title() string {
+	status := strings.ToUpper(n.Status)
+	if n.Alert.Process != "" {
+		return fmt.Sprintf("[%s] %s (%s) on %s", status, n.Alert.Name, n.Alert.Severity, n.Alert.Process)
+	}
+	return fmt.Sprintf("[%s] %s (%s)", status, n.Alert.Name, n.Alert.Severity)
+}
+
+// body returns a multi-line description of a notification
+func (n Notification) body() string {
+	var b strings.Builder
+	b.WriteString(n.title() + "\n")
+	if n.Alert.Summary != "" {
+		b.WriteString(n.Alert.Summary + "\n")
+	}
+	fmt.Fprintf(&b, "Expression: %s\n", n.Alert.Expr)
+	if n.Alert.Value != "" {
+		fmt.Fprintf(&b, "Value: %s\n", n.Alert.Value)
+	}
+	fmt.Fprintf(&b, "Active since: %s\n", n.Alert.ActiveSince.Format(time.RFC3339))
+	if n.Status == StateResolved {
+		fmt.Fprintf(&b, "Resolved at: %s\n", n.Alert.ResolvedAt.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// postJSON posts a JSON payload and checks for a 2xx response
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookNotifier posts the notification as generic JSON
+type webhookNotifier struct {
+	name    string
+	url     string
+	headers map[string]string
+}
+
+func (w *webhookNotifier) Name() string { return w.name }
+
+func (w *webhookNotifier) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, w.url, w.headers, n)
+}
+
+// slackNotifier posts to a Slack-compatible incoming webhook
+type slackNotifier struct {
+	name string
+	url  string
+}
+
+func (s *slackNotifier) Name() string { return s.name }
+
+func (s *slackNotifier) Notify(ctx context.Context, n Notification) error {
+	icon := ":rotating_light:"
+	if n.Status == StateResolved {
+		icon = ":white_check_mark:"
+	} else if n.Alert.Severity == "info" {
+		icon = ":information_source:"
+	}
+	return postJSON(ctx, s.url, nil, map[string]string{
+		"text": icon + " " + n.body(),
+	})
+}
+
+// emailNotifier sends the notification via SMTP
+type emailNotifier struct {
+	name string
+	cfg  models.SMTPConfig
+}
+
+func (e *emailNotifier) Name() string { return e.name }
+
+func (e *emailNotifier) Notify(ctx context.Context, n Notification) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", n.title())
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(n.body(), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+	addr := fmt.Sprintf("%s:%d", e.cfg.Host, e.cfg.Port)
+
+	// net/smtp has no context support; run it in the background and honour the deadline
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, e.cfg.From, e.cfg.To, msg.Bytes())
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email: %w", ctx.Err())
+	}
+}
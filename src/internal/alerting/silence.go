@@ -0,0 +1,103 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Silence suppresses notifications of alerts whose labels match all matchers
+type Silence struct {
+	ID       string            `json:"id"`
+	Matchers map[string]string `json:"matchers"` // alertname, process, severity
+	StartsAt time.Time         `json:"starts_at"`
+	EndsAt   time.Time         `json:"ends_at,omitempty"` // Zero = never expires
+	Comment  string            `json:"comment,omitempty"`
+}
+
+// active reports whether the silence is in effect at now
+func (s *Silence) active(now time.Time) bool {
+	if now.Before(s.StartsAt) {
+		return false
+	}
+	return s.EndsAt.IsZero() || now.Before(s.EndsAt)
+}
+
+// matches reports whether all matchers equal the alert's labels
+func (s *Silence) matches(alert *Alert) bool {
+	labels := alert.labels()
+	for k, v := range s.Matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// silenced reports whether any active silence matches the alert. Callers must hold e.mu.
+func (e *Engine) silenced(alert *Alert, now time.Time) bool {
+	for _, s := range e.silences {
+		if s.active(now) && s.matches(alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSilence adds a silence and returns it with its assigned ID
+func (e *Engine) AddSilence(s Silence) (Silence, error) {
+	if len(s.Matchers) == 0 {
+		return Silence{}, fmt.Errorf("silence requires at least one matcher")
+	}
+	for k := range s.Matchers {
+		switch k {
+		case "alertname", "process", "severity":
+		default:
+			return Silence{}, fmt.Errorf("unknown silence matcher %q (use alertname, process or severity)", k)
+		}
+	}
+	if s.StartsAt.IsZero() {
+		s.StartsAt = time.Now()
+	}
+	if !s.EndsAt.IsZero() && !s.EndsAt.After(s.StartsAt) {
+		return Silence{}, fmt.Errorf("silence must end after it starts")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextID++
+	s.ID = fmt.Sprintf("silence-%d", e.nextID)
+	e.silences[s.ID] = &s
+	return s, nil
+}
+
+// DeleteSilence removes a silence
+func (e *Engine) DeleteSilence(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.silences[id]; !ok {
+		return fmt.Errorf("silence %s not found", id)
+	}
+	delete(e.silences, id)
+	return nil
+}
+
+// Silences returns all silences that have not expired, sorted by ID
+func (e *Engine) Silences() []Silence {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	silences := make([]Silence, 0, len(e.silences))
+	for id, s := range e.silences {
+		if !s.EndsAt.IsZero() && now.After(s.EndsAt) {
+			delete(e.silences, id)
+			continue
+		}
+		silences = append(silences, *s)
+	}
+	sort.Slice(silences, func(i, j int) bool { return silences[i].ID < silences[j].ID })
+	return silences
+}
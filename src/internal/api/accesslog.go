@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+)
+
+// defaultAccessLogLimit is the number of entries returned without a limit parameter
+const defaultAccessLogLimit = 100
+
+// handleAccessLog handles GET /api/v1/accesslog
+// Query parameters: limit (default 100), source, balancer, process, method
+// (substring), code, failed=true (optional filters)
+func (s *Server) handleAccessLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.accessLog == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "access log is not enabled")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := defaultAccessLogLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			s.writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	entries := s.accessLog.Recent(limit, accesslog.Filter{
+		Source:   q.Get("source"),
+		Balancer: q.Get("balancer"),
+		Process:  q.Get("process"),
+		Method:   q.Get("method"),
+		Code:     q.Get("code"),
+		Failed:   q.Get("failed") == "true",
+	})
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
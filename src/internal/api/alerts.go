@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/alerting"
+)
+
+// handleListAlerts handles GET /api/v1/alerts
+// Query parameters: process, severity (optional filters)
+func (s *Server) handleListAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.alertEngine == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "alerting is not enabled")
+		return
+	}
+
+	processName := r.URL.Query().Get("process")
+	severity := r.URL.Query().Get("severity")
+
+	alerts := make([]alerting.Alert, 0)
+	for _, alert := range s.alertEngine.ActiveAlerts() {
+		if processName != "" && alert.Process != processName {
+			continue
+		}
+		if severity != "" && alert.Severity != severity {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+// handleSilences handles GET and POST /api/v1/alerts/silences
+func (s *Server) handleSilences(w http.ResponseWriter, r *http.Request) {
+	if s.alertEngine == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "alerting is not enabled")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		silences := s.alertEngine.Silences()
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"silences": silences,
+			"count":    len(silences),
+		})
+
+	case http.MethodPost:
+		var silence alerting.Silence
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		created, err := s.alertEngine.AddSilence(silence)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeJSON(w, http.StatusCreated, created)
+
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleDeleteSilence handles DELETE /api/v1/alerts/silences/{id}
+func (s *Server) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.alertEngine == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "alerting is not enabled")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/alerts/silences/")
+	if err := s.alertEngine.DeleteSilence(id); err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "deleted",
+		"id":     id,
+	})
+}
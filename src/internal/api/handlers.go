@@ -5,8 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/alerting"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/loadbalancer"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/monitor"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/storage"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/update"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
 )
@@ -16,6 +22,19 @@ type Server struct {
 	processManager *process.Manager
 	updateManager  *update.Manager
 	mux            *http.ServeMux
+
+	// Metrics history and background sampler (optional)
+	metricsStore *storage.TimeSeriesStore
+	sampler      *monitor.Sampler
+
+	// Alerting (optional)
+	alertEngine *alerting.Engine
+
+	// Load balancers (optional)
+	lbManager *loadbalancer.Manager
+
+	// Access log of proxied calls (optional)
+	accessLog *accesslog.Logger
 }
 
 // NewServer creates a new API server
@@ -30,6 +49,31 @@ func NewServer(processMgr *process.Manager, updateMgr *update.Manager) *Server {
 	return s
 }
 
+// SetMetricsStore sets the metrics history store used by the metrics query endpoint
+func (s *Server) SetMetricsStore(store *storage.TimeSeriesStore) {
+	s.metricsStore = store
+}
+
+// SetSampler sets the background sampler used by the metrics stream endpoint
+func (s *Server) SetSampler(sampler *monitor.Sampler) {
+	s.sampler = sampler
+}
+
+// SetAlertEngine sets the alert engine used by the alert endpoints
+func (s *Server) SetAlertEngine(engine *alerting.Engine) {
+	s.alertEngine = engine
+}
+
+// SetLoadBalancerManager sets the load balancer manager used by the load balancer endpoints
+func (s *Server) SetLoadBalancerManager(mgr *loadbalancer.Manager) {
+	s.lbManager = mgr
+}
+
+// SetAccessLogger sets the access logger queried by the access log endpoint
+func (s *Server) SetAccessLogger(l *accesslog.Logger) {
+	s.accessLog = l
+}
+
 // ServeHTTP implements http.Handler interface
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.mux.ServeHTTP(w, r)
@@ -41,6 +85,22 @@ func (s *Server) registerRoutes() {
 	s.mux.HandleFunc("/api/v1/processes", s.handleListProcesses)
 	s.mux.HandleFunc("/api/v1/processes/", s.handleProcessRoute)
 
+	// Metrics history
+	s.mux.HandleFunc("/api/v1/metrics/query", s.handleMetricsQuery)
+	s.mux.HandleFunc("/api/v1/metrics/stream", s.handleMetricsStream)
+
+	// Alerting
+	s.mux.HandleFunc("/api/v1/alerts", s.handleListAlerts)
+	s.mux.HandleFunc("/api/v1/alerts/silences", s.handleSilences)
+	s.mux.HandleFunc("/api/v1/alerts/silences/", s.handleDeleteSilence)
+
+	// Load balancers
+	s.mux.HandleFunc("/api/v1/loadbalancers", s.handleListLoadBalancers)
+	s.mux.HandleFunc("/api/v1/loadbalancers/", s.handleLoadBalancerRoute)
+
+	// Access log
+	s.mux.HandleFunc("/api/v1/accesslog", s.handleAccessLog)
+
 	// Server status
 	s.mux.HandleFunc("/api/v1/status", s.handleServerStatus)
 
@@ -102,6 +162,8 @@ func (s *Server) handleProcessRoute(w http.ResponseWriter, r *http.Request) {
 		s.handleProcessVersion(w, r, processName)
 	case "rollback":
 		s.handleProcessRollback(w, r, processName)
+	case "switchback":
+		s.handleProcessSwitchBack(w, r, processName)
 	default:
 		s.writeError(w, http.StatusNotFound, "action not found")
 	}
@@ -228,21 +290,41 @@ func (s *Server) handleProcessVersion(w http.ResponseWriter, r *http.Request, pr
 		return
 	}
 
-	// Check for update status
-	if status, exists := s.updateManager.GetUpdateStatus(processName); exists {
-		response := map[string]interface{}{
-			"process":       processName,
-			"update_status": status,
-		}
-		s.writeJSON(w, http.StatusOK, response)
+	instances, err := s.processManager.GetProcessStatus(processName)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
-	// Return version info
-	// TODO: Implement full version info retrieval
+	// Version of each instance; instances started before and after an
+	// update run different versions until the old ones have stopped
+	instanceVersions := make([]map[string]interface{}, len(instances))
+	for i, inst := range instances {
+		instanceVersions[i] = map[string]interface{}{
+			"id":             inst.ID,
+			"version":        inst.Version,
+			"binary_path":    inst.BinaryPath,
+			"status":         inst.GetStatus(),
+			"uptime_seconds": int64(time.Since(inst.StartTime).Seconds()),
+		}
+	}
+
 	response := map[string]interface{}{
-		"process": processName,
-		"message": "no update in progress",
+		"process":         processName,
+		"current_version": s.updateManager.GetCurrentVersion(processName),
+		"instances":       instanceVersions,
+	}
+
+	// Results of past canary updates
+	if analyses := s.updateManager.CanaryAnalyses(processName); len(analyses) > 0 {
+		response["canary_analyses"] = analyses
+	}
+
+	// Check for update status
+	if status, exists := s.updateManager.GetUpdateStatus(processName); exists {
+		response["update_status"] = status
+	} else {
+		response["message"] = "no update in progress"
 	}
 
 	s.writeJSON(w, http.StatusOK, response)
@@ -278,6 +360,26 @@ func (s *Server) handleProcessRollback(w http.ResponseWriter, r *http.Request, p
 	s.writeJSON(w, http.StatusAccepted, response)
 }
 
+// handleProcessSwitchBack handles POST /api/v1/processes/{name}/switchback
+func (s *Server) handleProcessSwitchBack(w http.ResponseWriter, r *http.Request, processName string) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if err := s.updateManager.SwitchBack(processName); err != nil {
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("failed to switch back: %v", err))
+		return
+	}
+
+	response := map[string]interface{}{
+		"message": "traffic switched back to the previous version",
+		"process": processName,
+	}
+
+	s.writeJSON(w, http.StatusOK, response)
+}
+
 // handleServerStatus handles GET /api/v1/status
 func (s *Server) handleServerStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// handleListLoadBalancers lists all load balancers with their routes and backends
+func (s *Server) handleListLoadBalancers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.lbManager == nil {
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"loadbalancers": []interface{}{},
+			"count":         0,
+		})
+		return
+	}
+
+	statuses := s.lbManager.Statuses()
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"loadbalancers": statuses,
+		"count":         len(statuses),
+	})
+}
+
+// handleLoadBalancerRoute handles routes for specific load balancers
+// Path format: /api/v1/loadbalancers/{name}[/{routes[/{route}/{weights|limits}]|backends[/{id}/{drain|enable}]|limits|mirror|reset}]
+func (s *Server) handleLoadBalancerRoute(w http.ResponseWriter, r *http.Request) {
+	if s.lbManager == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "no load balancers are configured")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/loadbalancers/"), "/"), "/")
+	lb, err := s.lbManager.GetBalancer(parts[0])
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		if r.Method != http.MethodGet {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.writeJSON(w, http.StatusOK, lb.Status())
+
+	case len(parts) == 2 && parts[1] == "reset":
+		if r.Method != http.MethodPost {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		lb.ResetStats()
+		s.writeJSON(w, http.StatusOK, map[string]string{
+			"status": "reset",
+		})
+
+	case len(parts) == 4 && parts[1] == "backends" && (parts[3] == "drain" || parts[3] == "enable"):
+		if r.Method != http.MethodPost {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		backend, err := lb.SetDraining(parts[2], parts[3] == "drain")
+		if err != nil {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		s.writeJSON(w, http.StatusOK, backend)
+
+	case len(parts) == 2 && parts[1] == "routes":
+		if r.Method != http.MethodGet {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		routes := lb.Splits()
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"routes": routes,
+			"count":  len(routes),
+		})
+
+	case len(parts) == 2 && parts[1] == "backends":
+		if r.Method != http.MethodGet {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		backends := lb.Backends()
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"backends": backends,
+			"count":    len(backends),
+		})
+
+	case len(parts) == 2 && parts[1] == "mirror":
+		if r.Method != http.MethodGet {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		reports := lb.MirrorReports()
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"mirrors": reports,
+			"count":   len(reports),
+		})
+
+	case len(parts) == 4 && parts[1] == "routes" && parts[3] == "weights":
+		if r.Method != http.MethodPut {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			Weights map[string]int `json:"weights"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		split, err := lb.SetRouteWeights(parts[2], req.Weights)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeJSON(w, http.StatusOK, split)
+
+	case len(parts) == 2 && parts[1] == "limits":
+		if r.Method != http.MethodGet {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		limits := lb.Limits()
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"routes": limits,
+			"count":  len(limits),
+		})
+
+	case len(parts) == 4 && parts[1] == "routes" && parts[3] == "limits":
+		if r.Method != http.MethodPut {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			Policy *models.RateLimitPolicy `json:"policy"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		limits, err := lb.SetRouteLimits(parts[2], req.Policy)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeJSON(w, http.StatusOK, limits)
+
+	default:
+		s.writeError(w, http.StatusNotFound, "action not found")
+	}
+}
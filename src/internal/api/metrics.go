@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/monitor"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/storage"
+)
+
+// handleMetricsQuery handles GET /api/v1/metrics/query
+// Query parameters: process (required), instance, metric (repeatable),
+// start and end (Unix seconds), step (seconds)
+func (s *Server) handleMetricsQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.metricsStore == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "metrics history is not enabled")
+		return
+	}
+
+	params := r.URL.Query()
+	query := storage.Query{
+		Process:  params.Get("process"),
+		Instance: params.Get("instance"),
+		Metrics:  params["metric"],
+	}
+	if query.Process == "" {
+		s.writeError(w, http.StatusBadRequest, "process is required")
+		return
+	}
+
+	for _, p := range []struct {
+		name   string
+		assign func(int64)
+	}{
+		{"start", func(v int64) { query.Start = time.Unix(v, 0) }},
+		{"end", func(v int64) { query.End = time.Unix(v, 0) }},
+		{"step", func(v int64) { query.Step = time.Duration(v) * time.Second }},
+	} {
+		raw := params.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid "+p.name)
+			return
+		}
+		if v == 0 {
+			continue // unset, like in the gRPC API
+		}
+		p.assign(v)
+	}
+
+	series := s.metricsStore.Query(query)
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"process": query.Process,
+		"series":  series,
+		"count":   len(series),
+	})
+}
+
+// processSnapshot is the SSE payload of one process
+type processSnapshot struct {
+	Process   string           `json:"process"`
+	Instances []monitor.Sample `json:"instances"`
+	Total     monitor.Sample   `json:"total"`
+}
+
+// handleMetricsStream handles GET /api/v1/metrics/stream as Server-Sent Events
+// Query parameters: process (optional, default all), interval (seconds)
+func (s *Server) handleMetricsStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.sampler == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "metrics sampler is not enabled")
+		return
+	}
+
+	processName := r.URL.Query().Get("process")
+	if processName != "" {
+		if _, err := s.processManager.GetProcessStatus(processName); err != nil {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+	}
+
+	var interval time.Duration
+	if raw := r.URL.Query().Get("interval"); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid interval")
+			return
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	// The server write timeout would otherwise cut the stream
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	s.sampler.Watch(r.Context(), interval, func(samples map[string][]monitor.Sample, at time.Time) error {
+		names := make([]string, 0, len(samples))
+		for name := range samples {
+			if processName == "" || name == processName {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		processes := make([]processSnapshot, 0, len(names))
+		for _, name := range names {
+			processes = append(processes, processSnapshot{
+				Process:   name,
+				Instances: samples[name],
+				Total:     monitor.Total(name, samples[name]),
+			})
+		}
+
+		data, err := json.Marshal(map[string]interface{}{
+			"timestamp": at.Unix(),
+			"processes": processes,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: metrics\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+}
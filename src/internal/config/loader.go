@@ -51,6 +51,116 @@ func setDefaults(cfg *models.Config) {
 		cfg.Server.WriteTimeout = 30 * time.Second
 	}
 
+	// Metrics defaults
+	if cfg.Metrics.SampleInterval == 0 {
+		cfg.Metrics.SampleInterval = 10 * time.Second
+	}
+
+	// Alerting defaults
+	if cfg.Alerting != nil {
+		if cfg.Alerting.RepeatInterval == 0 {
+			cfg.Alerting.RepeatInterval = 4 * time.Hour
+		}
+		for i := range cfg.Alerting.Rules {
+			if cfg.Alerting.Rules[i].Severity == "" {
+				cfg.Alerting.Rules[i].Severity = "warning"
+			}
+		}
+		for i := range cfg.Alerting.Notifiers {
+			if smtp := cfg.Alerting.Notifiers[i].SMTP; smtp != nil && smtp.Port == 0 {
+				smtp.Port = 587
+			}
+		}
+	}
+
+	// Access log defaults
+	if al := cfg.AccessLog; al != nil {
+		if al.Path == "" {
+			al.Path = "logs/access.log"
+		}
+		if al.MaxSizeMB == 0 {
+			al.MaxSizeMB = 100
+		}
+		if al.MaxBackups == 0 {
+			al.MaxBackups = 5
+		}
+		if al.SampleRate == 0 {
+			al.SampleRate = 1
+		}
+		if al.Recent == 0 {
+			al.Recent = 1000
+		}
+	}
+
+	// Load balancer defaults
+	for i := range cfg.LoadBalancers {
+		if od := cfg.LoadBalancers[i].OutlierDetection; od != nil {
+			if od.ConsecutiveErrors == 0 && od.ErrorRate == 0 {
+				od.ConsecutiveErrors = 5
+			}
+			if od.Window == 0 {
+				od.Window = 30 * time.Second
+			}
+			if od.MinRequests == 0 {
+				od.MinRequests = 20
+			}
+			if od.BaseEjectionTime == 0 {
+				od.BaseEjectionTime = 30 * time.Second
+			}
+			if od.MaxEjectionTime == 0 {
+				od.MaxEjectionTime = 5 * time.Minute
+			}
+			if od.MaxEjectionPercent == 0 {
+				od.MaxEjectionPercent = 50
+			}
+		}
+		if web := cfg.LoadBalancers[i].Web; web != nil && web.MaxMessageBytes == 0 {
+			web.MaxMessageBytes = 4 * 1024 * 1024
+		}
+		for j := range cfg.LoadBalancers[i].Routes {
+			route := &cfg.LoadBalancers[i].Routes[j]
+			if r := route.Retry; r != nil {
+				if r.MaxAttempts == 0 {
+					r.MaxAttempts = 3
+				}
+				if r.InitialBackoff == 0 {
+					r.InitialBackoff = 50 * time.Millisecond
+				}
+				if r.MaxBackoff == 0 {
+					r.MaxBackoff = time.Second
+				}
+				if len(r.RetryableCodes) == 0 {
+					r.RetryableCodes = []string{"UNAVAILABLE"}
+				}
+				if r.MaxBufferBytes == 0 {
+					r.MaxBufferBytes = 64 * 1024
+				}
+			}
+			if h := route.Hedge; h != nil {
+				if h.Delay == 0 {
+					h.Delay = 100 * time.Millisecond
+				}
+				if h.MaxAttempts == 0 {
+					h.MaxAttempts = 2
+				}
+				if h.MaxBufferBytes == 0 {
+					h.MaxBufferBytes = 64 * 1024
+				}
+			}
+			if m := route.Mirror; m != nil {
+				if m.Percent == 0 {
+					m.Percent = 100
+				}
+				if m.Timeout == 0 {
+					m.Timeout = 10 * time.Second
+				}
+				if m.MaxBufferBytes == 0 {
+					m.MaxBufferBytes = 64 * 1024
+				}
+			}
+		}
+	}
+
 	// Secrets defaults
 	if cfg.Secrets.Mode == "" {
 		cfg.Secrets.Mode = "standalone"
@@ -88,6 +198,52 @@ func setDefaults(cfg *models.Config) {
 		if p.HealthCheck.Retries == 0 {
 			p.HealthCheck.Retries = 3
 		}
+		if p.Update.Strategy == "" {
+			p.Update.Strategy = "rolling"
+		}
+		if p.Update.MaxSurge == 0 && p.Update.MaxUnavailable == 0 {
+			p.Update.MaxSurge = 1
+		}
+		if p.Update.ReadyTimeout == 0 {
+			p.Update.ReadyTimeout = 60 * time.Second
+		}
+		if p.Update.MinReadyTime == 0 {
+			p.Update.MinReadyTime = 5 * time.Second
+		}
+		if p.Update.StopTimeout == 0 {
+			p.Update.StopTimeout = 30 * time.Second
+		}
+		if p.Update.BakeTime == 0 {
+			p.Update.BakeTime = 10 * time.Minute
+		}
+		if c := &p.Update.Canary; p.Update.Strategy == "canary" {
+			if c.Instances == 0 {
+				c.Instances = 1
+			}
+			if len(c.Steps) == 0 {
+				c.Steps = []int{10, 50}
+			}
+			if c.StepDuration == 0 {
+				c.StepDuration = 5 * time.Minute
+			}
+			if c.MinRequests == 0 {
+				c.MinRequests = 20
+			}
+			if c.MaxErrorRateIncrease == 0 {
+				c.MaxErrorRateIncrease = 1
+			}
+			if c.MaxLatencyRatio == 0 {
+				c.MaxLatencyRatio = 1.5
+			}
+		}
+		if p.ScaleToZero != nil {
+			if p.ScaleToZero.IdleTimeout == 0 {
+				p.ScaleToZero.IdleTimeout = 15 * time.Minute
+			}
+			if p.ScaleToZero.MaxWakeWait == 0 {
+				p.ScaleToZero.MaxWakeWait = 30 * time.Second
+			}
+		}
 	}
 }
 
@@ -97,6 +253,10 @@ func validate(cfg *models.Config) error {
 		return fmt.Errorf("at least one process must be configured")
 	}
 
+	if cfg.Metrics.SampleInterval < time.Second {
+		return fmt.Errorf("metrics.sample_interval must be at least 1s")
+	}
+
 	// Validate secrets configuration
 	if cfg.Secrets.Mode == "cloudflare" {
 		if cfg.Secrets.Cloudflare == nil {
@@ -140,6 +300,31 @@ func validate(cfg *models.Config) error {
 		if p.MaxInstances < 1 {
 			return fmt.Errorf("process[%d]: max_instances must be at least 1", i)
 		}
+		switch p.Update.Strategy {
+		case "rolling", "blue_green":
+		case "canary":
+			if err := validateCanary(&p.Update.Canary); err != nil {
+				return fmt.Errorf("process[%d]: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("process[%d]: update.strategy must be 'rolling', 'blue_green' or 'canary'", i)
+		}
+		if p.Update.BakeTime < 0 {
+			return fmt.Errorf("process[%d]: update.bake_time must not be negative", i)
+		}
+		if p.Update.MaxSurge < 0 || p.Update.MaxUnavailable < 0 {
+			return fmt.Errorf("process[%d]: update.max_surge and max_unavailable must not be negative", i)
+		}
+		if p.Scaling != nil {
+			if p.Scaling.DefaultInstances < 0 || p.Scaling.DefaultInstances > p.MaxInstances {
+				return fmt.Errorf("process[%d]: scaling.default_instances must be between 0 and max_instances", i)
+			}
+			for j, sched := range p.Scaling.Schedules {
+				if sched.Start == "" || sched.End == "" {
+					return fmt.Errorf("process[%d]: scaling.schedules[%d]: start and end are required", i, j)
+				}
+			}
+		}
 	}
 
 	// Validate tunnel configuration
@@ -155,5 +340,82 @@ func validate(cfg *models.Config) error {
 		}
 	}
 
+	// Validate alerting configuration
+	if cfg.Alerting != nil {
+		if err := validateAlerting(cfg.Alerting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateAlerting validates alert rules and notifiers
+func validateAlerting(cfg *models.AlertingConfig) error {
+	notifiers := make(map[string]bool)
+	for i, n := range cfg.Notifiers {
+		if n.Name == "" {
+			return fmt.Errorf("alerting.notifiers[%d]: name is required", i)
+		}
+		if notifiers[n.Name] {
+			return fmt.Errorf("alerting.notifiers[%d]: duplicate name %q", i, n.Name)
+		}
+		notifiers[n.Name] = true
+
+		switch n.Type {
+		case "webhook", "slack":
+			if n.URL == "" {
+				return fmt.Errorf("alerting.notifiers[%d]: url is required for %s notifiers", i, n.Type)
+			}
+		case "email":
+			if n.SMTP == nil || n.SMTP.Host == "" || n.SMTP.From == "" || len(n.SMTP.To) == 0 {
+				return fmt.Errorf("alerting.notifiers[%d]: smtp host, from and to are required for email notifiers", i)
+			}
+		default:
+			return fmt.Errorf("alerting.notifiers[%d]: type must be 'webhook', 'slack' or 'email'", i)
+		}
+	}
+
+	rules := make(map[string]bool)
+	for i, r := range cfg.Rules {
+		if r.Name == "" || r.Expr == "" {
+			return fmt.Errorf("alerting.rules[%d]: name and expr are required", i)
+		}
+		if rules[r.Name] {
+			return fmt.Errorf("alerting.rules[%d]: duplicate name %q", i, r.Name)
+		}
+		rules[r.Name] = true
+
+		switch r.Severity {
+		case "info", "warning", "critical":
+		default:
+			return fmt.Errorf("alerting.rules[%d]: severity must be 'info', 'warning' or 'critical'", i)
+		}
+		for _, name := range r.Notifiers {
+			if !notifiers[name] {
+				return fmt.Errorf("alerting.rules[%d]: unknown notifier %q", i, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCanary validates the traffic steps and thresholds of a canary update
+func validateCanary(c *models.CanaryConfig) error {
+	if c.Instances < 1 {
+		return fmt.Errorf("update.canary.instances must be at least 1")
+	}
+	for _, weight := range c.Steps {
+		if weight < 1 || weight > 100 {
+			return fmt.Errorf("update.canary.steps must be percentages between 1 and 100")
+		}
+	}
+	if c.StepDuration < 0 || c.MinRequests < 0 || c.MaxCrashes < 0 {
+		return fmt.Errorf("update.canary.step_duration, min_requests and max_crashes must not be negative")
+	}
+	if c.MaxErrorRateIncrease < 0 || c.MaxLatencyRatio < 1 {
+		return fmt.Errorf("update.canary.max_error_rate_increase must not be negative and max_latency_ratio must be at least 1")
+	}
 	return nil
 }
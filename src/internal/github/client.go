@@ -62,7 +62,7 @@ func (c *Client) GetLatestRelease(repo string) (*models.Version, error) {
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch release: %w", err)
 	}
@@ -78,7 +78,7 @@ func (c *Client) GetLatestRelease(repo string) (*models.Version, error) {
 		}
 		req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-		resp, err = c.httpClient.Do(req)
+		resp, err = c.do(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch release without auth: %w", err)
 		}
@@ -123,7 +123,7 @@ func (c *Client) GetRelease(repo, tag string) (*models.Version, error) {
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch release: %w", err)
 	}
@@ -139,7 +139,7 @@ func (c *Client) GetRelease(repo, tag string) (*models.Version, error) {
 		}
 		req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-		resp, err = c.httpClient.Do(req)
+		resp, err = c.do(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch release without auth: %w", err)
 		}
@@ -179,7 +179,7 @@ func (c *Client) ListReleases(repo string, limit int) ([]models.Version, error)
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch releases: %w", err)
 	}
@@ -195,7 +195,7 @@ func (c *Client) ListReleases(repo string, limit int) ([]models.Version, error)
 		}
 		req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-		resp, err = c.httpClient.Do(req)
+		resp, err = c.do(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch releases without auth: %w", err)
 		}
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+)
+
+var (
+	apiRequests = metrics.NewCounterVec("gowinproc_github_api_requests_total",
+		"GitHub API requests by HTTP status (\"error\" for transport failures)", "status")
+	rateLimitRemaining = metrics.NewGaugeVec("gowinproc_github_rate_limit_remaining",
+		"Remaining GitHub API requests in the current rate-limit window")
+	rateLimitReset = metrics.NewGaugeVec("gowinproc_github_rate_limit_reset_timestamp_seconds",
+		"Unix time at which the GitHub API rate-limit window resets")
+)
+
+// ObserveResponse records the status and rate-limit headers of a GitHub API response
+func ObserveResponse(resp *http.Response, err error) {
+	if err != nil {
+		apiRequests.Inc("error")
+		return
+	}
+
+	apiRequests.Inc(strconv.Itoa(resp.StatusCode))
+
+	if v, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Remaining"), 64); err == nil {
+		rateLimitRemaining.Set(v)
+	}
+	if v, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Reset"), 64); err == nil {
+		rateLimitReset.Set(v)
+	}
+}
+
+// ErrorCount returns the number of failed GitHub API requests since startup
+// (transport errors and HTTP 4xx/5xx responses)
+func ErrorCount() float64 {
+	return apiRequests.Sum(func(labels []string) bool {
+		if labels[0] == "error" {
+			return true
+		}
+		code, err := strconv.Atoi(labels[0])
+		return err == nil && code >= 400
+	})
+}
+
+// do executes a GitHub API request and records its metrics
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	ObserveResponse(resp, err)
+	return resp, err
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/proto"
+)
+
+// ListAlerts returns pending and firing alerts
+func (s *Server) ListAlerts(ctx context.Context, req *pb.ListAlertsRequest) (*pb.ListAlertsResponse, error) {
+	if s.alertEngine == nil {
+		return nil, fmt.Errorf("alerting is not enabled")
+	}
+
+	resp := &pb.ListAlertsResponse{}
+	for _, alert := range s.alertEngine.ActiveAlerts() {
+		if req.ProcessName != "" && alert.Process != req.ProcessName {
+			continue
+		}
+		if req.Severity != "" && alert.Severity != req.Severity {
+			continue
+		}
+
+		pbAlert := &pb.Alert{
+			Id:          alert.ID,
+			Name:        alert.Name,
+			Severity:    alert.Severity,
+			ProcessName: alert.Process,
+			Summary:     alert.Summary,
+			Expr:        alert.Expr,
+			Value:       alert.Value,
+			State:       alert.State,
+			ActiveSince: alert.ActiveSince.Unix(),
+			Silenced:    alert.Silenced,
+		}
+		if !alert.FiredAt.IsZero() {
+			pbAlert.FiredAt = alert.FiredAt.Unix()
+		}
+		resp.Alerts = append(resp.Alerts, pbAlert)
+	}
+
+	return resp, nil
+}
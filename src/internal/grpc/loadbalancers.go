@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/loadbalancer"
+	pb "github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/proto"
+)
+
+// ListLoadBalancers returns load balancers with their routes and backends
+func (s *Server) ListLoadBalancers(ctx context.Context, req *pb.ListLoadBalancersRequest) (*pb.ListLoadBalancersResponse, error) {
+	if s.lbManager == nil {
+		return nil, fmt.Errorf("no load balancers are configured")
+	}
+
+	resp := &pb.ListLoadBalancersResponse{}
+	for _, st := range s.lbManager.Statuses() {
+		if req.Name != "" && st.Name != req.Name {
+			continue
+		}
+
+		pbLB := &pb.LoadBalancer{
+			Name:       st.Name,
+			ListenPort: int32(st.ListenPort),
+			Protocol:   st.Protocol,
+			Tls:        st.TLS,
+		}
+		for _, route := range st.Routes {
+			pbRoute := &pb.LoadBalancerRoute{
+				Name:     route.Route,
+				Strategy: route.Strategy,
+				Targets:  route.Targets,
+				Requests: route.Requests,
+				Default:  route.Default,
+			}
+			if len(route.Weights) > 0 {
+				pbRoute.Weights = make(map[string]int32, len(route.Weights))
+				for target, w := range route.Weights {
+					pbRoute.Weights[target] = int32(w)
+				}
+			}
+			pbLB.Routes = append(pbLB.Routes, pbRoute)
+		}
+		for _, b := range st.Backends {
+			pbLB.Backends = append(pbLB.Backends, backendToProto(b))
+		}
+		resp.LoadBalancers = append(resp.LoadBalancers, pbLB)
+	}
+
+	return resp, nil
+}
+
+// SetBackendDraining drains a backend or puts it back into rotation
+func (s *Server) SetBackendDraining(ctx context.Context, req *pb.SetBackendDrainingRequest) (*pb.LoadBalancerBackend, error) {
+	if s.lbManager == nil {
+		return nil, fmt.Errorf("no load balancers are configured")
+	}
+
+	lb, err := s.lbManager.GetBalancer(req.LoadBalancer)
+	if err != nil {
+		return nil, err
+	}
+	st, err := lb.SetDraining(req.InstanceId, req.Draining)
+	if err != nil {
+		return nil, err
+	}
+	return backendToProto(st), nil
+}
+
+// ResetLoadBalancerStats resets the request, error and latency counters of a load balancer
+func (s *Server) ResetLoadBalancerStats(ctx context.Context, req *pb.ResetLoadBalancerStatsRequest) (*pb.Empty, error) {
+	if s.lbManager == nil {
+		return nil, fmt.Errorf("no load balancers are configured")
+	}
+
+	lb, err := s.lbManager.GetBalancer(req.LoadBalancer)
+	if err != nil {
+		return nil, err
+	}
+	lb.ResetStats()
+	return &pb.Empty{}, nil
+}
+
+func backendToProto(b loadbalancer.BackendStatus) *pb.LoadBalancerBackend {
+	pbBackend := &pb.LoadBalancerBackend{
+		ProcessName:   b.Process,
+		InstanceId:    b.InstanceID,
+		Address:       b.Address,
+		State:         b.State,
+		Connectivity:  b.Connectivity,
+		InFlight:      b.InFlight,
+		Requests:      b.Requests,
+		Errors:        b.Errors,
+		LatencyAvgMs:  b.LatencyAvgMs,
+		LatencyEwmaMs: b.LatencyEWMAMs,
+		Ejections:     int32(b.Ejections),
+	}
+	if b.EjectedUntil != nil {
+		pbBackend.EjectedUntil = b.EjectedUntil.Unix()
+	}
+	return pbBackend
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/monitor"
+	pb "github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/proto"
+)
+
+// StreamMetrics streams metrics of one or all processes from the shared
+// background sampler at the requested interval
+func (s *Server) StreamMetrics(req *pb.StreamMetricsRequest, stream pb.ProcessManager_StreamMetricsServer) error {
+	if s.sampler == nil {
+		return fmt.Errorf("metrics sampler is not enabled")
+	}
+	if req.ProcessName != "" {
+		if _, err := s.processManager.GetProcessStatus(req.ProcessName); err != nil {
+			return err
+		}
+	}
+
+	interval := time.Duration(req.IntervalSeconds) * time.Second
+
+	return s.sampler.Watch(stream.Context(), interval, func(samples map[string][]monitor.Sample, at time.Time) error {
+		return stream.Send(buildSnapshot(samples, at, req.ProcessName))
+	})
+}
+
+// buildSnapshot converts sampler output to a metrics snapshot, optionally
+// restricted to one process
+func buildSnapshot(samples map[string][]monitor.Sample, at time.Time, processName string) *pb.MetricsSnapshot {
+	names := make([]string, 0, len(samples))
+	for name := range samples {
+		if processName == "" || name == processName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	snapshot := &pb.MetricsSnapshot{Timestamp: at.Unix()}
+	for _, name := range names {
+		instances := make([]*pb.ProcessMetrics, 0, len(samples[name]))
+		for _, sample := range samples[name] {
+			instances = append(instances, sampleToMetrics(sample))
+		}
+		snapshot.Processes = append(snapshot.Processes, &pb.Metrics{
+			ProcessName: name,
+			Instances:   instances,
+			Aggregated:  aggregateMetrics(instances),
+		})
+	}
+	return snapshot
+}
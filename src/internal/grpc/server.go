@@ -7,10 +7,14 @@ import (
 	"sync"
 	"time"
 
-	gopsutilProcess "github.com/shirou/gopsutil/v4/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/alerting"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/loadbalancer"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/monitor"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
 	pb "github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/proto"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/storage"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/update"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
 )
 
 // Server implements the ProcessManager gRPC service
@@ -23,6 +27,16 @@ type Server struct {
 	// Update watchers for streaming
 	watchersMu sync.RWMutex
 	watchers   map[string][]chan *pb.UpdateStatus
+
+	// Metrics history and background sampler (optional)
+	metricsStore *storage.TimeSeriesStore
+	sampler      *monitor.Sampler
+
+	// Alerting (optional)
+	alertEngine *alerting.Engine
+
+	// Load balancers (optional)
+	lbManager *loadbalancer.Manager
 }
 
 // NewServer creates a new gRPC server
@@ -35,6 +49,26 @@ func NewServer(processMgr *process.Manager, updateMgr *update.Manager, repos []s
 	}
 }
 
+// SetMetricsStore sets the metrics history store used by QueryMetrics
+func (s *Server) SetMetricsStore(store *storage.TimeSeriesStore) {
+	s.metricsStore = store
+}
+
+// SetSampler sets the background sampler whose latest samples back GetMetrics
+func (s *Server) SetSampler(sampler *monitor.Sampler) {
+	s.sampler = sampler
+}
+
+// SetAlertEngine sets the alert engine used by ListAlerts
+func (s *Server) SetAlertEngine(engine *alerting.Engine) {
+	s.alertEngine = engine
+}
+
+// SetLoadBalancerManager sets the load balancer manager used by the load balancer RPCs
+func (s *Server) SetLoadBalancerManager(mgr *loadbalancer.Manager) {
+	s.lbManager = mgr
+}
+
 // ListProcesses returns a list of all managed processes
 func (s *Server) ListProcesses(ctx context.Context, req *pb.ListProcessesRequest) (*pb.ListProcessesResponse, error) {
 	processes := s.processManager.ListProcesses()
@@ -45,30 +79,50 @@ func (s *Server) ListProcesses(ctx context.Context, req *pb.ListProcessesRequest
 	}, nil
 }
 
-// collectMetrics collects CPU and memory metrics for a process
-func collectMetrics(pid int) *pb.ProcessMetrics {
-	proc, err := gopsutilProcess.NewProcess(int32(pid))
+// instanceMetrics returns metrics of an instance, preferring the latest
+// background sample (which includes I/O rates) over a one-off collection
+func (s *Server) instanceMetrics(processName string, inst *models.ProcessInstance) *pb.ProcessMetrics {
+	if s.sampler != nil {
+		for _, sample := range s.sampler.Latest(processName) {
+			if sample.InstanceID == inst.ID && sample.PID == inst.PID {
+				return sampleToMetrics(sample)
+			}
+		}
+	}
+
+	sample, err := monitor.CollectSample(processName, inst)
 	if err != nil {
 		return nil
 	}
+	return sampleToMetrics(sample)
+}
 
-	cpuPercent, _ := proc.CPUPercent()
-	memInfo, _ := proc.MemoryInfo()
-
-	var memoryUsage uint64
-	if memInfo != nil {
-		memoryUsage = memInfo.RSS
+// sampleToMetrics converts a resource sample to protobuf metrics
+func sampleToMetrics(sample monitor.Sample) *pb.ProcessMetrics {
+	tcp := make(map[string]int32, len(sample.TCPConnections))
+	for state, n := range sample.TCPConnections {
+		tcp[state] = int32(n)
 	}
 
 	return &pb.ProcessMetrics{
-		InstanceId:  "", // Will be set by caller
-		CpuUsage:    cpuPercent,
-		MemoryUsage: memoryUsage,
-		DiskRead:    0, // TODO: Implement disk metrics
-		DiskWrite:   0,
-		NetworkRecv: 0, // TODO: Implement network metrics
-		NetworkSent: 0,
-		Uptime:      0, // Will be set by caller
+		InstanceId:       sample.InstanceID,
+		CpuUsage:         sample.CPUPercent,
+		MemoryUsage:      sample.MemoryBytes,
+		DiskRead:         sample.DiskReadBytes,
+		DiskWrite:        sample.DiskWriteBytes,
+		NetworkRecv:      sample.NetRecvBytes,
+		NetworkSent:      sample.NetSentBytes,
+		Uptime:           int64(sample.Timestamp.Sub(sample.StartTime).Seconds()),
+		DiskReadRate:     sample.DiskReadRate,
+		DiskWriteRate:    sample.DiskWriteRate,
+		NetworkRecvRate:  sample.NetRecvRate,
+		NetworkSentRate:  sample.NetSentRate,
+		DiskReadOps:      sample.DiskReadOps,
+		DiskWriteOps:     sample.DiskWriteOps,
+		OpenHandles:      sample.OpenHandles,
+		Threads:          sample.Threads,
+		TcpConnections:   tcp,
+		NetworkSupported: sample.NetSupported,
 	}
 }
 
@@ -93,11 +147,7 @@ func (s *Server) GetProcess(ctx context.Context, req *pb.GetProcessRequest) (*pb
 	pbInstances := make([]*pb.ProcessInstance, len(instances))
 	for i, inst := range instances {
 		// Collect metrics for this instance
-		metrics := collectMetrics(inst.PID)
-		if metrics != nil {
-			metrics.InstanceId = inst.ID
-			metrics.Uptime = int64(time.Since(inst.StartTime).Seconds())
-		}
+		metrics := s.instanceMetrics(req.ProcessName, inst)
 
 		pbInstances[i] = &pb.ProcessInstance{
 			Id:          inst.ID,
@@ -162,174 +212,45 @@ func (s *Server) StopProcess(ctx context.Context, req *pb.StopProcessRequest) (*
 	return &pb.Empty{}, nil
 }
 
-// RestartProcess restarts a process instance or all instances using hot restart
-// (start new instances first, then stop old ones to minimize downtime)
+// RestartProcess restarts a process instance or all instances using a rolling
+// hot restart (new instances are started and ready before old ones are stopped)
 func (s *Server) RestartProcess(ctx context.Context, req *pb.RestartProcessRequest) (*pb.ProcessInfo, error) {
 	if req.ProcessName == "" {
 		return nil, fmt.Errorf("process_name is required")
 	}
 
-	// Get current instances before restart
-	oldInstances, err := s.processManager.GetProcessStatus(req.ProcessName)
+	instances, err := s.processManager.GetProcessStatus(req.ProcessName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current instances: %w", err)
 	}
 
-	if len(oldInstances) == 0 {
-		return nil, fmt.Errorf("no instances found for process %s", req.ProcessName)
-	}
-
-	log.Printf("[HOT RESTART] Starting hot restart for %s: %d old instances", req.ProcessName, len(oldInstances))
-	for _, inst := range oldInstances {
-		log.Printf("[HOT RESTART]   Old instance: %s (PID: %d)", inst.ID, inst.PID)
-	}
-
-	// HOT RESTART: Start new instance(s) first
-	numInstances := len(oldInstances)
-	if req.InstanceId != "" {
-		numInstances = 1 // Only restart one instance
-	}
-
-	newInstanceIDs := make([]string, 0, numInstances)
-	for i := 0; i < numInstances; i++ {
-		instance, err := s.processManager.StartProcess(req.ProcessName)
-		if err != nil {
-			// Rollback: stop newly started instances
-			for _, id := range newInstanceIDs {
-				s.processManager.StopProcess(req.ProcessName, id)
-			}
-			return nil, fmt.Errorf("failed to start new instance %d: %w", i+1, err)
-		}
-		newInstanceIDs = append(newInstanceIDs, instance.ID)
-		log.Printf("[HOT RESTART] Started new instance %d/%d: %s (PID: %d)", i+1, numInstances, instance.ID, instance.PID)
-	}
-
-	// Wait for new instances to be ready and verify they are actually running
-	maxRetries := 10
-	retryDelay := 500 * time.Millisecond
-	minWaitTime := 1 * time.Second // Minimum wait time to ensure UI can see the transition state
-
-	log.Printf("[HOT RESTART] Starting health check for new instances (max %d retries, min wait time: %v)", maxRetries, minWaitTime)
-	healthCheckStart := time.Now()
-
-	for retry := 0; retry < maxRetries; retry++ {
-		time.Sleep(retryDelay)
-
-		// Check if all new instances are running and healthy
-		allHealthy := true
-		newInstances, err := s.processManager.GetProcessStatus(req.ProcessName)
-		if err != nil {
-			log.Printf("[HOT RESTART] Retry %d/%d: Failed to get process status: %v", retry+1, maxRetries, err)
+	// Replace every running instance, or only the requested one
+	var oldInstances []*models.ProcessInstance
+	for _, inst := range instances {
+		if req.InstanceId != "" && inst.ID != req.InstanceId {
 			continue
 		}
-
-		log.Printf("[HOT RESTART] Retry %d/%d: Total instances found: %d (old + new)", retry+1, maxRetries, len(newInstances))
-		for _, inst := range newInstances {
-			log.Printf("[HOT RESTART]   Instance %s: PID=%d, Status=%s", inst.ID, inst.PID, inst.GetStatus())
-		}
-
-		// Verify each new instance is present and running
-		for _, newID := range newInstanceIDs {
-			found := false
-			for _, inst := range newInstances {
-				if inst.ID == newID {
-					found = true
-					// Check if process is actually running (PID > 0)
-					if inst.PID <= 0 {
-						log.Printf("[HOT RESTART] Retry %d/%d: Instance %s has invalid PID: %d", retry+1, maxRetries, newID, inst.PID)
-						allHealthy = false
-						break
-					}
-					// Process must be in running state
-					if inst.GetStatus() != "running" {
-						log.Printf("[HOT RESTART] Retry %d/%d: Instance %s not running: %s", retry+1, maxRetries, newID, inst.GetStatus())
-						allHealthy = false
-						break
-					}
-				}
-			}
-			if !found {
-				log.Printf("[HOT RESTART] Retry %d/%d: New instance %s not found in status", retry+1, maxRetries, newID)
-				allHealthy = false
-				break
-			}
-		}
-
-		if allHealthy {
-			// Check if minimum wait time has elapsed
-			elapsed := time.Since(healthCheckStart)
-			if elapsed < minWaitTime {
-				remainingWait := minWaitTime - elapsed
-				log.Printf("[HOT RESTART] New instances are healthy after %d retries, but waiting additional %v for UI visibility (total wait: %v)", retry+1, remainingWait, minWaitTime)
-				time.Sleep(remainingWait)
-			} else {
-				log.Printf("[HOT RESTART] All new instances are healthy after %d retries (elapsed: %v)", retry+1, elapsed)
-			}
-			break
-		}
-
-		// If this is the last retry and still not healthy, rollback
-		if retry == maxRetries-1 {
-			log.Printf("[HOT RESTART] Health check failed after %d retries, rolling back", maxRetries)
-			for _, id := range newInstanceIDs {
-				s.processManager.StopProcess(req.ProcessName, id)
-			}
-			return nil, fmt.Errorf("new instances failed to become healthy after %d retries", maxRetries)
+		if inst.GetStatus() == models.StatusRunning {
+			oldInstances = append(oldInstances, inst)
 		}
 	}
-
-	// Get final status of all instances
-	newInstances, err := s.processManager.GetProcessStatus(req.ProcessName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify new instances: %w", err)
-	}
-
-	newInstanceMap := make(map[string]bool)
-	for _, inst := range newInstances {
-		newInstanceMap[inst.ID] = true
-	}
-
-	// Stop old instance(s) gracefully
-	log.Printf("[HOT RESTART] Stopping old instances...")
-	for _, oldInst := range oldInstances {
-		// Skip if this is a newly started instance
-		if newInstanceMap[oldInst.ID] && contains(newInstanceIDs, oldInst.ID) {
-			log.Printf("[HOT RESTART] Skipping new instance %s (not an old instance)", oldInst.ID)
-			continue
-		}
-
-		// If specific instance requested, only stop that one
-		if req.InstanceId != "" && oldInst.ID != req.InstanceId {
-			log.Printf("[HOT RESTART] Skipping instance %s (not the requested instance)", oldInst.ID)
-			continue
-		}
-
-		// Stop old instance with graceful timeout (reduced to 3s for faster response)
-		log.Printf("[HOT RESTART] Stopping old instance %s (PID: %d)", oldInst.ID, oldInst.PID)
-		if err := s.processManager.StopProcessGracefully(req.ProcessName, oldInst.ID, 3*time.Second); err != nil {
-			// Log error but continue - new instances are already running
-			log.Printf("[HOT RESTART] Warning: failed to stop old instance %s: %v", oldInst.ID, err)
-		} else {
-			log.Printf("[HOT RESTART] Successfully stopped old instance %s", oldInst.ID)
+	if len(oldInstances) == 0 {
+		if req.InstanceId != "" {
+			return nil, fmt.Errorf("instance %s of process %s is not running", req.InstanceId, req.ProcessName)
 		}
+		return nil, fmt.Errorf("no instances found for process %s", req.ProcessName)
 	}
 
+	log.Printf("[HOT RESTART] Starting hot restart for %s: %d old instances", req.ProcessName, len(oldInstances))
+	if _, err := s.processManager.RollingReplace(req.ProcessName, process.Rollout{Old: oldInstances}); err != nil {
+		return nil, fmt.Errorf("hot restart of %s stopped: %w", req.ProcessName, err)
+	}
 	log.Printf("[HOT RESTART] Hot restart completed for %s", req.ProcessName)
 
 	// Return updated process info
 	return s.GetProcess(ctx, &pb.GetProcessRequest{ProcessName: req.ProcessName})
 }
 
-// Helper function to check if slice contains string
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
-
 // GetMetrics returns metrics for a process
 func (s *Server) GetMetrics(ctx context.Context, req *pb.GetMetricsRequest) (*pb.Metrics, error) {
 	if req.ProcessName == "" {
@@ -343,86 +264,99 @@ func (s *Server) GetMetrics(ctx context.Context, req *pb.GetMetricsRequest) (*pb
 
 	// Collect metrics for each instance
 	metricsInstances := make([]*pb.ProcessMetrics, 0)
-	var totalCPU float64
-	var totalMemory uint64
 
 	for _, inst := range instances {
 		if req.InstanceId != "" && inst.ID != req.InstanceId {
 			continue
 		}
 
-		metrics := collectMetrics(inst.PID)
-		if metrics != nil {
-			metrics.InstanceId = inst.ID
-			metrics.Uptime = int64(time.Since(inst.StartTime).Seconds())
-
+		if metrics := s.instanceMetrics(req.ProcessName, inst); metrics != nil {
 			metricsInstances = append(metricsInstances, metrics)
-			totalCPU += metrics.CpuUsage
-			totalMemory += metrics.MemoryUsage
 		}
 	}
 
-	// Calculate aggregated metrics
-	aggregated := &pb.AggregatedMetrics{
-		TotalCpuUsage:     totalCPU,
-		TotalMemoryUsage:  totalMemory,
-		TotalDiskRead:     0,
-		TotalDiskWrite:    0,
-		TotalNetworkRecv:  0,
-		TotalNetworkSent:  0,
-		InstanceCount:     int32(len(metricsInstances)),
-	}
-
 	return &pb.Metrics{
 		ProcessName: req.ProcessName,
 		Instances:   metricsInstances,
-		Aggregated:  aggregated,
+		Aggregated:  aggregateMetrics(metricsInstances),
 	}, nil
 }
 
+// aggregateMetrics sums instance metrics into process totals
+func aggregateMetrics(instances []*pb.ProcessMetrics) *pb.AggregatedMetrics {
+	aggregated := &pb.AggregatedMetrics{InstanceCount: int32(len(instances))}
+	for _, metrics := range instances {
+		aggregated.TotalCpuUsage += metrics.CpuUsage
+		aggregated.TotalMemoryUsage += metrics.MemoryUsage
+		aggregated.TotalDiskRead += metrics.DiskRead
+		aggregated.TotalDiskWrite += metrics.DiskWrite
+		aggregated.TotalNetworkRecv += metrics.NetworkRecv
+		aggregated.TotalNetworkSent += metrics.NetworkSent
+		aggregated.TotalDiskReadRate += metrics.DiskReadRate
+		aggregated.TotalDiskWriteRate += metrics.DiskWriteRate
+		aggregated.TotalNetworkRecvRate += metrics.NetworkRecvRate
+		aggregated.TotalNetworkSentRate += metrics.NetworkSentRate
+		aggregated.TotalOpenHandles += metrics.OpenHandles
+		aggregated.TotalThreads += metrics.Threads
+		for _, n := range metrics.TcpConnections {
+			aggregated.TotalTcpConnections += n
+		}
+	}
+	return aggregated
+}
+
 // ScaleProcess scales a process to a target number of instances
 func (s *Server) ScaleProcess(ctx context.Context, req *pb.ScaleProcessRequest) (*pb.ProcessInfo, error) {
 	if req.ProcessName == "" {
 		return nil, fmt.Errorf("process_name is required")
 	}
 
-	instances, err := s.processManager.GetProcessStatus(req.ProcessName)
-	if err != nil {
+	// Manual scaling overrides any scaling schedule until its next boundary
+	if err := s.processManager.ScaleProcess(req.ProcessName, int(req.TargetInstances)); err != nil {
 		return nil, err
 	}
 
-	currentCount := len(instances)
-	targetCount := int(req.TargetInstances)
+	// Return updated process info
+	return s.GetProcess(ctx, &pb.GetProcessRequest{ProcessName: req.ProcessName})
+}
 
-	if targetCount > currentCount {
-		// Scale up - start more instances
-		for i := 0; i < targetCount-currentCount; i++ {
-			if _, err := s.processManager.StartProcess(req.ProcessName); err != nil {
-				return nil, fmt.Errorf("failed to scale up: %w", err)
-			}
-		}
-	} else if targetCount < currentCount {
-		// Scale down - stop instances gracefully
-		instancesToStop := currentCount - targetCount
-		for i := 0; i < instancesToStop; i++ {
-			// Get fresh instance list each time
-			currentInstances, err := s.processManager.GetProcessStatus(req.ProcessName)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get instances during scale down: %w", err)
-			}
+// QueryMetrics returns the recorded metrics history of a process
+func (s *Server) QueryMetrics(ctx context.Context, req *pb.QueryMetricsRequest) (*pb.QueryMetricsResponse, error) {
+	if req.ProcessName == "" {
+		return nil, fmt.Errorf("process_name is required")
+	}
+	if s.metricsStore == nil {
+		return nil, fmt.Errorf("metrics history is not enabled")
+	}
 
-			if len(currentInstances) > targetCount {
-				// Stop the last instance gracefully
-				lastIdx := len(currentInstances) - 1
-				if err := s.processManager.StopProcessGracefully(req.ProcessName, currentInstances[lastIdx].ID, 10*time.Second); err != nil {
-					return nil, fmt.Errorf("failed to scale down instance %s: %w", currentInstances[lastIdx].ID, err)
-				}
-			}
+	query := storage.Query{
+		Process:  req.ProcessName,
+		Instance: req.InstanceId,
+		Metrics:  req.Metrics,
+		Step:     time.Duration(req.Step) * time.Second,
+	}
+	if req.StartTime > 0 {
+		query.Start = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		query.End = time.Unix(req.EndTime, 0)
+	}
+
+	resp := &pb.QueryMetricsResponse{}
+	for _, series := range s.metricsStore.Query(query) {
+		pbSeries := &pb.MetricSeries{
+			ProcessName: series.Process,
+			InstanceId:  series.Instance,
+			Metric:      series.Metric,
+			Points:      make([]*pb.MetricPoint, 0, len(series.Points)),
 		}
+		for _, p := range series.Points {
+			pbSeries.Points = append(pbSeries.Points, &pb.MetricPoint{Timestamp: p.Timestamp, Value: p.Value})
+		}
+		resp.Series = append(resp.Series, pbSeries)
 	}
 
-	// Return updated process info
-	return s.GetProcess(ctx, &pb.GetProcessRequest{ProcessName: req.ProcessName})
+	return resp, nil
 }
 
 // UpdateAllProcesses updates all processes
@@ -440,6 +374,7 @@ func (s *Server) UpdateProcess(ctx context.Context, req *pb.UpdateProcessRequest
 		return nil, fmt.Errorf("process_name is required")
 	}
 
+	currentVersion := versionOrUnknown(s.updateManager.GetCurrentVersion(req.ProcessName))
 	err := s.updateManager.UpdateProcess(req.ProcessName, req.Version, req.Force)
 	if err != nil {
 		return &pb.UpdateResponse{
@@ -455,7 +390,7 @@ func (s *Server) UpdateProcess(ctx context.Context, req *pb.UpdateProcessRequest
 		Processes: []*pb.ProcessUpdateStatus{
 			{
 				Name:          req.ProcessName,
-				CurrentVersion: "unknown", // TODO: Get current version
+				CurrentVersion: currentVersion,
 				TargetVersion:  req.Version,
 				Status:        "updating",
 			},
@@ -478,20 +413,28 @@ func (s *Server) GetProcessVersion(ctx context.Context, req *pb.GetVersionReques
 	for i, inst := range instances {
 		instanceVersions[i] = &pb.InstanceVersion{
 			Id:      inst.ID,
-			Version: "unknown", // TODO: Track version per instance
+			Version: versionOrUnknown(inst.Version),
 			Uptime:  int64(time.Since(inst.StartTime).Seconds()),
 		}
 	}
 
 	return &pb.VersionInfo{
 		ProcessName:     req.ProcessName,
-		CurrentVersion:  "unknown", // TODO: Implement version tracking
+		CurrentVersion:  versionOrUnknown(s.updateManager.GetCurrentVersion(req.ProcessName)),
 		LatestVersion:   "unknown", // TODO: Get from update manager
 		UpdateAvailable: false,     // TODO: Check if update available
 		Instances:       instanceVersions,
 	}, nil
 }
 
+// versionOrUnknown returns the version, or "unknown" if it is not tracked
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
+
 // ListAvailableUpdates returns a list of available updates
 func (s *Server) ListAvailableUpdates(ctx context.Context, req *pb.ListUpdatesRequest) (*pb.ListUpdatesResponse, error) {
 	// TODO: Implement list available updates
@@ -506,6 +449,7 @@ func (s *Server) RollbackProcess(ctx context.Context, req *pb.RollbackRequest) (
 		return nil, fmt.Errorf("process_name is required")
 	}
 
+	fromVersion := versionOrUnknown(s.updateManager.GetCurrentVersion(req.ProcessName))
 	err := s.updateManager.RollbackProcess(req.ProcessName, req.Version)
 	if err != nil {
 		return &pb.RollbackResponse{
@@ -516,7 +460,7 @@ func (s *Server) RollbackProcess(ctx context.Context, req *pb.RollbackRequest) (
 	return &pb.RollbackResponse{
 		Success:     true,
 		ProcessName: req.ProcessName,
-		FromVersion: "unknown", // TODO: Track versions
+		FromVersion: fromVersion,
 		ToVersion:   req.Version,
 		RollbackId:  fmt.Sprintf("rbk_%s_%d", req.ProcessName, time.Now().Unix()),
 	}, nil
@@ -10,9 +10,13 @@ import (
 	"os/exec"
 	"time"
 
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
 )
 
+var invokeRequests = metrics.NewCounterVec("gowinproc_invoke_requests_total",
+	"JSON gRPC invocations through /api/invoke by result (success, unavailable, error)", "process", "result")
+
 // InvokeRequest represents a gRPC method invocation request
 type InvokeRequest struct {
 	Process string                 `json:"process"` // Process name (e.g., "db_service_local")
@@ -71,8 +75,9 @@ func (h *GrpcInvokeHandler) InvokeMethod(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get process port
-	instances, err := h.processManager.GetProcessStatus(req.Process)
+	instances, err := h.processManager.GetRoutableInstances(req.Process)
 	if err != nil || len(instances) == 0 || instances[0].Port <= 0 {
+		invokeRequests.Inc(req.Process, "unavailable")
 		h.sendError(w, fmt.Sprintf("Process %s is not running", req.Process), http.StatusServiceUnavailable)
 		return
 	}
@@ -85,10 +90,13 @@ func (h *GrpcInvokeHandler) InvokeMethod(w http.ResponseWriter, r *http.Request)
 	// Invoke gRPC method using grpcurl
 	result, err := h.invokeWithGrpcurl(address, req.Service, req.Method, req.Data)
 	if err != nil {
+		invokeRequests.Inc(req.Process, "error")
 		h.sendError(w, fmt.Sprintf("Failed to invoke method: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	invokeRequests.Inc(req.Process, "success")
+
 	// Send response
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(InvokeResponse{
@@ -159,8 +167,9 @@ func (h *GrpcInvokeHandler) InvokeMethodDirect(ctx context.Context, req *InvokeR
 	}
 
 	// Get process port
-	instances, err := h.processManager.GetProcessStatus(req.Process)
+	instances, err := h.processManager.GetRoutableInstances(req.Process)
 	if err != nil || len(instances) == 0 || instances[0].Port <= 0 {
+		invokeRequests.Inc(req.Process, "unavailable")
 		return nil, fmt.Errorf("process %s is not running", req.Process)
 	}
 
@@ -172,12 +181,14 @@ func (h *GrpcInvokeHandler) InvokeMethodDirect(ctx context.Context, req *InvokeR
 	// Invoke gRPC method using grpcurl
 	result, err := h.invokeWithGrpcurl(address, req.Service, req.Method, req.Data)
 	if err != nil {
+		invokeRequests.Inc(req.Process, "error")
 		return &InvokeResponse{
 			Success: false,
 			Error:   fmt.Sprintf("Failed to invoke method: %v", err),
 		}, nil
 	}
 
+	invokeRequests.Inc(req.Process, "success")
 	return &InvokeResponse{
 		Success: true,
 		Data:    result,
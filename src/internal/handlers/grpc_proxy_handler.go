@@ -5,24 +5,40 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
+var proxyRequests = metrics.NewCounterVec("gowinproc_proxy_requests_total",
+	"gRPC-Web requests through /proxy/{process}/ by result (proxied, unavailable, error)", "process", "result")
+
 // GrpcProxyHandler handles dynamic gRPC-Web proxy requests
 type GrpcProxyHandler struct {
 	processManager *process.Manager
 	wrapperCache   map[string]*grpcweb.WrappedGrpcServer
 	connCache      map[string]*grpc.ClientConn
 	cacheMu        sync.RWMutex
+	accessLog      *accesslog.Logger // nil: calls are not logged
 }
 
+// proxyTargetKey carries the instance a request is proxied to into the
+// stream context for the access log
+type proxyTargetKey struct{}
+
 // NewGrpcProxyHandler creates a new gRPC proxy handler
 func NewGrpcProxyHandler(procMgr *process.Manager) *GrpcProxyHandler {
 	return &GrpcProxyHandler{
@@ -32,18 +48,31 @@ func NewGrpcProxyHandler(procMgr *process.Manager) *GrpcProxyHandler {
 	}
 }
 
+// SetAccessLogger sets the logger that records proxied calls
+func (h *GrpcProxyHandler) SetAccessLogger(l *accesslog.Logger) {
+	h.accessLog = l
+}
+
 // director is a function that modifies the context and returns the backend connection
 type director func(ctx context.Context, fullMethodName string) (context.Context, *grpc.ClientConn, error)
 
 // transparentHandler creates a transparent proxy handler
 func (h *GrpcProxyHandler) transparentHandler(conn *grpc.ClientConn) grpc.StreamHandler {
-	return func(srv interface{}, serverStream grpc.ServerStream) error {
+	return func(srv interface{}, serverStream grpc.ServerStream) (err error) {
 		// Get method name from stream context
 		fullMethodName, ok := grpc.MethodFromServerStream(serverStream)
 		if !ok {
 			return fmt.Errorf("failed to get method name from stream")
 		}
 
+		var requestBytes, responseBytes int64
+		if h.accessLog != nil {
+			start := time.Now()
+			defer func() {
+				h.logCall(serverStream.Context(), fullMethodName, requestBytes, responseBytes, start, err)
+			}()
+		}
+
 		// Extract metadata from incoming stream
 		md, ok := metadata.FromIncomingContext(serverStream.Context())
 		if !ok {
@@ -79,6 +108,7 @@ func (h *GrpcProxyHandler) transparentHandler(conn *grpc.ClientConn) grpc.Stream
 					errChan <- err
 					return
 				}
+				requestBytes += int64(len(msg))
 				if err := clientStream.SendMsg(msg); err != nil {
 					errChan <- err
 					return
@@ -98,6 +128,7 @@ func (h *GrpcProxyHandler) transparentHandler(conn *grpc.ClientConn) grpc.Stream
 					errChan <- err
 					return
 				}
+				responseBytes += int64(len(msg))
 				if err := serverStream.SendMsg(msg); err != nil {
 					errChan <- err
 					return
@@ -174,9 +205,37 @@ func (h *GrpcProxyHandler) GetOrCreateWrapper(processName string, port int) (*gr
 
 // ProxyRequest proxies a gRPC-Web request to a native gRPC backend
 func (h *GrpcProxyHandler) ProxyRequest(w http.ResponseWriter, r *http.Request, processName string, grpcPath string) {
-	// Get process port dynamically
-	instances, err := h.processManager.GetProcessStatus(processName)
+	// Keep the process from being stopped by its idle timeout until the
+	// call ends
+	defer h.processManager.BeginCall(processName)()
+
+	// Wake the process first if it was stopped by its idle timeout
+	if h.processManager.IsScaledToZero(processName) {
+		if _, err := h.processManager.WakeProcess(processName); err != nil {
+			log.Printf("Failed to wake %s: %v", processName, err)
+		}
+	}
+
+	// Get process port dynamically (standby instances of a blue/green
+	// deploy receive no traffic)
+	instances, err := h.processManager.GetRoutableInstances(processName)
+	if err == nil {
+		// A canary update sends part of the calls to the canary instances
+		instances = h.processManager.SplitInstances(processName, instances, rand.IntN)
+	}
 	if err != nil || len(instances) == 0 || instances[0].Port <= 0 {
+		proxyRequests.Inc(processName, "unavailable")
+		if h.accessLog.Sampled(true) {
+			h.accessLog.Log(accesslog.Entry{
+				Time:    time.Now(),
+				Source:  accesslog.SourceGrpcProxy,
+				Method:  grpcPath,
+				Process: processName,
+				Code:    codes.Unavailable.String(),
+				Failed:  true,
+				Peer:    r.RemoteAddr,
+			})
+		}
 		http.Error(w, fmt.Sprintf("Process %s is not running or has no port assigned", processName), http.StatusServiceUnavailable)
 		return
 	}
@@ -187,13 +246,48 @@ func (h *GrpcProxyHandler) ProxyRequest(w http.ResponseWriter, r *http.Request,
 	wrapper, err := h.GetOrCreateWrapper(processName, port)
 	if err != nil {
 		log.Printf("Failed to create wrapper for %s: %v", processName, err)
+		proxyRequests.Inc(processName, "error")
 		http.Error(w, fmt.Sprintf("Failed to create proxy: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Modify request path to remove /proxy/{processName} prefix
 	r.URL.Path = grpcPath
+	r = r.WithContext(context.WithValue(r.Context(), proxyTargetKey{}, instances[0]))
 
 	// Proxy the request through gRPC-Web wrapper
+	proxyRequests.Inc(processName, "proxied")
 	wrapper.ServeHTTP(w, r)
 }
+
+// logCall writes the access log entry of a proxied call
+func (h *GrpcProxyHandler) logCall(ctx context.Context, method string, requestBytes, responseBytes int64, start time.Time, err error) {
+	code := status.Code(err)
+	if !h.accessLog.Sampled(code != codes.OK) {
+		return
+	}
+
+	e := accesslog.Entry{
+		Time:          start,
+		Source:        accesslog.SourceGrpcProxy,
+		Method:        method,
+		Code:          code.String(),
+		Failed:        code != codes.OK,
+		RequestBytes:  requestBytes,
+		ResponseBytes: responseBytes,
+		DurationMs:    float64(time.Since(start)) / float64(time.Millisecond),
+	}
+	if inst, ok := ctx.Value(proxyTargetKey{}).(*models.ProcessInstance); ok {
+		e.Process = inst.ProcessName
+		e.Instance = inst.ID
+		e.Version = inst.Version
+		e.Backend = fmt.Sprintf("127.0.0.1:%d", inst.Port)
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		e.Peer = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		e.Metadata = h.accessLog.Metadata(md.Get)
+	}
+	h.accessLog.Log(e)
+}
@@ -0,0 +1,158 @@
+package loadbalancer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+)
+
+// SetAccessLogger sets the logger that records proxied calls (nil disables it)
+func (lb *Balancer) SetAccessLogger(l *accesslog.Logger) {
+	lb.accessLog = l
+}
+
+// byteCounter counts the message bytes of a call in both directions
+type byteCounter struct {
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+// countingStream counts the bytes of the messages passing through a stream
+type countingStream struct {
+	grpc.ServerStream
+	counter *byteCounter
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if f, ok := m.(*frame); ok && err == nil {
+		s.counter.received.Add(int64(len(f.payload)))
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	if f, ok := m.(*frame); ok {
+		s.counter.sent.Add(int64(len(f.payload)))
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// countBytes wraps the stream to count its bytes if calls are logged;
+// otherwise it returns the stream unchanged and a nil counter
+func (lb *Balancer) countBytes(stream grpc.ServerStream) (grpc.ServerStream, *byteCounter) {
+	if lb.accessLog == nil {
+		return stream, nil
+	}
+	counter := &byteCounter{}
+	return &countingStream{ServerStream: stream, counter: counter}, counter
+}
+
+// logCall writes the access log entry of a gRPC call. route and selected are
+// nil if the call was not routed or no backend was selected.
+func (lb *Balancer) logCall(ctx context.Context, route *routeHandler, method string, selected *backend,
+	counter *byteCounter, start time.Time, err error) {
+
+	code := status.Code(err)
+	failed := code != codes.OK
+	if !lb.accessLog.Sampled(failed) {
+		return
+	}
+
+	e := lb.newEntry(route, selected, start)
+	e.Method = method
+	e.Code = code.String()
+	e.Failed = failed
+	if counter != nil {
+		e.RequestBytes = counter.received.Load()
+		e.ResponseBytes = counter.sent.Load()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		e.Peer = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		e.Metadata = lb.accessLog.Metadata(md.Get)
+	}
+	lb.accessLog.Log(e)
+}
+
+// logHTTPCall writes the access log entry of an HTTP request
+func (lb *Balancer) logHTTPCall(r *http.Request, route *routeHandler, selected *backend,
+	requestBytes, responseBytes int64, statusCode int, start time.Time) {
+
+	failed := statusCode >= 500
+	if !lb.accessLog.Sampled(failed) {
+		return
+	}
+
+	e := lb.newEntry(route, selected, start)
+	e.Method = r.Method + " " + r.URL.Path
+	e.Code = strconv.Itoa(statusCode)
+	e.Failed = failed
+	e.RequestBytes = requestBytes
+	e.ResponseBytes = responseBytes
+	e.Peer = r.RemoteAddr
+	e.Metadata = lb.accessLog.Metadata(r.Header.Values)
+	lb.accessLog.Log(e)
+}
+
+// newEntry fills the fields shared by gRPC and HTTP entries
+func (lb *Balancer) newEntry(route *routeHandler, selected *backend, start time.Time) accesslog.Entry {
+	e := accesslog.Entry{
+		Time:       start,
+		Source:     accesslog.SourceLoadBalancer,
+		Balancer:   lb.config.Name,
+		DurationMs: float64(time.Since(start)) / float64(time.Millisecond),
+	}
+	if route != nil {
+		e.Route = route.name
+	}
+	if selected != nil {
+		e.Process = selected.process
+		e.Instance = selected.instanceID
+		e.Version = selected.version
+		e.Backend = selected.addr
+	}
+	return e
+}
+
+// countingBody counts the bytes read from a request body
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// countingResponseWriter counts the bytes of a response body
+type countingResponseWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer for
+// flushing and hijacking upgraded connections
+func (w *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,200 @@
+package loadbalancer
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// p2cThreshold is the pool size above which least_connections and
+	// least_latency compare two random backends instead of scanning all
+	p2cThreshold = 8
+
+	// ewmaDecay is the time constant of the latency moving average
+	ewmaDecay = 10 * time.Second
+)
+
+// backend tracks per-instance state used by backend selection
+type backend struct {
+	addr       string
+	process    string
+	instanceID string
+	version    string
+	conn       *grpc.ClientConn // shared by all calls to this instance (nil in HTTP mode)
+
+	inFlight int64 // atomic: streams currently proxied to this backend
+
+	mu          sync.Mutex
+	ewma        float64 // seconds
+	lastLatency time.Time
+
+	// Outlier detection (guarded by mu)
+	consecutiveErrors int
+	windowStart       time.Time
+	windowRequests    int
+	windowErrors      int
+	ejectedUntil      time.Time
+	ejections         int // consecutive ejections; scales the ejection time
+
+	// Admin state and statistics (guarded by mu)
+	draining   bool // set by an operator; no new calls are sent
+	requests   uint64
+	errors     uint64 // calls that ended with a status other than OK or CANCELLED
+	latencySum time.Duration
+}
+
+// acquire marks the start of a proxied stream
+func (b *backend) acquire() {
+	atomic.AddInt64(&b.inFlight, 1)
+}
+
+// release marks the end of a proxied stream, counts it and feeds its latency
+// into the moving average. Calls that never reached the backend or were
+// cancelled by the client say nothing about backend latency and are left out
+// of the average.
+func (b *backend) release(latency time.Duration, code codes.Code) {
+	atomic.AddInt64(&b.inFlight, -1)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.requests++
+	b.latencySum += latency
+	if code != codes.OK && code != codes.Canceled {
+		b.errors++
+	}
+
+	if code == codes.Unavailable || code == codes.Canceled {
+		return
+	}
+
+	now := time.Now()
+	sample := latency.Seconds()
+	if b.lastLatency.IsZero() {
+		b.ewma = sample
+	} else {
+		// Weight the previous average by how recently it was updated
+		w := math.Exp(-float64(now.Sub(b.lastLatency)) / float64(ewmaDecay))
+		b.ewma = b.ewma*w + sample*(1-w)
+	}
+	b.lastLatency = now
+}
+
+// outstanding returns the number of in-flight streams
+func (b *backend) outstanding() int64 {
+	return atomic.LoadInt64(&b.inFlight)
+}
+
+// latencyCost returns the expected latency of one more request: the moving
+// average scaled by the queue in front of it. New backends start from the
+// pool's average (see seedLatency) so their in-flight calls count before the
+// first one completes.
+func (b *backend) latencyCost() float64 {
+	b.mu.Lock()
+	ewma := b.ewma
+	b.mu.Unlock()
+	return ewma * float64(b.outstanding()+1)
+}
+
+// backendFor returns the tracked state for an instance, connecting to it on first use
+func (lb *Balancer) backendFor(process, instanceID, version, addr string) (*backend, error) {
+	lb.backendsMu.Lock()
+	defer lb.backendsMu.Unlock()
+
+	if b, ok := lb.backends[instanceID]; ok {
+		if b.addr == addr {
+			return b, nil
+		}
+		// Port changed under the same instance; start over and let calls
+		// still running on the old connection finish
+		lb.retired = append(lb.retired, b)
+		delete(lb.backends, instanceID)
+	}
+
+	b := &backend{addr: addr, process: process, instanceID: instanceID, version: version, ewma: lb.seedLatency(process)}
+	if lb.config.Protocol != "http" {
+		conn, err := dialBackend(addr, lb.backendTLS)
+		if err != nil {
+			return nil, err
+		}
+		b.conn = conn
+	}
+	lb.backends[instanceID] = b
+	return b, nil
+}
+
+// seedLatency returns the latency average a new backend of a process starts
+// from: the mean of the process's backends with samples, or of all backends
+// with samples if it has none yet. The first sample of the new backend
+// replaces it. Called with backendsMu held.
+func (lb *Balancer) seedLatency(process string) float64 {
+	var sum, procSum float64
+	var n, procN int
+	for _, b := range lb.backends {
+		b.mu.Lock()
+		ewma, sampled := b.ewma, !b.lastLatency.IsZero()
+		b.mu.Unlock()
+		if !sampled {
+			continue
+		}
+		sum += ewma
+		n++
+		if b.process == process {
+			procSum += ewma
+			procN++
+		}
+	}
+	switch {
+	case procN > 0:
+		return procSum / float64(procN)
+	case n > 0:
+		return sum / float64(n)
+	}
+	return 0
+}
+
+// pickLowest returns the backend with the lowest cost. Ties are broken at
+// random; pools larger than p2cThreshold use the power of two choices.
+func pickLowest(backends []*backend, cost func(*backend) float64) *backend {
+	if len(backends) == 1 {
+		return backends[0]
+	}
+
+	if len(backends) > p2cThreshold {
+		i := rand.IntN(len(backends))
+		j := rand.IntN(len(backends) - 1)
+		if j >= i {
+			j++
+		}
+		a, b := backends[i], backends[j]
+		ca, cb := cost(a), cost(b)
+		if cb < ca || (cb == ca && rand.IntN(2) == 0) {
+			return b
+		}
+		return a
+	}
+
+	var best *backend
+	bestCost := math.Inf(1)
+	ties := 0
+	for _, b := range backends {
+		c := cost(b)
+		switch {
+		case c < bestCost:
+			best, bestCost, ties = b, c, 1
+		case c == bestCost:
+			// Reservoir sampling keeps each tied backend equally likely
+			ties++
+			if rand.IntN(ties) == 0 {
+				best = b
+			}
+		}
+	}
+	return best
+}
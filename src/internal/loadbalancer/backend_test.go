@@ -0,0 +1,75 @@
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestLeastLatencyNewBackend(t *testing.T) {
+	lb := &Balancer{
+		config:   models.LoadBalancerConfig{Name: "web", Protocol: "http"},
+		backends: make(map[string]*backend),
+	}
+
+	warm, err := lb.backendFor("web", "web-1", "v1", "localhost:5001")
+	if err != nil {
+		t.Fatalf("backendFor: %v", err)
+	}
+	warm.acquire()
+	warm.release(100*time.Millisecond, codes.OK)
+
+	cold, err := lb.backendFor("web", "web-2", "v1", "localhost:5002")
+	if err != nil {
+		t.Fatalf("backendFor: %v", err)
+	}
+	if cold.latencyCost() != warm.latencyCost() {
+		t.Fatalf("new backend costs %v, want the pool's %v", cold.latencyCost(), warm.latencyCost())
+	}
+
+	// Calls still in flight on the new backend count before its first sample
+	cold.acquire()
+	cold.acquire()
+	for i := 0; i < 100; i++ {
+		if b := pickLowest([]*backend{warm, cold}, (*backend).latencyCost); b != warm {
+			t.Fatalf("picked the new backend with %d calls in flight", cold.outstanding())
+		}
+	}
+}
+
+func TestBackendPortChangeDrains(t *testing.T) {
+	lb := &Balancer{
+		config:   models.LoadBalancerConfig{Name: "api", Protocol: "grpc"},
+		backends: make(map[string]*backend),
+	}
+	defer lb.closePool()
+
+	old, err := lb.backendFor("api", "api-1", "v1", "localhost:5001")
+	if err != nil {
+		t.Fatalf("backendFor: %v", err)
+	}
+	old.acquire()
+
+	moved, err := lb.backendFor("api", "api-1", "v1", "localhost:5002")
+	if err != nil {
+		t.Fatalf("backendFor: %v", err)
+	}
+	if moved == old {
+		t.Fatal("backendFor kept the backend of the old port")
+	}
+
+	lb.syncPool()
+	if old.state() == connectivity.Shutdown {
+		t.Fatal("old connection closed while a call was still running on it")
+	}
+
+	old.release(time.Millisecond, codes.OK)
+	lb.syncPool()
+	if old.state() != connectivity.Shutdown {
+		t.Fatalf("old connection is %v after draining, want SHUTDOWN", old.state())
+	}
+}
@@ -1,62 +1,214 @@
 package loadbalancer
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"io"
 	"log"
+	"math/rand/v2"
 	"net"
+	"net/http"
+	"net/http/httputil"
 	"regexp"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
-	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/certs"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
 )
 
-// Balancer implements a gRPC load balancer
+var (
+	lbRequests = metrics.NewCounterVec("gowinproc_lb_requests_total",
+		"Requests handled by a load balancer", "balancer", "route", "backend", "code")
+	lbRequestErrors = metrics.NewCounterVec("gowinproc_lb_request_errors_total",
+		"Requests that ended with a non-OK status", "balancer", "route", "backend", "code")
+	lbRequestDuration = metrics.NewHistogramVec("gowinproc_lb_request_duration_seconds",
+		"Duration of proxied requests", nil, "balancer", "route", "backend")
+)
+
+// ProcessManager is the part of the process manager a balancer routes with
+type ProcessManager interface {
+	GetRoutableInstances(processName string) ([]*models.ProcessInstance, error)
+	TrafficSplit(processName string) (map[string]bool, int)
+	BeginCall(processName string) (end func())
+	IsScaledToZero(processName string) bool
+	WakeProcess(processName string) (*models.ProcessInstance, error)
+}
+
+// Balancer implements a gRPC or HTTP load balancer
 type Balancer struct {
 	config         models.LoadBalancerConfig
-	processManager *process.Manager
+	processManager ProcessManager
 	server         *grpc.Server
 	listener       net.Listener
 
 	// Routing
-	routes []*routeHandler
-
-	// For round-robin strategy
-	counters map[string]*uint64 // key: route index
+	routes       []*routeHandler
+	defaultRoute *routeHandler // used when no route matches (optional)
+
+	// Per-instance selection state, keyed by instance ID
+	backends   map[string]*backend
+	retired    []*backend // replaced backends closed once drained
+	backendsMu sync.Mutex
+	stopCh     chan struct{}
+
+	// TLS
+	certManager *certs.Manager
+	reloaders   []*certReloader
+	backendTLS  *tls.Config // nil: plaintext to backends
+
+	// HTTP mode, or gRPC mode with browser protocols
+	httpServer *http.Server
+	httpProxy  *httputil.ReverseProxy
+	transport  *http.Transport
+	grpcWeb    *grpcweb.WrappedGrpcServer // nil unless gRPC-Web is enabled
+
+	accessLog *accesslog.Logger // nil: calls are not logged
 }
 
 // routeHandler represents a compiled route
 type routeHandler struct {
+	name           string
 	methodPatterns []*regexp.Regexp
+	headers        []headerMatcher
 	targetProcs    []string
 	strategy       string
+	next           uint64 // round-robin position
+
+	// Weighted split between target processes, adjustable at runtime
+	weights        map[string]int
+	weightsMu      sync.RWMutex
+	targetRequests map[string]*uint64 // per target process
+
+	// Call policy
+	timeout time.Duration // default deadline (0 = none)
+	policy  *callPolicy   // retries and hedging (nil = single attempt)
+	mirror  *mirror       // shadow traffic (nil = not mirrored)
+	limiter *limiter      // rate and concurrency limits
+
+	// Consistent hashing (hash strategy)
+	hashMetadataKey string
+	rings           map[string]*hashRing // keyed by backend set
+	ringsMu         sync.Mutex
+
+	// Header rewriting
+	requestHeaders  *headerRewrite
+	responseHeaders *headerRewrite
+
+	// HTTP matching
+	hosts       []string
+	pathPrefix  string
+	stripPrefix bool
 }
 
 // NewBalancer creates a new load balancer
-func NewBalancer(config models.LoadBalancerConfig, procMgr *process.Manager) (*Balancer, error) {
-	if config.Protocol != "grpc" {
-		return nil, fmt.Errorf("only gRPC protocol is supported, got: %s", config.Protocol)
+func NewBalancer(config models.LoadBalancerConfig, procMgr ProcessManager) (*Balancer, error) {
+	if config.Protocol != "grpc" && config.Protocol != "http" {
+		return nil, fmt.Errorf("protocol must be 'grpc' or 'http', got: %s", config.Protocol)
 	}
 
 	lb := &Balancer{
 		config:         config,
 		processManager: procMgr,
-		counters:       make(map[string]*uint64),
+		backends:       make(map[string]*backend),
+		stopCh:         make(chan struct{}),
+	}
+
+	if t := config.TLS; t != nil {
+		if t.ClientAuth != "" && t.ClientAuth != "require" && t.ClientAuth != "optional" {
+			return nil, fmt.Errorf("tls.client_auth must be 'require' or 'optional', got: %s", t.ClientAuth)
+		}
+		if (t.CertFile == "") != (t.KeyFile == "") {
+			return nil, fmt.Errorf("tls.cert_file and tls.key_file must be set together")
+		}
+	}
+	if t := config.BackendTLS; t != nil && (t.ClientCertFile == "") != (t.ClientKeyFile == "") {
+		return nil, fmt.Errorf("backend_tls.client_cert_file and client_key_file must be set together")
+	}
+	if web := config.Web; web != nil {
+		if config.Protocol != "grpc" {
+			return nil, fmt.Errorf("web is only supported for grpc load balancers")
+		}
+		if !web.GRPCWeb && !web.Connect {
+			return nil, fmt.Errorf("web must enable grpc_web or connect")
+		}
+		if web.MaxMessageBytes <= 0 {
+			return nil, fmt.Errorf("web.max_message_bytes must be positive")
+		}
+	}
+
+	if od := config.OutlierDetection; od != nil {
+		if od.ErrorRate < 0 || od.ErrorRate > 1 {
+			return nil, fmt.Errorf("outlier_detection.error_rate must be between 0 and 1")
+		}
+		if od.MaxEjectionPercent < 0 || od.MaxEjectionPercent > 100 {
+			return nil, fmt.Errorf("outlier_detection.max_ejection_percent must be between 0 and 100")
+		}
 	}
 
 	// Compile route patterns
 	for i, route := range config.Routes {
+		switch route.Strategy {
+		case "primary", "round_robin", "least_connections", "least_latency", "hash":
+		default:
+			return nil, fmt.Errorf("unknown strategy %q in route %d", route.Strategy, i)
+		}
+
+		name := route.Name
+		if name == "" {
+			name = fmt.Sprintf("route_%d", i)
+		}
+
 		handler := &routeHandler{
+			name:           name,
 			methodPatterns: make([]*regexp.Regexp, len(route.Methods)),
 			targetProcs:    route.TargetProcesses,
 			strategy:       route.Strategy,
+			targetRequests: make(map[string]*uint64),
+		}
+
+		policy, err := compilePolicy(route.Retry, route.Hedge)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		handler.policy = policy
+
+		handler.mirror, err = compileMirror(route.Mirror)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		handler.timeout = route.Timeout
+
+		handler.limiter, err = newLimiter(route.RateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+
+		if route.Strategy == "hash" {
+			handler.hashMetadataKey = strings.ToLower(route.HashKey)
+			handler.rings = make(map[string]*hashRing)
+		}
+
+		if err := validateWeights(route.TargetProcesses, route.Weights); err != nil {
+			return nil, fmt.Errorf("invalid weights in route %d: %w", i, err)
+		}
+		if len(route.Weights) > 0 {
+			handler.weights = route.Weights
+		}
+		for _, target := range route.TargetProcesses {
+			handler.targetRequests[target] = new(uint64)
 		}
 
 		for j, pattern := range route.Methods {
@@ -67,13 +219,29 @@ func NewBalancer(config models.LoadBalancerConfig, procMgr *process.Manager) (*B
 			handler.methodPatterns[j] = re
 		}
 
-		lb.routes = append(lb.routes, handler)
+		headers, err := compileHeaderMatchers(route.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		handler.headers = headers
+		handler.requestHeaders = (*headerRewrite)(route.RequestHeaders)
 
-		// Initialize counter for round-robin
-		if route.Strategy == "round_robin" {
-			counter := uint64(0)
-			lb.counters[fmt.Sprintf("route_%d", i)] = &counter
+		if config.Protocol == "http" {
+			if err := compileHTTPRoute(handler, route); err != nil {
+				return nil, fmt.Errorf("route %d: %w", i, err)
+			}
+		} else if route.ResponseHeaders != nil {
+			return nil, fmt.Errorf("route %d: response_headers are only supported for http load balancers", i)
+		}
+
+		if route.Default {
+			if lb.defaultRoute != nil {
+				return nil, fmt.Errorf("route %d: only one route can be the default", i)
+			}
+			lb.defaultRoute = handler
 		}
+
+		lb.routes = append(lb.routes, handler)
 	}
 
 	return lb, nil
@@ -81,18 +249,53 @@ func NewBalancer(config models.LoadBalancerConfig, procMgr *process.Manager) (*B
 
 // Start starts the load balancer server
 func (lb *Balancer) Start() error {
+	listenerTLS, err := lb.listenerTLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS: %w", err)
+	}
+	lb.backendTLS, err = lb.backendTLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to configure backend TLS: %w", err)
+	}
+
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", lb.config.ListenPort))
 	if err != nil {
 		return fmt.Errorf("failed to listen on port %d: %w", lb.config.ListenPort, err)
 	}
 	lb.listener = listener
 
-	// Create gRPC server with unknown service handler
-	lb.server = grpc.NewServer(
-		grpc.UnknownServiceHandler(lb.proxyHandler),
-	)
+	go lb.runPool()
+	if len(lb.reloaders) > 0 {
+		go lb.runCertReload()
+	}
+
+	log.Printf("Load balancer %q starting on port %d (%s, tls: %v)", lb.config.Name, lb.config.ListenPort, lb.config.Protocol, listenerTLS != nil)
 
-	log.Printf("Load balancer %q starting on port %d", lb.config.Name, lb.config.ListenPort)
+	if lb.config.Protocol == "http" {
+		lb.transport = newHTTPTransport(lb.config.BackendHTTP2, lb.backendTLS)
+		lb.httpServer = lb.newHTTPServer(listenerTLS)
+		go lb.serveHTTPServer(listenerTLS != nil)
+		return nil
+	}
+
+	// Create gRPC server with unknown service handler; messages are passed
+	// through as raw bytes
+	opts := []grpc.ServerOption{
+		grpc.UnknownServiceHandler(lb.proxyHandler),
+		grpc.ForceServerCodec(frameCodec{}),
+	}
+	if listenerTLS != nil && lb.config.Web == nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(listenerTLS)))
+	}
+	lb.server = grpc.NewServer(opts...)
+
+	// Browser protocols need HTTP/1.1, so the HTTP server owns the listener
+	// and hands native gRPC calls to the gRPC server
+	if lb.config.Web != nil {
+		lb.httpServer = lb.newWebServer(listenerTLS)
+		go lb.serveHTTPServer(listenerTLS != nil)
+		return nil
+	}
 
 	go func() {
 		if err := lb.server.Serve(listener); err != nil {
@@ -103,14 +306,45 @@ func (lb *Balancer) Start() error {
 	return nil
 }
 
+// serveHTTPServer runs the HTTP server on the listener until Stop
+func (lb *Balancer) serveHTTPServer(useTLS bool) {
+	var err error
+	if useTLS {
+		err = lb.httpServer.ServeTLS(lb.listener, "", "")
+	} else {
+		err = lb.httpServer.Serve(lb.listener)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		log.Printf("Load balancer %q server error: %v", lb.config.Name, err)
+	}
+}
+
 // Stop stops the load balancer
 func (lb *Balancer) Stop() error {
+	if lb.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := lb.httpServer.Shutdown(ctx); err != nil {
+			lb.httpServer.Close()
+		}
+		cancel()
+	}
 	if lb.server != nil {
-		lb.server.GracefulStop()
+		if lb.httpServer != nil {
+			// Calls served through ServeHTTP do not support GracefulStop;
+			// the HTTP server has already waited for them
+			lb.server.Stop()
+		} else {
+			lb.server.GracefulStop()
+		}
+	}
+	if lb.transport != nil {
+		lb.transport.CloseIdleConnections()
 	}
 	if lb.listener != nil {
 		lb.listener.Close()
 	}
+	close(lb.stopCh)
+	lb.closePool()
 	log.Printf("Load balancer %q stopped", lb.config.Name)
 	return nil
 }
@@ -124,109 +358,216 @@ func (lb *Balancer) proxyHandler(srv interface{}, stream grpc.ServerStream) erro
 	}
 
 	// Find matching route
-	route := lb.findRoute(method)
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	route := lb.findRoute(method, md)
 	if route == nil {
-		return status.Errorf(codes.Unimplemented, "no route found for method: %s", method)
+		err := status.Errorf(codes.Unimplemented, "no route found for method: %s", method)
+		lbRequests.Inc(lb.config.Name, "", "", codes.Unimplemented.String())
+		lb.logCall(stream.Context(), nil, method, nil, nil, time.Now(), err)
+		return err
 	}
 
-	// Select backend based on strategy
-	backend, err := lb.selectBackend(route)
+	start := time.Now()
+	release, retryAfter, err := lb.admit(stream.Context(), route)
 	if err != nil {
-		return status.Errorf(codes.Unavailable, "no available backend: %v", err)
+		stream.SetTrailer(metadata.Pairs(retryAfterKey, retryAfterSeconds(retryAfter)))
+		lb.observe(route, nil, start, err)
+		lb.logCall(stream.Context(), route, method, nil, nil, start, err)
+		return err
 	}
-
-	// Create client connection to backend
-	conn, err := grpc.Dial(backend, grpc.WithInsecure())
-	if err != nil {
-		return status.Errorf(codes.Unavailable, "failed to connect to backend %s: %v", backend, err)
+	defer release()
+
+	stream, tap := lb.tapMirror(route, stream)
+	stream, counter := lb.countBytes(stream)
+	selected, err := lb.proxy(route, method, stream)
+	lb.observe(route, selected, start, err)
+	if tap != nil {
+		lb.mirrorCall(route, method, tap, time.Since(start), err)
 	}
-	defer conn.Close()
+	lb.logCall(stream.Context(), route, method, selected, counter, start, err)
+	return err
+}
 
-	// Proxy the request
+// proxy selects a backend for the route and proxies the stream to it.
+// Returns the selected backend (nil if none was selected).
+func (lb *Balancer) proxy(route *routeHandler, method string, stream grpc.ServerStream) (_ *backend, err error) {
+	// Keep target processes from being stopped by their idle timeout while
+	// the stream is open
+	defer lb.beginCalls(route)()
+
+	// Apply the route's deadline if the client did not send one
 	ctx := stream.Context()
-	clientStream, err := conn.NewStream(ctx, &grpc.StreamDesc{
-		StreamName:    method,
-		ServerStreams: true,
-		ClientStreams: true,
-	}, method)
+	if _, ok := ctx.Deadline(); !ok && route.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, route.timeout)
+		defer cancel()
+	}
+
+	// Proxy metadata, rewritten for the route
+	ctx = metadata.NewOutgoingContext(ctx, route.outgoingMetadata(ctx))
+
+	if route.policy != nil && (route.policy.maxAttempts > 1 || route.policy.hedged(method)) {
+		return lb.proxyWithPolicy(ctx, route, method, stream, route.policy)
+	}
+
+	// Select backend based on strategy
+	selected, err := lb.pickBackend(ctx, route, nil)
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to create client stream: %v", err)
+		return nil, err
 	}
+	route.countTarget(lb.config.Name, selected.process)
+
+	// pickBackend counted the stream as in flight; record how it ends
+	start := time.Now()
+	defer func() {
+		lb.done(selected, start, err)
+	}()
 
-	// Proxy metadata
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		ctx = metadata.NewOutgoingContext(ctx, md)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Open the call on the backend's pooled connection
+	clientStream, err := selected.conn.NewStream(ctx, proxyStreamDesc, method)
+	if err != nil {
+		return selected, err
 	}
 
-	// Bidirectional streaming proxy
-	var wg sync.WaitGroup
-	var clientErr, serverErr error
+	return selected, forward(stream, clientStream, cancel)
+}
 
-	// Client -> Server
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for {
-			var msg interface{}
-			if err := stream.RecvMsg(&msg); err != nil {
-				clientErr = err
-				return
-			}
-			if err := clientStream.SendMsg(msg); err != nil {
-				clientErr = err
-				return
+// pickBackend selects a backend, preferring ones not in tried, and counts the
+// call as in flight on it; the caller must pass it to done. Holds the call
+// while a scaled-to-zero target is woken if no backend is available.
+func (lb *Balancer) pickBackend(ctx context.Context, route *routeHandler, tried map[string]bool) (*backend, error) {
+	max := lb.maxConcurrent()
+	skip := tried
+	woken := false
+
+	for {
+		selected, err := lb.selectBackend(ctx, route, skip)
+		if errors.Is(err, errCircuitOpen) {
+			lbBreakerRejections.Inc(lb.config.Name, route.name)
+			return nil, status.Errorf(codes.Unavailable, "no available backend: %v", err)
+		}
+		if err != nil {
+			if woken || !lb.wakeTargets(route) {
+				return nil, status.Errorf(codes.Unavailable, "no available backend: %v", err)
 			}
+			woken = true
+			continue
 		}
-	}()
 
-	// Server -> Client
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for {
-			var msg interface{}
-			if err := clientStream.RecvMsg(&msg); err != nil {
-				serverErr = err
-				return
-			}
-			if err := stream.SendMsg(msg); err != nil {
-				serverErr = err
-				return
-			}
+		if selected.tryAcquire(max) {
+			return selected, nil
 		}
-	}()
 
-	wg.Wait()
+		// Another call took the last slot; choose again without this backend
+		next := make(map[string]bool, len(skip)+1)
+		for id := range skip {
+			next[id] = true
+		}
+		next[selected.instanceID] = true
+		skip = next
+	}
+}
 
-	// Check errors
-	if clientErr != nil && clientErr != io.EOF {
-		return clientErr
+// observe records request metrics for a proxied call; selected is nil if no
+// backend was selected
+func (lb *Balancer) observe(route *routeHandler, selected *backend, start time.Time, err error) {
+	code := status.Code(err).String()
+	backend := ""
+	if selected != nil {
+		backend = selected.addr
+		lbRequestDuration.Observe(time.Since(start).Seconds(), lb.config.Name, route.name, backend)
 	}
-	if serverErr != nil && serverErr != io.EOF {
-		return serverErr
+	lbRequests.Inc(lb.config.Name, route.name, backend, code)
+	if err != nil {
+		lbRequestErrors.Inc(lb.config.Name, route.name, backend, code)
 	}
-
-	return nil
 }
 
-// findRoute finds a matching route for the given method
-func (lb *Balancer) findRoute(method string) *routeHandler {
+// findRoute finds the first route matching the method and metadata, or the
+// default route if none does
+func (lb *Balancer) findRoute(method string, md metadata.MD) *routeHandler {
 	for _, route := range lb.routes {
-		for _, pattern := range route.methodPatterns {
-			if pattern.MatchString(method) {
-				return route
-			}
+		if route == lb.defaultRoute {
+			continue
+		}
+		if route.matchesMethod(method) && route.matchesHeaders(md.Get) {
+			return route
 		}
 	}
-	return nil
+	return lb.defaultRoute
+}
+
+// outgoingMetadata returns the metadata sent to backends for an incoming call:
+// the client's metadata rewritten for the route
+func (r *routeHandler) outgoingMetadata(ctx context.Context) metadata.MD {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	r.requestHeaders.applyMetadata(md)
+	md.Set(routeHeader, r.name)
+	return md
 }
 
 // selectBackend selects a backend based on the route strategy
-func (lb *Balancer) selectBackend(route *routeHandler) (string, error) {
+func (lb *Balancer) selectBackend(ctx context.Context, route *routeHandler, tried map[string]bool) (*backend, error) {
+	// Random draws by default; hashed routes derive them from the call's key
+	// so a key always lands on the same target and instance
+	draw := rand.IntN
+	var keyHash uint64
+	if route.strategy == "hash" {
+		keyHash = hashString(route.hashKey(ctx))
+		draw = func(n int) int { return int(keyHash % uint64(n)) }
+	}
+
 	// Get all healthy instances from target processes
-	backends := lb.getHealthyBackends(route.targetProcs)
+	var backends []*backend
+	if weights := route.getWeights(); weights != nil {
+		// Pick a target process by weight, then a backend within it
+		available := make(map[string][]*backend, len(route.targetProcs))
+		for _, procName := range route.targetProcs {
+			available[procName] = lb.getHealthyBackends([]string{procName})
+		}
+		backends = available[pickWeightedTarget(weights, available, draw)]
+	} else {
+		backends = lb.getHealthyBackends(route.targetProcs)
+	}
+	// A canary update sends part of a process's calls to its canary instances
+	backends = lb.splitCanary(backends, draw)
 	if len(backends) == 0 {
-		return "", fmt.Errorf("no healthy backends available")
+		return nil, fmt.Errorf("no healthy backends available")
+	}
+
+	// Retries go to backends that have not failed this call yet, if any
+	if len(tried) > 0 {
+		var fresh []*backend
+		for _, b := range backends {
+			if !tried[b.instanceID] {
+				fresh = append(fresh, b)
+			}
+		}
+		if len(fresh) > 0 {
+			backends = fresh
+		}
+	}
+
+	// Circuit breaker: skip backends at their concurrency limit
+	if max := lb.maxConcurrent(); max > 0 {
+		var open []*backend
+		for _, b := range backends {
+			if b.outstanding() < max {
+				open = append(open, b)
+			}
+		}
+		if len(open) == 0 {
+			return nil, errCircuitOpen
+		}
+		backends = open
 	}
 
 	switch route.strategy {
@@ -236,49 +577,115 @@ func (lb *Balancer) selectBackend(route *routeHandler) (string, error) {
 
 	case "round_robin":
 		// Round-robin across all healthy backends
-		counter := lb.counters[fmt.Sprintf("route_%p", route)]
-		if counter == nil {
-			c := uint64(0)
-			counter = &c
-			lb.counters[fmt.Sprintf("route_%p", route)] = counter
-		}
-		idx := atomic.AddUint64(counter, 1) % uint64(len(backends))
+		idx := atomic.AddUint64(&route.next, 1) % uint64(len(backends))
 		return backends[idx], nil
 
 	case "least_connections":
-		// TODO: Implement connection tracking
-		// For now, fallback to round-robin
-		counter := lb.counters[fmt.Sprintf("route_%p", route)]
-		if counter == nil {
-			c := uint64(0)
-			counter = &c
-			lb.counters[fmt.Sprintf("route_%p", route)] = counter
-		}
-		idx := atomic.AddUint64(counter, 1) % uint64(len(backends))
-		return backends[idx], nil
+		// Fewest outstanding streams
+		return pickLowest(backends, func(b *backend) float64 {
+			return float64(b.outstanding())
+		}), nil
+
+	case "least_latency":
+		// Lowest expected latency given the current queue
+		return pickLowest(backends, (*backend).latencyCost), nil
+
+	case "hash":
+		// Consistent hash of the metadata key or client address
+		return route.pickHashed(backends, keyHash), nil
 
 	default:
-		return "", fmt.Errorf("unknown strategy: %s", route.strategy)
+		return nil, fmt.Errorf("unknown strategy: %s", route.strategy)
+	}
+}
+
+// beginCalls marks the start of a call to the route's target processes so
+// their idle timeout does not stop them; the returned function ends it
+func (lb *Balancer) beginCalls(route *routeHandler) func() {
+	ends := make([]func(), len(route.targetProcs))
+	for i, procName := range route.targetProcs {
+		ends[i] = lb.processManager.BeginCall(procName)
+	}
+	return func() {
+		for _, end := range ends {
+			end()
+		}
 	}
 }
 
-// getHealthyBackends returns addresses of all healthy backend instances
-func (lb *Balancer) getHealthyBackends(processNames []string) []string {
-	var backends []string
+// wakeTargets starts an instance of the first scaled-to-zero target process and
+// waits for it to become ready. Returns true if a target was woken.
+func (lb *Balancer) wakeTargets(route *routeHandler) bool {
+	for _, procName := range route.targetProcs {
+		if !lb.processManager.IsScaledToZero(procName) {
+			continue
+		}
 
-	for _, procName := range processNames {
-		instances, err := lb.processManager.GetProcessStatus(procName)
-		if err != nil {
-			log.Printf("Failed to get status for process %s: %v", procName, err)
+		if _, err := lb.processManager.WakeProcess(procName); err != nil {
+			log.Printf("Load balancer %q: %v", lb.config.Name, err)
 			continue
 		}
+		return true
+	}
+	return false
+}
+
+// getHealthyBackends returns all healthy backend instances. Backends whose
+// connection is failing or that are ejected by outlier detection are skipped
+// unless no other backend is left.
+func (lb *Balancer) getHealthyBackends(processNames []string) []*backend {
+	var running, routable, backends []*backend
 
-		for _, inst := range instances {
-			if inst.GetStatus() == "running" && inst.Port > 0 {
-				backends = append(backends, fmt.Sprintf("localhost:%d", inst.Port))
+	for _, procName := range processNames {
+		for _, b := range lb.runningBackends(procName) {
+			// Draining backends finish their calls but get no new ones
+			if !b.isDraining() {
+				running = append(running, b)
 			}
 		}
 	}
 
+	for _, b := range running {
+		if b.routable() {
+			routable = append(routable, b)
+		}
+	}
+	if len(routable) == 0 {
+		return running
+	}
+
+	now := time.Now()
+	for _, b := range routable {
+		if !b.ejected(now) {
+			backends = append(backends, b)
+		}
+	}
+	if len(backends) == 0 {
+		return routable
+	}
+	return backends
+}
+
+// runningBackends returns the running instances of a process with a port,
+// leaving out standby instances of a blue/green deploy
+func (lb *Balancer) runningBackends(procName string) []*backend {
+	instances, err := lb.processManager.GetRoutableInstances(procName)
+	if err != nil {
+		log.Printf("Failed to get status for process %s: %v", procName, err)
+		return nil
+	}
+
+	var backends []*backend
+	for _, inst := range instances {
+		if inst.GetStatus() == "running" && inst.Port > 0 {
+			addr := fmt.Sprintf("localhost:%d", inst.Port)
+			b, err := lb.backendFor(procName, inst.ID, inst.Version, addr)
+			if err != nil {
+				log.Printf("Load balancer %q: %v", lb.config.Name, err)
+				continue
+			}
+			backends = append(backends, b)
+		}
+	}
 	return backends
 }
@@ -0,0 +1,129 @@
+package loadbalancer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestFindRouteHeaderOnly(t *testing.T) {
+	lb, err := NewBalancer(models.LoadBalancerConfig{
+		Name:       "db",
+		Protocol:   "grpc",
+		ListenPort: 50100,
+		Routes: []models.LoadBalancerRoute{
+			{
+				Name:            "tenant_customer_a",
+				Headers:         []models.HeaderMatch{{Name: "x-tenant", Exact: "customerA"}},
+				TargetProcesses: []string{"db_service_customerA"},
+				Strategy:        "round_robin",
+			},
+			{
+				Name:            "db",
+				Default:         true,
+				TargetProcesses: []string{"db_service_v1"},
+				Strategy:        "round_robin",
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBalancer: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want string
+	}{
+		{"matching header", metadata.Pairs("x-tenant", "customerA"), "tenant_customer_a"},
+		{"other header value", metadata.Pairs("x-tenant", "customerB"), "db"},
+		{"no header", metadata.MD{}, "db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lb.findRoute("/db.DBService/Query", tt.md).name; got != tt.want {
+				t.Errorf("findRoute = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// staticProcesses routes calls for every process to one instance per port
+type staticProcesses struct {
+	ports []int
+}
+
+func (p staticProcesses) GetRoutableInstances(processName string) ([]*models.ProcessInstance, error) {
+	instances := make([]*models.ProcessInstance, len(p.ports))
+	for i, port := range p.ports {
+		instances[i] = &models.ProcessInstance{
+			ID:          fmt.Sprintf("%s-%d", processName, i+1),
+			ProcessName: processName,
+			Status:      models.StatusRunning,
+			Port:        port,
+		}
+	}
+	return instances, nil
+}
+
+func (staticProcesses) TrafficSplit(string) (map[string]bool, int) { return nil, 0 }
+func (staticProcesses) BeginCall(string) func()                    { return func() {} }
+func (staticProcesses) IsScaledToZero(string) bool                 { return false }
+
+func (staticProcesses) WakeProcess(string) (*models.ProcessInstance, error) {
+	return nil, errors.New("not scaled to zero")
+}
+
+// startBackend serves the health API with srv and returns the port
+func startBackend(tb testing.TB, srv healthpb.HealthServer) int {
+	tb.Helper()
+
+	lis, err := net.Listen("tcp", ":0")
+	if err != nil {
+		tb.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, srv)
+	go server.Serve(lis)
+	tb.Cleanup(server.Stop)
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+// startBalancer starts a grpc balancer with one route to backends on the ports
+func startBalancer(tb testing.TB, route models.LoadBalancerRoute, ports ...int) *Balancer {
+	tb.Helper()
+
+	lb, err := NewBalancer(models.LoadBalancerConfig{
+		Name:     "test",
+		Protocol: "grpc",
+		Routes:   []models.LoadBalancerRoute{route},
+	}, staticProcesses{ports: ports})
+	if err != nil {
+		tb.Fatalf("NewBalancer: %v", err)
+	}
+	if err := lb.Start(); err != nil {
+		tb.Fatalf("Start: %v", err)
+	}
+	tb.Cleanup(func() { lb.Stop() })
+	return lb
+}
+
+// dialBalancer returns a client connection to a started balancer
+func dialBalancer(tb testing.TB, lb *Balancer) *grpc.ClientConn {
+	tb.Helper()
+
+	conn, err := grpc.NewClient(lb.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		tb.Fatalf("NewClient: %v", err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+	return conn
+}
@@ -0,0 +1,52 @@
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// startBenchBalancer starts a health server as the backend and a balancer in
+// front of it, and returns the balancer and a client connected to it
+func startBenchBalancer(b *testing.B) (*Balancer, healthpb.HealthClient) {
+	b.Helper()
+
+	port := startBackend(b, health.NewServer())
+	lb := startBalancer(b, models.LoadBalancerRoute{
+		TargetProcesses: []string{"backend"},
+		Strategy:        "round_robin",
+	}, port)
+	return lb, healthpb.NewHealthClient(dialBalancer(b, lb))
+}
+
+// BenchmarkProxyPooled proxies calls over the balancer's pooled backend connection
+func BenchmarkProxyPooled(b *testing.B) {
+	_, client := startBenchBalancer(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			b.Fatalf("Check: %v", err)
+		}
+	}
+}
+
+// BenchmarkProxyDialPerCall drops the pool after every call, so each call
+// dials the backend again
+func BenchmarkProxyDialPerCall(b *testing.B) {
+	lb, client := startBenchBalancer(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			b.Fatalf("Check: %v", err)
+		}
+		lb.closePool()
+	}
+}
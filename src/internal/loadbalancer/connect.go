@@ -0,0 +1,429 @@
+package loadbalancer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Connect protocol content types. Only binary protobuf is supported: JSON
+// would have to be transcoded, but messages pass through undecoded.
+const (
+	connectUnaryType     = "application/proto"
+	connectStreamingType = "application/connect+proto"
+)
+
+// Flags of a Connect streaming envelope
+const (
+	connectFlagCompressed = 0x01
+	connectFlagEndStream  = 0x02
+)
+
+// connectCodes maps gRPC codes to Connect error codes and the HTTP status of
+// unary errors
+var connectCodes = map[codes.Code]struct {
+	name       string
+	httpStatus int
+}{
+	codes.Canceled:           {"canceled", 499},
+	codes.Unknown:            {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:           {"not_found", http.StatusNotFound},
+	codes.AlreadyExists:      {"already_exists", http.StatusConflict},
+	codes.PermissionDenied:   {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"failed_precondition", http.StatusBadRequest},
+	codes.Aborted:            {"aborted", http.StatusConflict},
+	codes.OutOfRange:         {"out_of_range", http.StatusBadRequest},
+	codes.Unimplemented:      {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:           {"internal", http.StatusInternalServerError},
+	codes.Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"data_loss", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+}
+
+// connectSkipHeaders are request headers that belong to the HTTP or Connect
+// protocol and are not forwarded to backends as metadata
+var connectSkipHeaders = map[string]bool{
+	"content-type":      true,
+	"content-length":    true,
+	"content-encoding":  true,
+	"accept-encoding":   true,
+	"connection":        true,
+	"keep-alive":        true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+	"te":                true,
+	"host":              true,
+}
+
+// connectError is the JSON form of an error in the Connect protocol
+type connectError struct {
+	Code    string               `json:"code"`
+	Message string               `json:"message,omitempty"`
+	Details []connectErrorDetail `json:"details,omitempty"`
+}
+
+type connectErrorDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// connectEndStream is the last message of a Connect streaming response
+type connectEndStream struct {
+	Error    *connectError       `json:"error,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+}
+
+// serveConnect handles a Connect protocol request by proxying it like a
+// native gRPC call
+func (lb *Balancer) serveConnect(w http.ResponseWriter, r *http.Request) {
+	if lb.handleCORS(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var streaming bool
+	switch contentType := r.Header.Get("Content-Type"); contentType {
+	case connectUnaryType:
+	case connectStreamingType:
+		streaming = true
+	default:
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	stream := &connectStream{
+		w:         w,
+		body:      r.Body,
+		streaming: streaming,
+		maxBytes:  lb.config.Web.MaxMessageBytes,
+	}
+
+	method := r.URL.Path
+	if strings.Count(method, "/") != 2 || strings.HasSuffix(method, "/") {
+		stream.finish(status.Errorf(codes.Unimplemented, "invalid method path: %s", method))
+		return
+	}
+
+	encodingHeader := "Content-Encoding"
+	if streaming {
+		encodingHeader = "Connect-Content-Encoding"
+	}
+	if enc := r.Header.Get(encodingHeader); enc != "" && enc != "identity" {
+		stream.finish(status.Errorf(codes.Unimplemented, "unsupported compression %q", enc))
+		return
+	}
+
+	ctx := r.Context()
+	if v := r.Header.Get("Connect-Timeout-Ms"); v != "" {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ms < 0 || len(v) > 10 {
+			stream.finish(status.Errorf(codes.InvalidArgument, "invalid Connect-Timeout-Ms: %q", v))
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+		defer cancel()
+	}
+
+	ctx = metadata.NewIncomingContext(ctx, connectMetadata(r.Header))
+	if addr, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: net.TCPAddrFromAddrPort(addr)})
+	}
+	stream.ctx = grpc.NewContextWithServerTransportStream(ctx, &connectTransportStream{method: method, stream: stream})
+
+	stream.finish(lb.proxyHandler(nil, stream))
+}
+
+// connectMetadata converts request headers to the metadata of the call
+func connectMetadata(header http.Header) metadata.MD {
+	md := make(metadata.MD, len(header))
+	for name, values := range header {
+		key := strings.ToLower(name)
+		if connectSkipHeaders[key] || strings.HasPrefix(key, "connect-") || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		if !strings.HasSuffix(key, "-bin") {
+			md[key] = append(md[key], values...)
+			continue
+		}
+		for _, v := range values {
+			decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(v, "="))
+			if err != nil {
+				continue
+			}
+			md[key] = append(md[key], string(decoded))
+		}
+	}
+	return md
+}
+
+// encodeMetadata returns response metadata as Connect sends it: gRPC
+// protocol keys are dropped and binary values are base64 encoded
+func encodeMetadata(md metadata.MD) map[string][]string {
+	encoded := make(map[string][]string, len(md))
+	for key, values := range md {
+		if key == "content-type" || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			encoded[key] = append(encoded[key], v)
+		}
+	}
+	return encoded
+}
+
+// setMetadataHeaders writes metadata to HTTP headers, prefixing each name
+func setMetadataHeaders(h http.Header, md metadata.MD, prefix string) {
+	for key, values := range encodeMetadata(md) {
+		for _, v := range values {
+			h.Add(prefix+key, v)
+		}
+	}
+}
+
+// connectTransportStream gives proxyHandler the method of a Connect call
+type connectTransportStream struct {
+	method string
+	stream *connectStream
+}
+
+func (t *connectTransportStream) Method() string { return t.method }
+
+func (t *connectTransportStream) SetHeader(md metadata.MD) error { return t.stream.SetHeader(md) }
+
+func (t *connectTransportStream) SendHeader(md metadata.MD) error { return t.stream.SendHeader(md) }
+
+func (t *connectTransportStream) SetTrailer(md metadata.MD) error {
+	t.stream.SetTrailer(md)
+	return nil
+}
+
+// connectStream adapts a Connect request to grpc.ServerStream. Unary
+// responses are held until the call ends since the HTTP status depends on
+// the outcome; streaming responses are written as they arrive.
+type connectStream struct {
+	ctx       context.Context
+	w         http.ResponseWriter
+	body      io.Reader
+	streaming bool
+	maxBytes  int
+
+	received bool // unary: the request message was read
+
+	mu          sync.Mutex
+	header      metadata.MD
+	trailer     metadata.MD
+	wroteHeader bool
+	response    []byte
+	hasResponse bool
+}
+
+func (s *connectStream) Context() context.Context { return s.ctx }
+
+func (s *connectStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.wroteHeader {
+		return status.Error(codes.Internal, "headers already sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *connectStream) SendHeader(md metadata.MD) error {
+	if err := s.SetHeader(md); err != nil {
+		return err
+	}
+	if s.streaming {
+		s.mu.Lock()
+		s.writeHeader(http.StatusOK, connectStreamingType)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *connectStream) SetTrailer(md metadata.MD) {
+	s.mu.Lock()
+	s.trailer = metadata.Join(s.trailer, md)
+	s.mu.Unlock()
+}
+
+func (s *connectStream) SendMsg(m interface{}) error {
+	f, ok := m.(*frame)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected message type %T", m)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.streaming {
+		if s.hasResponse {
+			return status.Error(codes.Unimplemented, "backend sent more than one response to a unary call")
+		}
+		s.response = append([]byte(nil), f.payload...)
+		s.hasResponse = true
+		return nil
+	}
+
+	s.writeHeader(http.StatusOK, connectStreamingType)
+	if err := s.writeEnvelope(0, f.payload); err != nil {
+		return status.Errorf(codes.Canceled, "failed to write response: %v", err)
+	}
+	return nil
+}
+
+func (s *connectStream) RecvMsg(m interface{}) error {
+	f, ok := m.(*frame)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected message type %T", m)
+	}
+
+	if !s.streaming {
+		// The whole body is the request message
+		if s.received {
+			return io.EOF
+		}
+		s.received = true
+		data, err := io.ReadAll(io.LimitReader(s.body, int64(s.maxBytes)+1))
+		if err != nil {
+			return status.Errorf(codes.Canceled, "failed to read request: %v", err)
+		}
+		if len(data) > s.maxBytes {
+			return status.Errorf(codes.ResourceExhausted, "request larger than %d bytes", s.maxBytes)
+		}
+		f.payload = data
+		return nil
+	}
+
+	var prefix [5]byte
+	if _, err := io.ReadFull(s.body, prefix[:]); err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return status.Errorf(codes.InvalidArgument, "failed to read message: %v", err)
+	}
+	if prefix[0]&(connectFlagCompressed|connectFlagEndStream) != 0 {
+		return status.Errorf(codes.InvalidArgument, "unexpected message flags 0x%02x", prefix[0])
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if int64(size) > int64(s.maxBytes) {
+		return status.Errorf(codes.ResourceExhausted, "message larger than %d bytes", s.maxBytes)
+	}
+	f.payload = make([]byte, size)
+	if _, err := io.ReadFull(s.body, f.payload); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to read message: %v", err)
+	}
+	return nil
+}
+
+// finish writes the outcome of the call to the client
+func (s *connectStream) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.streaming {
+		if err == nil && !s.hasResponse {
+			err = status.Error(codes.Unimplemented, "backend sent no response to a unary call")
+		}
+		h := s.w.Header()
+		setMetadataHeaders(h, s.trailer, "Trailer-")
+		if err == nil {
+			h.Set("Content-Length", strconv.Itoa(len(s.response)))
+			s.writeHeader(http.StatusOK, connectUnaryType)
+			s.w.Write(s.response)
+			return
+		}
+		body, _ := json.Marshal(newConnectError(err))
+		s.writeHeader(connectHTTPStatus(status.Code(err)), "application/json")
+		s.w.Write(body)
+		return
+	}
+
+	end := connectEndStream{Metadata: encodeMetadata(s.trailer)}
+	if err != nil {
+		end.Error = newConnectError(err)
+	}
+	body, _ := json.Marshal(end)
+
+	s.writeHeader(http.StatusOK, connectStreamingType)
+	s.writeEnvelope(connectFlagEndStream, body)
+}
+
+// writeHeader writes the response headers once; s.mu must be held
+func (s *connectStream) writeHeader(statusCode int, contentType string) {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+
+	h := s.w.Header()
+	setMetadataHeaders(h, s.header, "")
+	h.Set("Content-Type", contentType)
+	exposeHeaders(h)
+	s.w.WriteHeader(statusCode)
+}
+
+// writeEnvelope writes and flushes one streaming message; s.mu must be held
+func (s *connectStream) writeEnvelope(flags byte, payload []byte) error {
+	var prefix [5]byte
+	prefix[0] = flags
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(payload)))
+	if _, err := s.w.Write(prefix[:]); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(payload); err != nil {
+		return err
+	}
+	return http.NewResponseController(s.w).Flush()
+}
+
+// newConnectError converts a gRPC status error to its Connect form
+func newConnectError(err error) *connectError {
+	st := status.Convert(err)
+	ce := &connectError{Code: connectCodes[st.Code()].name, Message: st.Message()}
+	if ce.Code == "" {
+		ce.Code = "unknown"
+	}
+	for _, d := range st.Proto().GetDetails() {
+		ce.Details = append(ce.Details, connectErrorDetail{
+			Type:  strings.TrimPrefix(d.GetTypeUrl(), "type.googleapis.com/"),
+			Value: base64.RawStdEncoding.EncodeToString(d.GetValue()),
+		})
+	}
+	return ce
+}
+
+// connectHTTPStatus returns the HTTP status of a unary Connect error
+func connectHTTPStatus(code codes.Code) int {
+	if c, ok := connectCodes[code]; ok {
+		return c.httpStatus
+	}
+	return http.StatusInternalServerError
+}
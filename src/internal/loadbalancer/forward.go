@@ -0,0 +1,135 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// frame is an opaque gRPC message passed through the balancer undecoded
+type frame struct {
+	payload []byte
+}
+
+// frameCodec passes message bytes through unchanged so the balancer never
+// needs the backend's protobuf definitions. It keeps the "proto" name so the
+// content-type seen by clients and backends is unchanged.
+type frameCodec struct{}
+
+func (frameCodec) Marshal(v interface{}) ([]byte, error) {
+	f, ok := v.(*frame)
+	if !ok {
+		return nil, fmt.Errorf("frameCodec: unexpected message type %T", v)
+	}
+	return f.payload, nil
+}
+
+func (frameCodec) Unmarshal(data []byte, v interface{}) error {
+	f, ok := v.(*frame)
+	if !ok {
+		return fmt.Errorf("frameCodec: unexpected message type %T", v)
+	}
+	f.payload = append(f.payload[:0], data...)
+	return nil
+}
+
+func (frameCodec) Name() string { return "proto" }
+
+// proxyStreamDesc describes a proxied call; every call is treated as
+// bidirectional streaming since the balancer does not know the method type
+var proxyStreamDesc = &grpc.StreamDesc{
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// forwardClientToServer copies messages from the incoming call to the
+// backend until the client half-closes (io.EOF) or an error occurs
+func forwardClientToServer(src grpc.ServerStream, dst grpc.ClientStream) chan error {
+	ret := make(chan error, 1)
+	go func() {
+		f := &frame{}
+		for {
+			if err := src.RecvMsg(f); err != nil {
+				ret <- err
+				return
+			}
+			if err := dst.SendMsg(f); err != nil {
+				ret <- err
+				return
+			}
+		}
+	}()
+	return ret
+}
+
+// forwardServerToClient copies the backend's headers and messages to the
+// incoming call until the backend finishes (io.EOF) or an error occurs
+func forwardServerToClient(src grpc.ClientStream, dst grpc.ServerStream) chan error {
+	ret := make(chan error, 1)
+	go func() {
+		f := &frame{}
+		for i := 0; ; i++ {
+			if err := src.RecvMsg(f); err != nil {
+				if i == 0 {
+					// No messages: headers go out with the trailers
+					if md, herr := src.Header(); herr == nil && len(md) > 0 {
+						dst.SetHeader(md)
+					}
+				}
+				ret <- err
+				return
+			}
+			if i == 0 {
+				// Headers are available once the first message arrives
+				md, err := src.Header()
+				if err != nil {
+					ret <- err
+					return
+				}
+				if err := dst.SendHeader(md); err != nil {
+					ret <- err
+					return
+				}
+			}
+			if err := dst.SendMsg(f); err != nil {
+				ret <- err
+				return
+			}
+		}
+	}()
+	return ret
+}
+
+// forward runs both directions of a proxied call and returns the backend's
+// final status
+func forward(src grpc.ServerStream, dst grpc.ClientStream, cancel func()) error {
+	c2s := forwardClientToServer(src, dst)
+	s2c := forwardServerToClient(dst, src)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-c2s:
+			if err != io.EOF {
+				// The client went away or sent something we could not forward
+				cancel()
+				if _, ok := status.FromError(err); ok {
+					return err
+				}
+				return status.Errorf(codes.Internal, "failed to forward request: %v", err)
+			}
+			// Client finished sending; let the backend know
+			dst.CloseSend()
+		case err := <-s2c:
+			// The backend's trailers are final whether or not it succeeded
+			src.SetTrailer(dst.Trailer())
+			if err != io.EOF {
+				return err
+			}
+			return nil
+		}
+	}
+	return status.Error(codes.Internal, "proxied call ended unexpectedly")
+}
@@ -0,0 +1,135 @@
+package loadbalancer
+
+import (
+	"context"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// ringReplicas is the number of points each backend owns on the hash ring.
+	// More points spread keys more evenly at the cost of a larger ring.
+	ringReplicas = 160
+
+	// maxCachedRings bounds the rings kept per route (one per backend set)
+	maxCachedRings = 8
+)
+
+// hashRing is a consistent hash ring over backend instance IDs. Adding or
+// removing a backend only remaps the keys that land on its points.
+type hashRing struct {
+	points []ringPoint // sorted by hash
+}
+
+type ringPoint struct {
+	hash uint64
+	id   string
+}
+
+// newHashRing builds a ring for the given instance IDs
+func newHashRing(ids []string) *hashRing {
+	r := &hashRing{points: make([]ringPoint, 0, len(ids)*ringReplicas)}
+	for _, id := range ids {
+		for i := 0; i < ringReplicas; i++ {
+			r.points = append(r.points, ringPoint{hash: hashString(id + "#" + strconv.Itoa(i)), id: id})
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i].hash < r.points[j].hash })
+	return r
+}
+
+// lookup returns the instance ID owning the given key hash
+func (r *hashRing) lookup(h uint64) string {
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].id
+}
+
+// hashString hashes a string with FNV-1a followed by a 64-bit finalizer, since
+// FNV alone clusters similar inputs such as "id#1", "id#2"
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// hashKey returns the routing key of a call: the configured metadata value
+// (a request header in HTTP mode), or the client's IP address when the key is
+// not configured or not sent
+func (r *routeHandler) hashKey(ctx context.Context) string {
+	return clientKey(ctx, r.hashMetadataKey)
+}
+
+// clientKey identifies the client of a call by the value of a metadata key
+// (a request header in HTTP mode), or by its IP address when key is empty or
+// not sent
+func clientKey(ctx context.Context, key string) string {
+	if req, ok := ctx.Value(httpRequestKey{}).(*http.Request); ok {
+		if key != "" {
+			if v := req.Header.Get(key); v != "" {
+				return v
+			}
+		}
+		if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+			return host
+		}
+		return req.RemoteAddr
+	}
+
+	if key != "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(key); len(values) > 0 && values[0] != "" {
+				return values[0]
+			}
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return host
+		}
+		return addr
+	}
+	return ""
+}
+
+// pickHashed returns the backend owning the key on the ring built from backends
+func (r *routeHandler) pickHashed(backends []*backend, h uint64) *backend {
+	ids := make([]string, len(backends))
+	byID := make(map[string]*backend, len(backends))
+	for i, b := range backends {
+		ids[i] = b.instanceID
+		byID[b.instanceID] = b
+	}
+	sort.Strings(ids)
+	members := strings.Join(ids, ",")
+
+	r.ringsMu.Lock()
+	ring, ok := r.rings[members]
+	if !ok {
+		if len(r.rings) >= maxCachedRings {
+			r.rings = make(map[string]*hashRing)
+		}
+		ring = newHashRing(ids)
+		r.rings[members] = ring
+	}
+	r.ringsMu.Unlock()
+
+	return byID[ring.lookup(h)]
+}
@@ -0,0 +1,51 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHashRingMemberChange(t *testing.T) {
+	const keys = 20000
+	members := []string{"api-1", "api-2", "api-3", "api-4", "api-5"}
+	before := newHashRing(members)
+
+	owners := make([]string, keys)
+	for i := range owners {
+		owners[i] = before.lookup(hashString(fmt.Sprintf("session-%d", i)))
+	}
+
+	tests := []struct {
+		name    string
+		members []string
+		changed string // member whose keys may move
+		share   float64
+	}{
+		{"added", append(members[:5:5], "api-6"), "api-6", 1.0 / 6},
+		{"removed", []string{"api-1", "api-2", "api-4", "api-5"}, "api-3", 1.0 / 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := newHashRing(tt.members)
+
+			moved := 0
+			for i, owner := range owners {
+				now := after.lookup(hashString(fmt.Sprintf("session-%d", i)))
+				if now == owner {
+					continue
+				}
+				moved++
+				// Only keys of the changed member move
+				if now != tt.changed && owner != tt.changed {
+					t.Fatalf("key %d moved from %s to %s", i, owner, now)
+				}
+			}
+
+			share := float64(moved) / keys
+			if share < tt.share*0.7 || share > tt.share*1.3 {
+				t.Errorf("%.1f%% of keys moved, want about %.1f%%", share*100, tt.share*100)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package loadbalancer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// httpCall carries the state of one proxied HTTP request through the reverse proxy
+type httpCall struct {
+	route      *routeHandler
+	backend    *backend
+	statusCode int
+	err        error
+}
+
+type httpCallKey struct{}
+
+// newHTTPTransport creates the transport shared by all requests to backends;
+// it keeps idle connections to each backend for reuse. A nil tlsConfig
+// connects without TLS.
+func newHTTPTransport(http2 bool, tlsConfig *tls.Config) *http.Transport {
+	t := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        256,
+		MaxIdleConnsPerHost: 64,
+		IdleConnTimeout:     90 * time.Second,
+		TLSClientConfig:     tlsConfig,
+	}
+	t.Protocols = new(http.Protocols)
+	switch {
+	case tlsConfig != nil:
+		t.Protocols.SetHTTP1(true)
+		t.Protocols.SetHTTP2(http2)
+	case http2:
+		t.Protocols.SetUnencryptedHTTP2(true)
+	default:
+		t.Protocols.SetHTTP1(true)
+	}
+	return t
+}
+
+// newHTTPServer creates the listener-side server for HTTP mode. It accepts
+// HTTP/1.1 and HTTP/2, negotiated over TLS or without it (h2c).
+func (lb *Balancer) newHTTPServer(tlsConfig *tls.Config) *http.Server {
+	lb.httpProxy = &httputil.ReverseProxy{
+		Rewrite:        lb.rewriteRequest,
+		Transport:      lb.transport,
+		ModifyResponse: lb.modifyResponse,
+		ErrorHandler:   lb.proxyError,
+	}
+
+	srv := &http.Server{
+		Handler:           http.HandlerFunc(lb.serveHTTP),
+		ReadHeaderTimeout: 30 * time.Second,
+		Protocols:         new(http.Protocols),
+		TLSConfig:         tlsConfig,
+	}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP2(true)
+	srv.Protocols.SetUnencryptedHTTP2(true)
+	return srv
+}
+
+// serveHTTP handles all incoming HTTP requests
+func (lb *Balancer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	route := lb.findHTTPRoute(r)
+	if route == nil {
+		lbRequests.Inc(lb.config.Name, "", "", strconv.Itoa(http.StatusNotFound))
+		http.Error(w, "no route found", http.StatusNotFound)
+		lb.logHTTPCall(r, nil, nil, 0, 0, http.StatusNotFound, start)
+		return
+	}
+
+	release, retryAfter, err := lb.admit(withHTTPRequest(r.Context(), r), route)
+	if err != nil {
+		w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+		http.Error(w, status.Convert(err).Message(), http.StatusTooManyRequests)
+		lb.observeHTTP(route, "", start, http.StatusTooManyRequests)
+		lb.logHTTPCall(r, route, nil, 0, 0, http.StatusTooManyRequests, start)
+		return
+	}
+	defer release()
+
+	// Keep target processes from being stopped by their idle timeout while
+	// the request (or upgraded connection) is open
+	defer lb.beginCalls(route)()
+
+	// Upgraded connections (WebSocket) live as long as the client keeps them
+	ctx := r.Context()
+	if route.timeout > 0 && !isUpgrade(r) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, route.timeout)
+		defer cancel()
+	}
+
+	selected, err := lb.pickBackend(withHTTPRequest(ctx, r), route, nil)
+	if err != nil {
+		lb.observeHTTP(route, "", start, http.StatusServiceUnavailable)
+		http.Error(w, status.Convert(err).Message(), http.StatusServiceUnavailable)
+		lb.logHTTPCall(r, route, nil, 0, 0, http.StatusServiceUnavailable, start)
+		return
+	}
+	route.countTarget(lb.config.Name, selected.process)
+
+	// Count the bytes of both bodies for the access log
+	body, cw := &countingBody{ReadCloser: r.Body}, &countingResponseWriter{ResponseWriter: w}
+	if lb.accessLog != nil {
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		w = cw
+	}
+
+	call := &httpCall{route: route, backend: selected}
+	lb.httpProxy.ServeHTTP(w, r.WithContext(context.WithValue(ctx, httpCallKey{}, call)))
+
+	lb.done(selected, start, httpOutcome(ctx, call))
+	lb.observeHTTP(route, selected.addr, start, call.statusCode)
+	lb.logHTTPCall(r, route, selected, body.n, cw.n, call.statusCode, start)
+}
+
+// rewriteRequest points the outgoing request at the selected backend
+func (lb *Balancer) rewriteRequest(pr *httputil.ProxyRequest) {
+	call := pr.In.Context().Value(httpCallKey{}).(*httpCall)
+
+	scheme := "http"
+	if lb.backendTLS != nil {
+		scheme = "https"
+	}
+	pr.SetURL(&url.URL{Scheme: scheme, Host: call.backend.addr})
+	if call.route.stripPrefix && call.route.pathPrefix != "" {
+		pr.Out.URL.Path = "/" + strings.TrimLeft(strings.TrimPrefix(pr.In.URL.Path, call.route.pathPrefix), "/")
+		pr.Out.URL.RawPath = ""
+	}
+	pr.SetXForwarded()
+	call.route.requestHeaders.apply(pr.Out.Header)
+	pr.Out.Header.Set(routeHeader, call.route.name)
+}
+
+// modifyResponse applies the route's response header rewrites
+func (lb *Balancer) modifyResponse(resp *http.Response) error {
+	call := resp.Request.Context().Value(httpCallKey{}).(*httpCall)
+	call.statusCode = resp.StatusCode
+	call.route.responseHeaders.apply(resp.Header)
+	return nil
+}
+
+// proxyError answers requests that could not be forwarded to the backend
+func (lb *Balancer) proxyError(w http.ResponseWriter, r *http.Request, err error) {
+	call := r.Context().Value(httpCallKey{}).(*httpCall)
+	call.err = err
+
+	call.statusCode = http.StatusBadGateway
+	if errors.Is(err, context.DeadlineExceeded) {
+		call.statusCode = http.StatusGatewayTimeout
+	}
+	if !errors.Is(err, context.Canceled) {
+		log.Printf("Load balancer %q: proxy to %s failed: %v", lb.config.Name, call.backend.addr, err)
+	}
+	w.WriteHeader(call.statusCode)
+}
+
+// httpOutcome maps the result of a proxied request to the status used by
+// latency tracking and outlier detection
+func httpOutcome(ctx context.Context, call *httpCall) error {
+	switch {
+	case call.err != nil && errors.Is(call.err, context.Canceled) && ctx.Err() != context.DeadlineExceeded:
+		return status.Error(codes.Canceled, call.err.Error())
+	case call.statusCode == http.StatusGatewayTimeout:
+		return status.Error(codes.DeadlineExceeded, "gateway timeout")
+	case call.err != nil, call.statusCode == http.StatusBadGateway, call.statusCode == http.StatusServiceUnavailable:
+		return status.Errorf(codes.Unavailable, "backend returned %d", call.statusCode)
+	case call.statusCode >= 500:
+		return status.Errorf(codes.Internal, "backend returned %d", call.statusCode)
+	}
+	return nil
+}
+
+// observeHTTP records request metrics for a proxied HTTP request
+func (lb *Balancer) observeHTTP(route *routeHandler, backend string, start time.Time, statusCode int) {
+	code := strconv.Itoa(statusCode)
+	lbRequests.Inc(lb.config.Name, route.name, backend, code)
+	if backend != "" {
+		lbRequestDuration.Observe(time.Since(start).Seconds(), lb.config.Name, route.name, backend)
+	}
+	if statusCode >= 500 {
+		lbRequestErrors.Inc(lb.config.Name, route.name, backend, code)
+	}
+}
+
+// findHTTPRoute finds the first route matching the request's host, path and
+// headers, or the default route if none does
+func (lb *Balancer) findHTTPRoute(r *http.Request) *routeHandler {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	for _, route := range lb.routes {
+		if route == lb.defaultRoute {
+			continue
+		}
+		if route.matchesHost(host) && strings.HasPrefix(r.URL.Path, route.pathPrefix) &&
+			route.matchesHeaders(r.Header.Values) {
+			return route
+		}
+	}
+	return lb.defaultRoute
+}
+
+// matchesHost reports whether the route accepts the given host name
+func (r *routeHandler) matchesHost(host string) bool {
+	if len(r.hosts) == 0 {
+		return true
+	}
+	for _, h := range r.hosts {
+		if h == host {
+			return true
+		}
+		if strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUpgrade reports whether the request asks to switch protocols (e.g. WebSocket)
+func isUpgrade(r *http.Request) bool {
+	for _, v := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headerRewrite is a compiled models.HeaderRewrite
+type headerRewrite models.HeaderRewrite
+
+// apply modifies headers in place; a nil rewrite does nothing
+func (h *headerRewrite) apply(header http.Header) {
+	if h == nil {
+		return
+	}
+	for _, name := range h.Remove {
+		header.Del(name)
+	}
+	for name, value := range h.Set {
+		header.Set(name, value)
+	}
+	for name, value := range h.Add {
+		header.Add(name, value)
+	}
+}
+
+// compileHTTPRoute sets the HTTP matching and response rewriting options of a route
+func compileHTTPRoute(handler *routeHandler, route models.LoadBalancerRoute) error {
+	if route.Retry != nil || route.Hedge != nil || route.Mirror != nil {
+		return fmt.Errorf("retry, hedge and mirror are only supported for grpc load balancers")
+	}
+	if route.PathPrefix != "" && !strings.HasPrefix(route.PathPrefix, "/") {
+		return fmt.Errorf("path_prefix must start with /")
+	}
+
+	for _, h := range route.Hosts {
+		handler.hosts = append(handler.hosts, strings.ToLower(h))
+	}
+	handler.pathPrefix = route.PathPrefix
+	handler.stripPrefix = route.StripPrefix
+	handler.responseHeaders = (*headerRewrite)(route.ResponseHeaders)
+	return nil
+}
+
+// httpRequestKey carries the incoming HTTP request for hash key extraction
+type httpRequestKey struct{}
+
+func withHTTPRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, httpRequestKey{}, r)
+}
@@ -3,8 +3,11 @@ package loadbalancer
 import (
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/accesslog"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/certs"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
 )
@@ -35,6 +38,25 @@ func NewManager(configs []models.LoadBalancerConfig, procMgr *process.Manager) (
 	return mgr, nil
 }
 
+// SetCertManager sets the certificate manager of all load balancers; call
+// before Start
+func (m *Manager) SetCertManager(cm *certs.Manager) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, lb := range m.balancers {
+		lb.SetCertManager(cm)
+	}
+}
+
+// SetAccessLogger sets the access logger of all load balancers
+func (m *Manager) SetAccessLogger(l *accesslog.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, lb := range m.balancers {
+		lb.SetAccessLogger(l)
+	}
+}
+
 // Start starts all load balancers
 func (m *Manager) Start() error {
 	m.mu.Lock()
@@ -82,6 +104,32 @@ func (m *Manager) GetBalancer(name string) (*Balancer, error) {
 	return lb, nil
 }
 
+// Statuses returns the status of every load balancer, sorted by name
+func (m *Manager) Statuses() []BalancerStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]BalancerStatus, 0, len(m.balancers))
+	for _, lb := range m.balancers {
+		statuses = append(statuses, lb.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// InstanceCalls returns the calls all load balancers proxied to each instance
+// of a process since start or their last stats reset, by instance ID
+func (m *Manager) InstanceCalls(processName string) map[string]InstanceCalls {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	calls := make(map[string]InstanceCalls)
+	for _, lb := range m.balancers {
+		lb.addInstanceCalls(processName, calls)
+	}
+	return calls
+}
+
 // ListBalancers returns names of all load balancers
 func (m *Manager) ListBalancers() []string {
 	m.mu.RLock()
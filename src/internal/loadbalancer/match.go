@@ -0,0 +1,121 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// routeHeader is set on every forwarded call to the name of the route that
+// matched it, so backends can tell which route was applied
+const routeHeader = "x-gowinproc-route"
+
+// headerMatcher is a compiled models.HeaderMatch
+type headerMatcher struct {
+	name    string
+	exact   string
+	prefix  string
+	re      *regexp.Regexp
+	present *bool
+}
+
+// compileHeaderMatchers validates and compiles a route's header conditions
+func compileHeaderMatchers(matches []models.HeaderMatch) ([]headerMatcher, error) {
+	matchers := make([]headerMatcher, 0, len(matches))
+	for _, m := range matches {
+		if m.Name == "" {
+			return nil, fmt.Errorf("header match without a name")
+		}
+
+		set := 0
+		for _, v := range []string{m.Exact, m.Prefix, m.Regex} {
+			if v != "" {
+				set++
+			}
+		}
+		if m.Present != nil {
+			set++
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("header match %q must set exactly one of exact, prefix, regex, present", m.Name)
+		}
+
+		hm := headerMatcher{
+			name:    strings.ToLower(m.Name),
+			exact:   m.Exact,
+			prefix:  m.Prefix,
+			present: m.Present,
+		}
+		if m.Regex != "" {
+			re, err := regexp.Compile(m.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex for header %q: %w", m.Name, err)
+			}
+			hm.re = re
+		}
+		matchers = append(matchers, hm)
+	}
+	return matchers, nil
+}
+
+// matches reports whether the values sent for the header satisfy the condition
+func (m headerMatcher) matches(values []string) bool {
+	if m.present != nil {
+		return (len(values) > 0) == *m.present
+	}
+	for _, v := range values {
+		switch {
+		case m.exact != "" && v == m.exact,
+			m.prefix != "" && strings.HasPrefix(v, m.prefix),
+			m.re != nil && m.re.MatchString(v):
+			return true
+		}
+	}
+	return false
+}
+
+// matchesHeaders reports whether all header conditions of the route hold.
+// get returns the values sent for a (lower case) header name.
+func (r *routeHandler) matchesHeaders(get func(name string) []string) bool {
+	for _, m := range r.headers {
+		if !m.matches(get(m.name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesMethod reports whether the route accepts the gRPC method; a route
+// without method patterns accepts all methods
+func (r *routeHandler) matchesMethod(method string) bool {
+	if len(r.methodPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range r.methodPatterns {
+		if pattern.MatchString(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMetadata applies the rewrite to outgoing gRPC metadata; a nil rewrite
+// does nothing
+func (h *headerRewrite) applyMetadata(md metadata.MD) {
+	if h == nil {
+		return
+	}
+	for _, name := range h.Remove {
+		delete(md, strings.ToLower(name))
+	}
+	for name, value := range h.Set {
+		md.Set(name, value)
+	}
+	for name, value := range h.Add {
+		md.Append(name, value)
+	}
+}
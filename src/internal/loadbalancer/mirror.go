@@ -0,0 +1,334 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+const (
+	// maxMirrorInFlight bounds the shadow calls of a route so a slow shadow
+	// cannot pile up goroutines; calls beyond it are skipped
+	maxMirrorInFlight = 64
+
+	// mirrorSamples is the number of recent latency pairs kept for percentiles
+	mirrorSamples = 1024
+
+	// mirrorHeader is set on shadow calls so backends can tell them apart
+	mirrorHeader = "x-gowinproc-mirror"
+)
+
+var (
+	lbMirrorCalls = metrics.NewCounterVec("gowinproc_lb_mirror_calls_total",
+		"Calls mirrored to a shadow process by primary and shadow status", "balancer", "route", "primary_code", "shadow_code")
+	lbMirrorSkipped = metrics.NewCounterVec("gowinproc_lb_mirror_skipped_total",
+		"Sampled calls that were not mirrored", "balancer", "route", "reason")
+	lbMirrorLatency = metrics.NewHistogramVec("gowinproc_lb_mirror_latency_seconds",
+		"Latency of mirrored calls on the primary and the shadow", nil, "balancer", "route", "side")
+)
+
+// errNoShadowBackend is returned by callShadow when the shadow process has no
+// running instance
+var errNoShadowBackend = errors.New("shadow process has no running instance")
+
+// mirror is the compiled mirror policy of a route and its comparison results
+type mirror struct {
+	target         string
+	percent        float64
+	timeout        time.Duration
+	maxBufferBytes int
+	inFlight       int64 // atomic
+
+	mu         sync.Mutex
+	mirrored   uint64
+	matched    uint64
+	skipped    map[string]uint64
+	codes      map[string]uint64 // "PRIMARY -> SHADOW"
+	primarySum time.Duration
+	shadowSum  time.Duration
+	samples    []latencyPair // ring of recent calls
+	nextSample int
+}
+
+type latencyPair struct {
+	primary, shadow time.Duration
+}
+
+// MirrorReport compares the primary and shadow results of a route's mirrored calls
+type MirrorReport struct {
+	Route          string            `json:"route"`
+	Target         string            `json:"target"`
+	Percent        float64           `json:"percent"`
+	Mirrored       uint64            `json:"mirrored"`
+	Matched        uint64            `json:"matched"`    // shadow returned the same status code
+	Mismatched     uint64            `json:"mismatched"` // shadow returned a different status code
+	Skipped        map[string]uint64 `json:"skipped"`    // sampled calls not mirrored, by reason
+	Codes          map[string]uint64 `json:"codes"`      // "PRIMARY -> SHADOW" status code pairs
+	PrimaryLatency LatencySummary    `json:"primary_latency"`
+	ShadowLatency  LatencySummary    `json:"shadow_latency"`
+}
+
+// LatencySummary summarizes latencies; percentiles cover recent calls only
+type LatencySummary struct {
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+}
+
+// compileMirror validates a route's mirror policy. Returns nil if not configured.
+func compileMirror(policy *models.MirrorPolicy) (*mirror, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	if policy.TargetProcess == "" {
+		return nil, fmt.Errorf("mirror.target_process is required")
+	}
+	if policy.Percent < 0 || policy.Percent > 100 {
+		return nil, fmt.Errorf("mirror.percent must be between 0 and 100")
+	}
+	return &mirror{
+		target:         policy.TargetProcess,
+		percent:        policy.Percent,
+		timeout:        policy.Timeout,
+		maxBufferBytes: policy.MaxBufferBytes,
+		skipped:        make(map[string]uint64),
+		codes:          make(map[string]uint64),
+	}, nil
+}
+
+// mirrorTap records the request messages of a call as the proxy reads them
+type mirrorTap struct {
+	grpc.ServerStream
+	limit int
+
+	mu       sync.Mutex
+	payload  []byte
+	count    int
+	tooLarge bool
+	eof      bool
+}
+
+func (t *mirrorTap) RecvMsg(m interface{}) error {
+	err := t.ServerStream.RecvMsg(m)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case err == io.EOF:
+		t.eof = true
+	case err == nil:
+		t.count++
+		if f, ok := m.(*frame); ok && t.count == 1 {
+			if len(f.payload) > t.limit {
+				t.tooLarge = true
+			} else {
+				t.payload = append([]byte(nil), f.payload...)
+			}
+		}
+	}
+	return err
+}
+
+// request returns the request message of a unary call, or the reason the
+// call cannot be mirrored
+func (t *mirrorTap) request() ([]byte, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case !t.eof || t.count != 1:
+		return nil, "not_unary"
+	case t.tooLarge:
+		return nil, "too_large"
+	}
+	return t.payload, ""
+}
+
+// tapMirror wraps the stream of a call sampled for mirroring; otherwise it
+// returns the stream unchanged and a nil tap
+func (lb *Balancer) tapMirror(route *routeHandler, stream grpc.ServerStream) (grpc.ServerStream, *mirrorTap) {
+	m := route.mirror
+	if m == nil || rand.Float64()*100 >= m.percent {
+		return stream, nil
+	}
+	tap := &mirrorTap{ServerStream: stream, limit: m.maxBufferBytes}
+	return tap, tap
+}
+
+// mirrorCall sends the request captured by tap to the shadow process in the
+// background and compares the result with the primary's
+func (lb *Balancer) mirrorCall(route *routeHandler, method string, tap *mirrorTap, primaryLatency time.Duration, primaryErr error) {
+	m := route.mirror
+
+	payload, reason := tap.request()
+	if reason == "" && status.Code(primaryErr) == codes.Canceled {
+		reason = "canceled"
+	}
+	if reason == "" {
+		if atomic.AddInt64(&m.inFlight, 1) > maxMirrorInFlight {
+			atomic.AddInt64(&m.inFlight, -1)
+			reason = "overloaded"
+		}
+	}
+	if reason != "" {
+		lb.skipMirror(route, reason)
+		return
+	}
+
+	md := route.outgoingMetadata(tap.Context())
+	md.Set(mirrorHeader, "true")
+
+	go func() {
+		defer atomic.AddInt64(&m.inFlight, -1)
+
+		shadowLatency, shadowErr := lb.callShadow(m, method, md, payload)
+		if shadowErr == errNoShadowBackend {
+			lb.skipMirror(route, "no_backend")
+			return
+		}
+
+		primaryCode, shadowCode := status.Code(primaryErr), status.Code(shadowErr)
+		lbMirrorCalls.Inc(lb.config.Name, route.name, primaryCode.String(), shadowCode.String())
+		lbMirrorLatency.Observe(primaryLatency.Seconds(), lb.config.Name, route.name, "primary")
+		lbMirrorLatency.Observe(shadowLatency.Seconds(), lb.config.Name, route.name, "shadow")
+		m.record(primaryCode, shadowCode, primaryLatency, shadowLatency)
+	}()
+}
+
+// callShadow makes a unary call on a backend of the shadow process, discarding
+// the response, and returns its latency and status
+func (lb *Balancer) callShadow(m *mirror, method string, md metadata.MD, payload []byte) (time.Duration, error) {
+	backends := lb.getHealthyBackends([]string{m.target})
+	if len(backends) == 0 {
+		return 0, errNoShadowBackend
+	}
+	b := backends[rand.IntN(len(backends))]
+
+	// Shadow calls outlive the client's call, so they get their own deadline
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), md), m.timeout)
+	defer cancel()
+
+	b.acquire()
+	start := time.Now()
+	cs, err := b.conn.NewStream(ctx, proxyStreamDesc, method)
+	if err == nil {
+		// A failed send surfaces as the status returned by RecvMsg
+		cs.SendMsg(&frame{payload: payload})
+		cs.CloseSend()
+		f := &frame{}
+		for err == nil {
+			err = cs.RecvMsg(f)
+		}
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	latency := time.Since(start)
+	b.release(latency, status.Code(err))
+	return latency, err
+}
+
+// skipMirror records a sampled call that was not mirrored
+func (lb *Balancer) skipMirror(route *routeHandler, reason string) {
+	lbMirrorSkipped.Inc(lb.config.Name, route.name, reason)
+	route.mirror.mu.Lock()
+	route.mirror.skipped[reason]++
+	route.mirror.mu.Unlock()
+}
+
+// record adds the comparison of one mirrored call
+func (m *mirror) record(primaryCode, shadowCode codes.Code, primary, shadow time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mirrored++
+	if primaryCode == shadowCode {
+		m.matched++
+	}
+	m.codes[primaryCode.String()+" -> "+shadowCode.String()]++
+	m.primarySum += primary
+	m.shadowSum += shadow
+
+	pair := latencyPair{primary: primary, shadow: shadow}
+	if len(m.samples) < mirrorSamples {
+		m.samples = append(m.samples, pair)
+	} else {
+		m.samples[m.nextSample] = pair
+		m.nextSample = (m.nextSample + 1) % mirrorSamples
+	}
+}
+
+// report returns the comparison results of the route's mirror
+func (m *mirror) report(route string) MirrorReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := MirrorReport{
+		Route:      route,
+		Target:     m.target,
+		Percent:    m.percent,
+		Mirrored:   m.mirrored,
+		Matched:    m.matched,
+		Mismatched: m.mirrored - m.matched,
+		Skipped:    make(map[string]uint64, len(m.skipped)),
+		Codes:      make(map[string]uint64, len(m.codes)),
+	}
+	for reason, n := range m.skipped {
+		r.Skipped[reason] = n
+	}
+	for pair, n := range m.codes {
+		r.Codes[pair] = n
+	}
+
+	primary := make([]time.Duration, len(m.samples))
+	shadow := make([]time.Duration, len(m.samples))
+	for i, s := range m.samples {
+		primary[i], shadow[i] = s.primary, s.shadow
+	}
+	r.PrimaryLatency = summarize(m.primarySum, m.mirrored, primary)
+	r.ShadowLatency = summarize(m.shadowSum, m.mirrored, shadow)
+	return r
+}
+
+// summarize computes the mean over all calls and percentiles over samples
+func summarize(sum time.Duration, n uint64, samples []time.Duration) LatencySummary {
+	var s LatencySummary
+	if n == 0 || len(samples) == 0 {
+		return s
+	}
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	at := func(q float64) float64 { return ms(samples[int(q*float64(len(samples)-1))]) }
+
+	s.MeanMs = ms(sum) / float64(n)
+	s.P50Ms = at(0.50)
+	s.P95Ms = at(0.95)
+	s.P99Ms = at(0.99)
+	return s
+}
+
+// MirrorReports returns the comparison results of every mirrored route
+func (lb *Balancer) MirrorReports() []MirrorReport {
+	var reports []MirrorReport
+	for _, route := range lb.routes {
+		if route.mirror != nil {
+			reports = append(reports, route.mirror.report(route.name))
+		}
+	}
+	return reports
+}
@@ -0,0 +1,163 @@
+package loadbalancer
+
+import (
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+)
+
+var (
+	lbEjections = metrics.NewCounterVec("gowinproc_lb_outlier_ejections_total",
+		"Backends ejected by outlier detection", "balancer", "backend", "reason")
+	lbBackendsEjected = metrics.NewGaugeVec("gowinproc_lb_backends_ejected",
+		"Backends currently ejected by outlier detection", "balancer")
+	lbBreakerRejections = metrics.NewCounterVec("gowinproc_lb_circuit_breaker_rejections_total",
+		"Calls rejected because every backend was at its concurrency limit", "balancer", "route")
+)
+
+// errCircuitOpen is returned by selectBackend when every candidate backend is
+// at its concurrency limit
+var errCircuitOpen = errors.New("all backends are at their concurrency limit")
+
+// backendFailure reports whether a status code indicates a failing backend
+// rather than a problem with the request
+func backendFailure(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.Internal, codes.Unknown, codes.DataLoss, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// tryAcquire marks the start of a proxied stream unless the backend already
+// has max streams in flight (max <= 0 means unlimited)
+func (b *backend) tryAcquire(max int64) bool {
+	if max <= 0 {
+		b.acquire()
+		return true
+	}
+	for {
+		n := atomic.LoadInt64(&b.inFlight)
+		if n >= max {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&b.inFlight, n, n+1) {
+			return true
+		}
+	}
+}
+
+// ejected reports whether the backend is ejected at the given time
+func (b *backend) ejected(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.ejectedUntil.After(now)
+}
+
+// maxConcurrent returns the per-backend concurrency limit (0 = unlimited)
+func (lb *Balancer) maxConcurrent() int64 {
+	if lb.config.CircuitBreaker == nil {
+		return 0
+	}
+	return int64(lb.config.CircuitBreaker.MaxConcurrentRequests)
+}
+
+// done ends a call on a backend acquired by pickBackend and feeds its outcome
+// into latency tracking and outlier detection
+func (lb *Balancer) done(b *backend, start time.Time, err error) {
+	code := status.Code(err)
+	b.release(time.Since(start), code)
+	if lb.config.OutlierDetection != nil && code != codes.Canceled {
+		lb.recordOutcome(b, backendFailure(code))
+	}
+}
+
+// recordOutcome updates a backend's failure counters and ejects it when
+// either threshold is reached
+func (lb *Balancer) recordOutcome(b *backend, failed bool) {
+	od := lb.config.OutlierDetection
+	now := time.Now()
+
+	b.mu.Lock()
+	if b.ejectedUntil.After(now) {
+		// Calls that were in flight when the backend was ejected
+		b.mu.Unlock()
+		return
+	}
+	if now.Sub(b.windowStart) >= od.Window {
+		b.windowStart, b.windowRequests, b.windowErrors = now, 0, 0
+	}
+	b.windowRequests++
+	if failed {
+		b.consecutiveErrors++
+		b.windowErrors++
+	} else {
+		b.consecutiveErrors = 0
+	}
+
+	reason := ""
+	switch {
+	case od.ConsecutiveErrors > 0 && b.consecutiveErrors >= od.ConsecutiveErrors:
+		reason = "consecutive_errors"
+	case od.ErrorRate > 0 && b.windowRequests >= od.MinRequests &&
+		float64(b.windowErrors)/float64(b.windowRequests) >= od.ErrorRate:
+		reason = "error_rate"
+	}
+	b.mu.Unlock()
+
+	if reason != "" {
+		lb.eject(b, reason, now)
+	}
+}
+
+// eject removes a backend from selection for a duration that grows with each
+// ejection, unless that would eject more than the allowed share of the
+// backends of its process
+func (lb *Balancer) eject(b *backend, reason string, now time.Time) {
+	od := lb.config.OutlierDetection
+
+	lb.backendsMu.Lock()
+	defer lb.backendsMu.Unlock()
+
+	pool, ejected := 0, 0
+	for _, other := range lb.backends {
+		if other.process != b.process {
+			continue
+		}
+		pool++
+		if other.ejected(now) {
+			ejected++
+		}
+	}
+	if (ejected+1)*100 > od.MaxEjectionPercent*pool {
+		return
+	}
+
+	b.mu.Lock()
+	if b.ejectedUntil.After(now) {
+		b.mu.Unlock()
+		return
+	}
+	// A backend that stayed healthy for a while starts over
+	if !b.ejectedUntil.IsZero() && now.Sub(b.ejectedUntil) > od.MaxEjectionTime {
+		b.ejections = 0
+	}
+	b.ejections++
+	duration := od.BaseEjectionTime * time.Duration(b.ejections)
+	if duration > od.MaxEjectionTime {
+		duration = od.MaxEjectionTime
+	}
+	b.ejectedUntil = now.Add(duration)
+	b.consecutiveErrors = 0
+	b.windowStart, b.windowRequests, b.windowErrors = now, 0, 0
+	b.mu.Unlock()
+
+	lbEjections.Inc(lb.config.Name, b.addr, reason)
+	log.Printf("Load balancer %q: ejected %s (%s) for %v (%s)", lb.config.Name, b.addr, b.process, duration, reason)
+}
@@ -0,0 +1,42 @@
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestEjectLimitPerProcess(t *testing.T) {
+	lb := &Balancer{
+		config: models.LoadBalancerConfig{
+			Name: "mixed",
+			OutlierDetection: &models.OutlierDetectionConfig{
+				BaseEjectionTime:   30 * time.Second,
+				MaxEjectionTime:    5 * time.Minute,
+				MaxEjectionPercent: 50,
+			},
+		},
+		backends: make(map[string]*backend),
+	}
+	add := func(process, id string) *backend {
+		b := &backend{addr: id, process: process, instanceID: id}
+		lb.backends[id] = b
+		return b
+	}
+	small1, small2 := add("small", "small-1"), add("small", "small-2")
+	for _, id := range []string{"big-1", "big-2", "big-3", "big-4", "big-5", "big-6"} {
+		add("big", id)
+	}
+
+	now := time.Now()
+	lb.eject(small1, "consecutive_errors", now)
+	lb.eject(small2, "consecutive_errors", now)
+
+	if !small1.ejected(now) {
+		t.Error("first backend of the process was not ejected")
+	}
+	if small2.ejected(now) {
+		t.Error("second backend ejected beyond 50% of its process, counting other processes' backends")
+	}
+}
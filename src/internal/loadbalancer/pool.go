@@ -0,0 +1,170 @@
+package loadbalancer
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+)
+
+const (
+	// poolSyncInterval is how often the pool is reconciled with running instances
+	poolSyncInterval = 2 * time.Second
+
+	// keepaliveTime matches grpc-go's default server enforcement (5m) so
+	// backends never answer our pings with a GOAWAY
+	keepaliveTime    = 5 * time.Minute
+	keepaliveTimeout = 20 * time.Second
+)
+
+var lbPoolConnections = metrics.NewGaugeVec("gowinproc_lb_pool_connections",
+	"Pooled backend connections by connectivity state", "balancer", "state")
+
+// poolStates are the connectivity states reported by lbPoolConnections
+var poolStates = []connectivity.State{connectivity.Idle, connectivity.Connecting,
+	connectivity.Ready, connectivity.TransientFailure}
+
+// dialBackend opens the shared connection for a backend. The connection is
+// established in the background; calls made before it is ready wait for it.
+// A nil tlsConfig connects without TLS.
+func dialBackend(addr string, tlsConfig *tls.Config) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(frameCodec{})),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    keepaliveTime,
+			Timeout: keepaliveTimeout,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection to %s: %w", addr, err)
+	}
+	conn.Connect()
+	return conn, nil
+}
+
+// state returns the connectivity state of the backend's pooled connection.
+// HTTP backends have no pooled connection and always report READY.
+func (b *backend) state() connectivity.State {
+	if b.conn == nil {
+		return connectivity.Ready
+	}
+	return b.conn.GetState()
+}
+
+// close closes the backend's pooled connection, if any
+func (b *backend) close() {
+	if b.conn != nil {
+		b.conn.Close()
+	}
+}
+
+// routable reports whether new calls should be sent to the backend. A
+// connection in TRANSIENT_FAILURE would fail them immediately.
+func (b *backend) routable() bool {
+	switch b.state() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	}
+	return true
+}
+
+// runPool keeps the connection pool in step with running instances until Stop
+func (lb *Balancer) runPool() {
+	ticker := time.NewTicker(poolSyncInterval)
+	defer ticker.Stop()
+
+	lb.syncPool()
+	for {
+		select {
+		case <-ticker.C:
+			lb.syncPool()
+		case <-lb.stopCh:
+			return
+		}
+	}
+}
+
+// syncPool connects to instances that became routable and closes connections
+// to instances that went away or changed ports once their last in-flight call
+// has finished
+func (lb *Balancer) syncPool() {
+	live := make(map[string]bool)
+	for _, procName := range lb.targetProcesses() {
+		for _, b := range lb.runningBackends(procName) {
+			live[b.instanceID] = true
+		}
+	}
+
+	lb.backendsMu.Lock()
+	defer lb.backendsMu.Unlock()
+
+	now := time.Now()
+	counts := make(map[connectivity.State]int)
+
+	var retired []*backend
+	for _, b := range lb.retired {
+		if b.outstanding() == 0 {
+			b.close()
+			log.Printf("Load balancer %q: closed connection to %s", lb.config.Name, b.addr)
+			continue
+		}
+		retired = append(retired, b)
+		if b.conn != nil {
+			counts[b.state()]++
+		}
+	}
+	lb.retired = retired
+
+	ejected := 0
+	for id, b := range lb.backends {
+		if !live[id] && b.outstanding() == 0 {
+			b.close()
+			delete(lb.backends, id)
+			log.Printf("Load balancer %q: closed connection to %s", lb.config.Name, b.addr)
+			continue
+		}
+		if b.conn != nil {
+			counts[b.state()]++
+		}
+		if b.ejected(now) {
+			ejected++
+		}
+	}
+	lbBackendsEjected.Set(float64(ejected), lb.config.Name)
+
+	for _, s := range poolStates {
+		lbPoolConnections.Set(float64(counts[s]), lb.config.Name, s.String())
+	}
+}
+
+// closePool closes every pooled connection
+func (lb *Balancer) closePool() {
+	lb.backendsMu.Lock()
+	defer lb.backendsMu.Unlock()
+
+	for id, b := range lb.backends {
+		b.close()
+		delete(lb.backends, id)
+	}
+	for _, b := range lb.retired {
+		b.close()
+	}
+	lb.retired = nil
+	for _, s := range poolStates {
+		lbPoolConnections.Delete(lb.config.Name, s.String())
+	}
+	lbBackendsEjected.Delete(lb.config.Name)
+}
@@ -0,0 +1,346 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+var lbRateLimited = metrics.NewCounterVec("gowinproc_lb_rate_limited_total",
+	"Calls rejected by a route's rate or concurrency limits", "balancer", "route", "reason")
+
+const (
+	// retryAfterKey is the metadata key (header in HTTP mode) of the retry
+	// hint sent with rejected calls, in whole seconds
+	retryAfterKey = "retry-after"
+
+	// concurrencyRetryAfter is the retry hint for calls rejected by a
+	// concurrency limit, which has no refill time to derive one from
+	concurrencyRetryAfter = time.Second
+
+	// clientSweepInterval is how often the state of clients that are back
+	// at their initial state is dropped
+	clientSweepInterval = time.Minute
+)
+
+// Reasons for rejecting a call
+const (
+	limitRate              = "rate"
+	limitConcurrency       = "concurrency"
+	limitClientRate        = "client_rate"
+	limitClientConcurrency = "client_concurrency"
+)
+
+// RouteLimits describes the limits of a route and their current usage
+type RouteLimits struct {
+	Route    string                  `json:"route"`
+	Policy   *models.RateLimitPolicy `json:"policy"` // nil: unlimited
+	InFlight int                     `json:"in_flight"`
+	Clients  int                     `json:"clients"`  // clients with tracked usage
+	Rejected map[string]uint64       `json:"rejected"` // calls rejected since start, by reason
+}
+
+// limits is one compiled set of limits: the route's or each client's
+type limits struct {
+	rate          float64 // tokens per second (0 = no rate limit)
+	burst         float64
+	maxConcurrent int // 0 = unlimited
+}
+
+// tokenBucket holds the tokens available for new calls; a new bucket is full
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accrued since the last refill, up to the burst size
+func (b *tokenBucket) refill(now time.Time, l limits) {
+	if b.last.IsZero() {
+		b.tokens = l.burst
+	} else {
+		b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	}
+	b.last = now
+}
+
+// usage is what has been counted against one set of limits
+type usage struct {
+	bucket   tokenBucket
+	inFlight int
+}
+
+// check returns the reason a new call is over the limits and how long until
+// it would not be, or "" if the call is within them
+func (u *usage) check(now time.Time, l limits, rateReason, concurrencyReason string) (string, time.Duration) {
+	if l.maxConcurrent > 0 && u.inFlight >= l.maxConcurrent {
+		return concurrencyReason, concurrencyRetryAfter
+	}
+	if l.rate > 0 {
+		u.bucket.refill(now, l)
+		if u.bucket.tokens < 1 {
+			return rateReason, time.Duration((1 - u.bucket.tokens) / l.rate * float64(time.Second))
+		}
+	}
+	return "", 0
+}
+
+// take counts a call that passed check
+func (u *usage) take(l limits) {
+	if l.rate > 0 {
+		u.bucket.tokens--
+	}
+	u.inFlight++
+}
+
+// idle reports whether the usage is back where new usage starts, so it can be
+// dropped without changing any decision
+func (u *usage) idle(now time.Time, l limits) bool {
+	if u.inFlight > 0 {
+		return false
+	}
+	if l.rate == 0 {
+		return true
+	}
+	u.bucket.refill(now, l)
+	return u.bucket.tokens >= l.burst
+}
+
+// limiter enforces a route's rate limit policy, adjustable at runtime
+type limiter struct {
+	mu        sync.Mutex
+	policy    *models.RateLimitPolicy // normalized; nil = unlimited
+	route     limits
+	client    limits
+	clientKey string // lower case metadata key; "" = client IP
+	perClient bool
+
+	usage     usage
+	clients   map[string]*usage
+	lastSweep time.Time
+	rejected  map[string]uint64
+}
+
+// newLimiter creates a limiter; a nil policy leaves the route unlimited
+func newLimiter(policy *models.RateLimitPolicy) (*limiter, error) {
+	l := &limiter{
+		clients:  make(map[string]*usage),
+		rejected: make(map[string]uint64),
+	}
+	if err := l.set(policy); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// set replaces the policy. Usage counted so far is kept, except per-client
+// usage when the client key changes.
+func (l *limiter) set(policy *models.RateLimitPolicy) error {
+	policy, err := normalizeRateLimit(policy)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.policy = policy
+	l.route, l.client = limits{}, limits{}
+	perClient, clientKey := false, ""
+	if policy != nil {
+		l.route = limits{rate: policy.RequestsPerSecond, burst: float64(policy.Burst), maxConcurrent: policy.MaxConcurrent}
+		if pc := policy.PerClient; pc != nil {
+			l.client = limits{rate: pc.RequestsPerSecond, burst: float64(pc.Burst), maxConcurrent: pc.MaxConcurrent}
+			perClient, clientKey = true, strings.ToLower(pc.Key)
+		}
+	}
+	if perClient != l.perClient || clientKey != l.clientKey {
+		l.clients = make(map[string]*usage)
+	}
+	l.perClient, l.clientKey = perClient, clientKey
+	return nil
+}
+
+// acquire admits a call unless it is over a limit. An admitted call counts
+// as in flight until release is called; a rejected call gets the reason and
+// how long the client should wait before retrying.
+func (l *limiter) acquire(ctx context.Context) (release func(), reason string, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.policy == nil {
+		return func() {}, "", 0
+	}
+	now := time.Now()
+
+	var client *usage
+	if l.perClient {
+		key := clientKey(ctx, l.clientKey)
+		client = l.clients[key]
+		if client == nil {
+			l.sweep(now)
+			client = &usage{}
+			l.clients[key] = client
+		}
+	}
+
+	// Check every limit before counting against any of them
+	reason, retryAfter = l.usage.check(now, l.route, limitRate, limitConcurrency)
+	if reason == "" && client != nil {
+		reason, retryAfter = client.check(now, l.client, limitClientRate, limitClientConcurrency)
+	}
+	if reason != "" {
+		l.rejected[reason]++
+		return nil, reason, retryAfter
+	}
+
+	l.usage.take(l.route)
+	if client != nil {
+		client.take(l.client)
+	}
+	return func() {
+		l.mu.Lock()
+		l.usage.inFlight--
+		if client != nil {
+			client.inFlight--
+		}
+		l.mu.Unlock()
+	}, "", 0
+}
+
+// sweep drops idle client usage at most once per clientSweepInterval; l.mu
+// must be held
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < clientSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, u := range l.clients {
+		if u.idle(now, l.client) {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// status returns the route's limits and usage
+func (l *limiter) status(route string) RouteLimits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := RouteLimits{
+		Route:    route,
+		InFlight: l.usage.inFlight,
+		Clients:  len(l.clients),
+		Rejected: make(map[string]uint64, len(l.rejected)),
+	}
+	if l.policy != nil {
+		s.Policy = copyRateLimit(l.policy)
+	}
+	for reason, n := range l.rejected {
+		s.Rejected[reason] = n
+	}
+	return s
+}
+
+// normalizeRateLimit validates a policy and returns a copy with default burst
+// sizes filled in (nil for a nil policy)
+func normalizeRateLimit(p *models.RateLimitPolicy) (*models.RateLimitPolicy, error) {
+	if p == nil {
+		return nil, nil
+	}
+	n := copyRateLimit(p)
+
+	// Per-client limits alone leave the route as a whole unlimited
+	if n.PerClient == nil || n.RequestsPerSecond != 0 || n.Burst != 0 || n.MaxConcurrent != 0 {
+		if err := normalizeLimits("rate_limit", n.RequestsPerSecond, &n.Burst, n.MaxConcurrent); err != nil {
+			return nil, err
+		}
+	}
+	if pc := n.PerClient; pc != nil {
+		if err := normalizeLimits("rate_limit.per_client", pc.RequestsPerSecond, &pc.Burst, pc.MaxConcurrent); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// normalizeLimits validates one set of limits and defaults the burst size to
+// the rate rounded up
+func normalizeLimits(name string, rate float64, burst *int, maxConcurrent int) error {
+	switch {
+	case rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0):
+		return fmt.Errorf("%s.requests_per_second must be a positive number", name)
+	case *burst < 0:
+		return fmt.Errorf("%s.burst must not be negative", name)
+	case maxConcurrent < 0:
+		return fmt.Errorf("%s.max_concurrent must not be negative", name)
+	case rate == 0 && *burst > 0:
+		return fmt.Errorf("%s.burst requires requests_per_second", name)
+	case rate == 0 && maxConcurrent == 0:
+		return fmt.Errorf("%s must set requests_per_second or max_concurrent", name)
+	}
+	if rate > 0 && *burst == 0 {
+		*burst = int(math.Ceil(rate))
+	}
+	return nil
+}
+
+// copyRateLimit returns a deep copy of a policy
+func copyRateLimit(p *models.RateLimitPolicy) *models.RateLimitPolicy {
+	c := *p
+	if p.PerClient != nil {
+		pc := *p.PerClient
+		c.PerClient = &pc
+	}
+	return &c
+}
+
+// admit applies the route's limits to a call. Rejected calls are counted and
+// get a RESOURCE_EXHAUSTED error along with the wait to hint to the client;
+// admitted calls must call release when they end.
+func (lb *Balancer) admit(ctx context.Context, route *routeHandler) (release func(), retryAfter time.Duration, err error) {
+	release, reason, retryAfter := route.limiter.acquire(ctx)
+	if reason == "" {
+		return release, 0, nil
+	}
+	lbRateLimited.Inc(lb.config.Name, route.name, reason)
+	return nil, retryAfter, status.Errorf(codes.ResourceExhausted, "%s limit of route %q exceeded",
+		strings.ReplaceAll(reason, "_", " "), route.name)
+}
+
+// retryAfterSeconds formats a retry hint as whole seconds, rounded up
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
+
+// Limits returns the limits of every route
+func (lb *Balancer) Limits() []RouteLimits {
+	limits := make([]RouteLimits, 0, len(lb.routes))
+	for _, route := range lb.routes {
+		limits = append(limits, route.limiter.status(route.name))
+	}
+	return limits
+}
+
+// SetRouteLimits replaces the limits of a route at runtime; nil removes them.
+// The change is not persisted; the configured limits apply again after a
+// restart.
+func (lb *Balancer) SetRouteLimits(routeName string, policy *models.RateLimitPolicy) (RouteLimits, error) {
+	route := lb.findRouteByName(routeName)
+	if route == nil {
+		return RouteLimits{}, fmt.Errorf("route %q not found", routeName)
+	}
+	if err := route.limiter.set(policy); err != nil {
+		return RouteLimits{}, fmt.Errorf("route %q: %w", routeName, err)
+	}
+	return route.limiter.status(route.name), nil
+}
@@ -0,0 +1,116 @@
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestUsageBurstAndRefill(t *testing.T) {
+	// 2 calls per second with room for a burst of 3
+	l := limits{rate: 2, burst: 3}
+	start := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		after      time.Duration // since start
+		admitted   bool
+		retryAfter time.Duration
+	}{
+		{0, true, 0},
+		{0, true, 0},
+		{0, true, 0},
+		{0, false, 500 * time.Millisecond}, // burst used up
+		{250 * time.Millisecond, false, 250 * time.Millisecond},
+		{500 * time.Millisecond, true, 0}, // one token refilled
+		{500 * time.Millisecond, false, 500 * time.Millisecond},
+		{10 * time.Second, true, 0}, // refilled up to the burst only
+		{10 * time.Second, true, 0},
+		{10 * time.Second, true, 0},
+		{10 * time.Second, false, 500 * time.Millisecond},
+	}
+
+	var u usage
+	for i, step := range steps {
+		reason, retryAfter := u.check(start.Add(step.after), l, limitRate, limitConcurrency)
+		if admitted := reason == ""; admitted != step.admitted {
+			t.Fatalf("step %d: admitted = %v (reason %q), want %v", i, admitted, reason, step.admitted)
+		}
+		if step.admitted {
+			u.take(l)
+			u.inFlight--
+			continue
+		}
+		if reason != limitRate {
+			t.Errorf("step %d: reason = %q, want %q", i, reason, limitRate)
+		}
+		if d := retryAfter - step.retryAfter; d < -time.Millisecond || d > time.Millisecond {
+			t.Errorf("step %d: retry after %v, want %v", i, retryAfter, step.retryAfter)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		wait time.Duration
+		want string
+	}{
+		{0, "1"},
+		{250 * time.Millisecond, "1"},
+		{time.Second, "1"},
+		{1500 * time.Millisecond, "2"},
+		{10 * time.Second, "10"},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfterSeconds(tt.wait); got != tt.want {
+			t.Errorf("retryAfterSeconds(%v) = %q, want %q", tt.wait, got, tt.want)
+		}
+	}
+}
+
+func TestLimiterPerClient(t *testing.T) {
+	l, err := newLimiter(&models.RateLimitPolicy{
+		PerClient: &models.ClientRateLimit{Key: "X-Tenant", RequestsPerSecond: 0.5, Burst: 2, MaxConcurrent: 1},
+	})
+	if err != nil {
+		t.Fatalf("newLimiter: %v", err)
+	}
+	tenant := func(name string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", name))
+	}
+
+	release, reason, _ := l.acquire(tenant("a"))
+	if reason != "" {
+		t.Fatalf("first call rejected: %s", reason)
+	}
+	if _, reason, retryAfter := l.acquire(tenant("a")); reason != limitClientConcurrency || retryAfter != concurrencyRetryAfter {
+		t.Errorf("call over max_concurrent: reason %q, retry after %v", reason, retryAfter)
+	}
+	if _, reason, _ := l.acquire(tenant("b")); reason != "" {
+		t.Errorf("other client rejected: %s", reason)
+	}
+	release()
+
+	release, reason, _ = l.acquire(tenant("a"))
+	if reason != "" {
+		t.Fatalf("second call rejected: %s", reason)
+	}
+	release()
+
+	// Burst of 2 used up; a token takes 2s to refill
+	_, reason, retryAfter := l.acquire(tenant("a"))
+	if reason != limitClientRate {
+		t.Fatalf("call over burst: reason %q, want %q", reason, limitClientRate)
+	}
+	if retryAfter <= 1900*time.Millisecond || retryAfter > 2*time.Second {
+		t.Errorf("call over burst: retry after %v, want about 2s", retryAfter)
+	}
+
+	if got := l.status("r").Rejected; got[limitClientRate] != 1 || got[limitClientConcurrency] != 1 {
+		t.Errorf("rejected = %v", got)
+	}
+}
@@ -0,0 +1,439 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"regexp"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+var lbAttempts = metrics.NewCounterVec("gowinproc_lb_extra_attempts_total",
+	"Additional attempts made for proxied calls", "balancer", "route", "kind")
+
+// callPolicy is the compiled retry/hedging policy of a route
+type callPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	retryable      map[codes.Code]bool
+	maxBufferBytes int
+
+	hedgeMethods     []*regexp.Regexp
+	hedgeDelay       time.Duration
+	hedgeMaxAttempts int
+	hedgeBufferBytes int
+}
+
+// compilePolicy compiles the retry and hedging settings of a route.
+// Returns nil if neither is configured.
+func compilePolicy(retry *models.RetryPolicy, hedge *models.HedgePolicy) (*callPolicy, error) {
+	if retry == nil && hedge == nil {
+		return nil, nil
+	}
+
+	p := &callPolicy{
+		maxAttempts: 1,
+		retryable:   map[codes.Code]bool{codes.Unavailable: true},
+	}
+
+	if retry != nil {
+		if retry.MaxAttempts < 1 {
+			return nil, fmt.Errorf("retry.max_attempts must be at least 1")
+		}
+		p.maxAttempts = retry.MaxAttempts
+		p.initialBackoff = retry.InitialBackoff
+		p.maxBackoff = retry.MaxBackoff
+		p.maxBufferBytes = retry.MaxBufferBytes
+		p.retryable = make(map[codes.Code]bool)
+		for _, name := range retry.RetryableCodes {
+			var c codes.Code
+			if err := c.UnmarshalJSON([]byte(`"` + name + `"`)); err != nil {
+				return nil, fmt.Errorf("retry.retryable_codes: unknown status code %q", name)
+			}
+			p.retryable[c] = true
+		}
+	}
+
+	if hedge != nil {
+		if hedge.MaxAttempts < 1 {
+			return nil, fmt.Errorf("hedge.max_attempts must be at least 1")
+		}
+		for _, pattern := range hedge.Methods {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hedge method pattern %q: %w", pattern, err)
+			}
+			p.hedgeMethods = append(p.hedgeMethods, re)
+		}
+		p.hedgeDelay = hedge.Delay
+		p.hedgeMaxAttempts = hedge.MaxAttempts
+		p.hedgeBufferBytes = hedge.MaxBufferBytes
+	}
+
+	return p, nil
+}
+
+// hedged reports whether calls to the method may be hedged
+func (p *callPolicy) hedged(method string) bool {
+	for _, re := range p.hedgeMethods {
+		if re.MatchString(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before the given retry (1-based), with jitter
+func (p *callPolicy) backoff(retry int) time.Duration {
+	d := p.initialBackoff
+	for i := 1; i < retry && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// errNotReplayable is returned when a new attempt cannot be sent the full request
+var errNotReplayable = status.Error(codes.Unavailable, "request is too large to replay")
+
+// replayBuffer records the client's request messages so that every attempt
+// of a call can be sent the same request. Once the recorded size exceeds the
+// limit the call can no longer be replayed and messages are only kept until
+// the last remaining attempt has sent them.
+type replayBuffer struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	msgs     [][]byte
+	size     int
+	limit    int
+	overflow bool  // limit exceeded: no further attempts may start
+	done     bool  // client finished sending
+	err      error // client receive error other than io.EOF
+	senders  int   // attempts currently reading the buffer
+	trimmed  int   // messages before this index have been dropped
+}
+
+func newReplayBuffer(limit int) *replayBuffer {
+	b := &replayBuffer{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// fill reads the client's messages until it half-closes or fails
+func (b *replayBuffer) fill(src grpc.ServerStream) {
+	for {
+		f := &frame{}
+		err := src.RecvMsg(f)
+
+		b.mu.Lock()
+		if err != nil {
+			b.done = true
+			if err != io.EOF {
+				b.err = err
+			}
+			b.cond.Broadcast()
+			b.mu.Unlock()
+			return
+		}
+		b.msgs = append(b.msgs, f.payload)
+		b.size += len(f.payload)
+		if b.size > b.limit {
+			b.overflow = true
+		}
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	}
+}
+
+// replayable reports whether a new attempt could be sent the full request
+func (b *replayBuffer) replayable() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.overflow
+}
+
+// attach registers a new attempt as a reader. Returns false if the request
+// can no longer be replayed.
+func (b *replayBuffer) attach() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.overflow {
+		return false
+	}
+	b.senders++
+	return true
+}
+
+// detach unregisters an attempt attached with attach
+func (b *replayBuffer) detach() {
+	b.mu.Lock()
+	b.senders--
+	b.mu.Unlock()
+}
+
+// sendTo sends all recorded and future messages to an attached attempt,
+// then half-closes it
+func (b *replayBuffer) sendTo(ctx context.Context, dst grpc.ClientStream) {
+	defer b.detach()
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	})
+	defer stop()
+
+	for i := 0; ; i++ {
+		b.mu.Lock()
+		for i >= len(b.msgs) && !b.done && ctx.Err() == nil {
+			b.cond.Wait()
+		}
+		if ctx.Err() != nil {
+			b.mu.Unlock()
+			return
+		}
+		if i >= len(b.msgs) {
+			failed := b.err != nil
+			b.mu.Unlock()
+			if !failed {
+				dst.CloseSend()
+			}
+			return
+		}
+		payload := b.msgs[i]
+		if b.overflow && b.senders == 1 {
+			// No other attempt can need these any more
+			for ; b.trimmed <= i; b.trimmed++ {
+				b.msgs[b.trimmed] = nil
+			}
+		}
+		b.mu.Unlock()
+
+		if err := dst.SendMsg(&frame{payload: payload}); err != nil {
+			// The attempt has ended; its status is reported by RecvMsg
+			return
+		}
+	}
+}
+
+// attempt is one copy of a proxied call sent to a backend
+type attempt struct {
+	backend *backend
+	stream  grpc.ClientStream
+	cancel  context.CancelFunc
+	start   time.Time
+}
+
+// attemptResult is the first response (or failure) of an attempt
+type attemptResult struct {
+	attempt *attempt
+	first   *frame // nil if the attempt ended without a response message
+	err     error  // io.EOF if it ended successfully without messages
+}
+
+// proxyWithPolicy proxies a call with retries and/or hedging. Attempts race
+// until one returns a response message or a non-retryable status; that
+// attempt is committed and its remaining responses are streamed to the client.
+func (lb *Balancer) proxyWithPolicy(ctx context.Context, route *routeHandler, method string,
+	stream grpc.ServerStream, policy *callPolicy) (*backend, error) {
+
+	hedged := policy.hedged(method)
+	maxAttempts, limit := policy.maxAttempts, policy.maxBufferBytes
+	if hedged {
+		maxAttempts, limit = policy.hedgeMaxAttempts, policy.hedgeBufferBytes
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	buf := newReplayBuffer(limit)
+
+	results := make(chan attemptResult, maxAttempts)
+	tried := make(map[string]bool)
+	running := make(map[*attempt]bool)
+	started := 0
+
+	// finish releases an attempt's backend with the attempt's outcome
+	finish := func(a *attempt, err error) {
+		a.cancel()
+		lb.done(a.backend, a.start, err)
+		delete(running, a)
+	}
+	defer func() {
+		for a := range running {
+			finish(a, status.Error(codes.Canceled, "attempt abandoned"))
+		}
+	}()
+
+	startAttempt := func() error {
+		selected, err := lb.pickBackend(ctx, route, tried)
+		if err != nil {
+			return err
+		}
+		if !buf.attach() {
+			lb.done(selected, time.Now(), status.Error(codes.Canceled, "attempt not started"))
+			return errNotReplayable
+		}
+		tried[selected.instanceID] = true
+		route.countTarget(lb.config.Name, selected.process)
+
+		attemptCtx, attemptCancel := context.WithCancel(ctx)
+		a := &attempt{backend: selected, cancel: attemptCancel, start: time.Now()}
+		running[a] = true
+		started++
+
+		a.stream, err = selected.conn.NewStream(attemptCtx, proxyStreamDesc, method)
+		if err != nil {
+			buf.detach()
+			a.stream = nil
+			results <- attemptResult{attempt: a, err: err}
+			return nil
+		}
+		go buf.sendTo(attemptCtx, a.stream)
+		go func() {
+			f := &frame{}
+			if err := a.stream.RecvMsg(f); err != nil {
+				results <- attemptResult{attempt: a, err: err}
+				return
+			}
+			results <- attemptResult{attempt: a, first: f}
+		}()
+		return nil
+	}
+
+	if err := startAttempt(); err != nil {
+		return nil, err
+	}
+	go buf.fill(stream)
+
+	var hedgeTimer, retryTimer <-chan time.Time
+	if hedged && maxAttempts > 1 {
+		hedgeTimer = time.After(policy.hedgeDelay)
+	}
+	var lastErr error
+	var lastBackend *backend
+	retries := 0
+
+	for {
+		select {
+		case r := <-results:
+			a := r.attempt
+			if r.first != nil || r.err == io.EOF || !policy.retryable[status.Code(r.err)] {
+				// Commit: stop the other attempts and stream this one to the client
+				for other := range running {
+					if other != a {
+						finish(other, status.Error(codes.Canceled, "another attempt was committed"))
+					}
+				}
+				if a.stream == nil {
+					finish(a, r.err)
+					return a.backend, r.err
+				}
+				err := relay(stream, a.stream, r.first, r.err)
+				finish(a, err)
+				return a.backend, err
+			}
+
+			// Retryable failure before any response
+			finish(a, r.err)
+			lastErr, lastBackend = r.err, a.backend
+			if started >= maxAttempts || !buf.replayable() {
+				if len(running) == 0 {
+					if a.stream != nil {
+						stream.SetTrailer(a.stream.Trailer())
+					}
+					return lastBackend, lastErr
+				}
+				continue
+			}
+			if hedged {
+				// A failed hedge is replaced straight away
+				if err := startAttempt(); err == nil {
+					lbAttempts.Inc(lb.config.Name, route.name, "hedge")
+				} else if len(running) == 0 {
+					return lastBackend, lastErr
+				}
+				continue
+			}
+			retries++
+			retryTimer = time.After(policy.backoff(retries))
+
+		case <-retryTimer:
+			retryTimer = nil
+			if !buf.replayable() {
+				return lastBackend, lastErr
+			}
+			if err := startAttempt(); err != nil {
+				return lastBackend, lastErr
+			}
+			lbAttempts.Inc(lb.config.Name, route.name, "retry")
+
+		case <-hedgeTimer:
+			hedgeTimer = nil
+			if started >= maxAttempts || !buf.replayable() {
+				continue
+			}
+			if err := startAttempt(); err == nil {
+				lbAttempts.Inc(lb.config.Name, route.name, "hedge")
+			}
+			if started < maxAttempts {
+				hedgeTimer = time.After(policy.hedgeDelay)
+			}
+
+		case <-ctx.Done():
+			return lastBackend, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// relay streams a committed attempt's response to the client, starting with
+// the first response message (or final error) already received from it
+func relay(dst grpc.ServerStream, src grpc.ClientStream, first *frame, firstErr error) error {
+	if first == nil {
+		// No messages: headers go out with the trailers
+		if md, err := src.Header(); err == nil && len(md) > 0 {
+			dst.SetHeader(md)
+		}
+		dst.SetTrailer(src.Trailer())
+		if firstErr == io.EOF {
+			return nil
+		}
+		return firstErr
+	}
+
+	md, err := src.Header()
+	if err != nil {
+		return err
+	}
+	if err := dst.SendHeader(md); err != nil {
+		return err
+	}
+
+	f := first
+	for {
+		if err := dst.SendMsg(f); err != nil {
+			return err
+		}
+		if err := src.RecvMsg(f); err != nil {
+			dst.SetTrailer(src.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
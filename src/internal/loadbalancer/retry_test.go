@@ -0,0 +1,182 @@
+package loadbalancer
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// recordedRequest is a client stream that sends the given messages, then half-closes
+type recordedRequest struct {
+	grpc.ServerStream
+	msgs [][]byte
+}
+
+func (s *recordedRequest) RecvMsg(m any) error {
+	if len(s.msgs) == 0 {
+		return io.EOF
+	}
+	m.(*frame).payload, s.msgs = s.msgs[0], s.msgs[1:]
+	return nil
+}
+
+// capturedAttempt is a backend stream that records what it is sent
+type capturedAttempt struct {
+	grpc.ClientStream
+	sent   [][]byte
+	closed bool
+}
+
+func (s *capturedAttempt) SendMsg(m any) error {
+	s.sent = append(s.sent, m.(*frame).payload)
+	return nil
+}
+
+func (s *capturedAttempt) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func TestReplayBufferOverflow(t *testing.T) {
+	msgs := [][]byte{[]byte("1234"), []byte("5678"), []byte("9abc")}
+
+	t.Run("within limit", func(t *testing.T) {
+		buf := newReplayBuffer(12)
+		buf.fill(&recordedRequest{msgs: msgs})
+		if !buf.replayable() || !buf.attach() {
+			t.Fatal("request within the limit is not replayable")
+		}
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		buf := newReplayBuffer(10)
+		if !buf.attach() {
+			t.Fatal("first attempt not attached")
+		}
+		buf.fill(&recordedRequest{msgs: msgs})
+
+		if buf.replayable() || buf.attach() {
+			t.Fatal("request over the limit is replayable")
+		}
+
+		// The attempt attached before the overflow still gets the whole request
+		dst := &capturedAttempt{}
+		buf.sendTo(context.Background(), dst)
+		if len(dst.sent) != len(msgs) || !dst.closed {
+			t.Fatalf("attempt was sent %d message(s), closed %v", len(dst.sent), dst.closed)
+		}
+		for i, payload := range buf.msgs {
+			if payload != nil {
+				t.Errorf("message %d kept after the last attempt sent it", i)
+			}
+		}
+	})
+}
+
+// unavailableHealth fails every call with UNAVAILABLE
+type unavailableHealth struct {
+	healthpb.UnimplementedHealthServer
+	calls atomic.Int32
+}
+
+func (h *unavailableHealth) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.calls.Add(1)
+	return nil, status.Error(codes.Unavailable, "backend unavailable")
+}
+
+func TestRetryRequestTooLargeToReplay(t *testing.T) {
+	backend := &unavailableHealth{}
+	lb := startBalancer(t, models.LoadBalancerRoute{
+		TargetProcesses: []string{"backend"},
+		Strategy:        "round_robin",
+		Retry: &models.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			RetryableCodes: []string{"UNAVAILABLE"},
+			MaxBufferBytes: 16,
+		},
+	}, startBackend(t, backend))
+	client := healthpb.NewHealthClient(dialBalancer(t, lb))
+
+	tests := []struct {
+		name     string
+		service  string
+		attempts int32
+	}{
+		{"replayable", "small", 3},
+		{"too large", strings.Repeat("x", 32), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend.calls.Store(0)
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+			if status.Code(err) != codes.Unavailable {
+				t.Fatalf("Check: %v, want UNAVAILABLE", err)
+			}
+			if got := backend.calls.Load(); got != tt.attempts {
+				t.Errorf("backend got %d attempt(s), want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+// slowFirstHealth holds the first call until it is cancelled and answers
+// the others straight away
+type slowFirstHealth struct {
+	healthpb.UnimplementedHealthServer
+	calls     atomic.Int32
+	cancelled chan struct{}
+}
+
+func (h *slowFirstHealth) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if h.calls.Add(1) == 1 {
+		<-ctx.Done()
+		close(h.cancelled)
+		return nil, ctx.Err()
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestHedgeCancelsSlowAttempt(t *testing.T) {
+	backend := &slowFirstHealth{cancelled: make(chan struct{})}
+	lb := startBalancer(t, models.LoadBalancerRoute{
+		TargetProcesses: []string{"backend"},
+		Strategy:        "round_robin",
+		Hedge: &models.HedgePolicy{
+			Methods:        []string{"/grpc.health.v1.Health/Check"},
+			Delay:          20 * time.Millisecond,
+			MaxAttempts:    2,
+			MaxBufferBytes: 1024,
+		},
+	}, startBackend(t, backend), startBackend(t, backend))
+	client := healthpb.NewHealthClient(dialBalancer(t, lb))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", resp.Status)
+	}
+	if got := backend.calls.Load(); got != 2 {
+		t.Errorf("backends got %d attempt(s), want 2", got)
+	}
+
+	select {
+	case <-backend.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow attempt was not cancelled after the hedge won")
+	}
+}
@@ -0,0 +1,186 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+)
+
+var lbTargetRequests = metrics.NewCounterVec("gowinproc_lb_target_requests_total",
+	"Requests sent to each target process of a route", "balancer", "route", "target")
+
+// RouteSplit describes how a route divides traffic between its target processes
+type RouteSplit struct {
+	Route    string            `json:"route"`
+	Strategy string            `json:"strategy"`
+	Default  bool              `json:"default,omitempty"` // used when no other route matches
+	Targets  []string          `json:"targets"`
+	Weights  map[string]int    `json:"weights,omitempty"` // nil: all targets share one pool
+	Requests map[string]uint64 `json:"requests"`          // requests sent to each target since start
+}
+
+// validateWeights checks that weights only reference targets of the route and
+// leave at least one target with traffic
+func validateWeights(targets []string, weights map[string]int) error {
+	known := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		known[t] = true
+	}
+
+	total := 0
+	for target, w := range weights {
+		if !known[target] {
+			return fmt.Errorf("weight for %q which is not a target process", target)
+		}
+		if w < 0 {
+			return fmt.Errorf("weight for %q must not be negative", target)
+		}
+		total += w
+	}
+	if len(weights) > 0 && total == 0 {
+		return fmt.Errorf("at least one target must have a positive weight")
+	}
+	return nil
+}
+
+// getWeights returns the route's current weights (nil if traffic is not split)
+func (r *routeHandler) getWeights() map[string]int {
+	r.weightsMu.RLock()
+	defer r.weightsMu.RUnlock()
+	return r.weights
+}
+
+// countTarget records a request sent to a target process
+func (r *routeHandler) countTarget(balancer, target string) {
+	if counter, ok := r.targetRequests[target]; ok {
+		atomic.AddUint64(counter, 1)
+	}
+	lbTargetRequests.Inc(balancer, r.name, target)
+}
+
+// split returns the route's current traffic split
+func (r *routeHandler) split() RouteSplit {
+	s := RouteSplit{
+		Route:    r.name,
+		Strategy: r.strategy,
+		Targets:  r.targetProcs,
+		Requests: make(map[string]uint64, len(r.targetRequests)),
+	}
+	if weights := r.getWeights(); weights != nil {
+		s.Weights = make(map[string]int, len(weights))
+		for target, w := range weights {
+			s.Weights[target] = w
+		}
+	}
+	for target, counter := range r.targetRequests {
+		s.Requests[target] = atomic.LoadUint64(counter)
+	}
+	return s
+}
+
+// pickWeightedTarget chooses a target process in proportion to its weight
+// among those that have backends available. draw(n) returns a number in
+// [0, n). Returns "" if no target has backends.
+func pickWeightedTarget(weights map[string]int, available map[string][]*backend, draw func(n int) int) string {
+	// Iterate in a fixed order so a given draw always maps to the same target
+	targets := make([]string, 0, len(available))
+	total := 0
+	for target, backends := range available {
+		if len(backends) > 0 && weights[target] > 0 {
+			targets = append(targets, target)
+			total += weights[target]
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	sort.Strings(targets)
+
+	n := draw(total)
+	for _, target := range targets {
+		n -= weights[target]
+		if n < 0 {
+			return target
+		}
+	}
+	return targets[len(targets)-1]
+}
+
+// Splits returns the traffic split of every route
+func (lb *Balancer) Splits() []RouteSplit {
+	splits := make([]RouteSplit, 0, len(lb.routes))
+	for _, route := range lb.routes {
+		s := route.split()
+		s.Default = route == lb.defaultRoute
+		splits = append(splits, s)
+	}
+	return splits
+}
+
+// SetRouteWeights replaces the weights of a route at runtime. A nil or empty
+// map removes the split so all targets share one pool again. The change is
+// not persisted; the configured weights apply again after a restart.
+func (lb *Balancer) SetRouteWeights(routeName string, weights map[string]int) (RouteSplit, error) {
+	route := lb.findRouteByName(routeName)
+	if route == nil {
+		return RouteSplit{}, fmt.Errorf("route %q not found", routeName)
+	}
+	if err := validateWeights(route.targetProcs, weights); err != nil {
+		return RouteSplit{}, fmt.Errorf("route %q: %w", routeName, err)
+	}
+
+	var copied map[string]int
+	if len(weights) > 0 {
+		copied = make(map[string]int, len(weights))
+		for target, w := range weights {
+			copied[target] = w
+		}
+	}
+
+	route.weightsMu.Lock()
+	route.weights = copied
+	route.weightsMu.Unlock()
+
+	return route.split(), nil
+}
+
+// findRouteByName finds a route by its name
+func (lb *Balancer) findRouteByName(name string) *routeHandler {
+	for _, route := range lb.routes {
+		if route.name == name {
+			return route
+		}
+	}
+	return nil
+}
+
+// splitCanary narrows backends down to the canary instances of a process under
+// a canary update or to the other backends, by the canary's share of traffic.
+// Backends of processes without a canary stay in both groups; if either group
+// is empty all backends are kept.
+func (lb *Balancer) splitCanary(backends []*backend, draw func(int) int) []*backend {
+	var canaries, others []*backend
+	found, percent := false, 0
+	for _, b := range backends {
+		canary, p := lb.processManager.TrafficSplit(b.process)
+		switch {
+		case canary == nil:
+			canaries = append(canaries, b)
+			others = append(others, b)
+		case canary[b.instanceID]:
+			canaries = append(canaries, b)
+			found, percent = true, p
+		default:
+			others = append(others, b)
+		}
+	}
+	if !found || len(canaries) == len(backends) {
+		return backends
+	}
+	if draw(100) < percent {
+		return canaries
+	}
+	return others
+}
@@ -0,0 +1,193 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Backend states reported by the admin API
+const (
+	backendHealthy   = "healthy"
+	backendDraining  = "draining"
+	backendEjected   = "ejected"
+	backendUnhealthy = "unhealthy" // connection in TRANSIENT_FAILURE
+)
+
+// BalancerStatus describes a load balancer with its routes and backends
+type BalancerStatus struct {
+	Name       string          `json:"name"`
+	ListenPort int             `json:"listen_port"`
+	Protocol   string          `json:"protocol"`
+	TLS        bool            `json:"tls"`
+	Routes     []RouteSplit    `json:"routes"`
+	Backends   []BackendStatus `json:"backends"`
+}
+
+// BackendStatus describes a backend instance as seen by the balancer
+type BackendStatus struct {
+	Process           string     `json:"process"`
+	InstanceID        string     `json:"instance_id"`
+	Address           string     `json:"address"`
+	State             string     `json:"state"`        // healthy, draining, ejected or unhealthy
+	Connectivity      string     `json:"connectivity"` // state of the pooled connection ("n/a" in HTTP mode)
+	InFlight          int64      `json:"in_flight"`
+	Requests          uint64     `json:"requests"` // since start or the last reset
+	Errors            uint64     `json:"errors"`
+	LatencyAvgMs      float64    `json:"latency_avg_ms"`
+	LatencyEWMAMs     float64    `json:"latency_ewma_ms"`
+	Draining          bool       `json:"draining"`
+	Ejected           bool       `json:"ejected"`
+	EjectedUntil      *time.Time `json:"ejected_until,omitempty"`
+	Ejections         int        `json:"ejections"`
+	ConsecutiveErrors int        `json:"consecutive_errors"`
+}
+
+// isDraining reports whether an operator has drained the backend
+func (b *backend) isDraining() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.draining
+}
+
+// status returns the backend's current status
+func (b *backend) status(now time.Time) BackendStatus {
+	st := BackendStatus{
+		Process:      b.process,
+		InstanceID:   b.instanceID,
+		Address:      b.addr,
+		State:        backendHealthy,
+		Connectivity: "n/a",
+		InFlight:     b.outstanding(),
+	}
+
+	if b.conn != nil {
+		st.Connectivity = b.state().String()
+	}
+	if !b.routable() {
+		st.State = backendUnhealthy
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st.Requests = b.requests
+	st.Errors = b.errors
+	if b.requests > 0 {
+		st.LatencyAvgMs = float64(b.latencySum) / float64(time.Millisecond) / float64(b.requests)
+	}
+	st.LatencyEWMAMs = b.ewma * 1000
+	if b.ejectedUntil.After(now) {
+		until := b.ejectedUntil
+		st.Ejected = true
+		st.EjectedUntil = &until
+		st.State = backendEjected
+	}
+	st.Ejections = b.ejections
+	st.ConsecutiveErrors = b.consecutiveErrors
+	if b.draining {
+		st.Draining = true
+		st.State = backendDraining
+	}
+	return st
+}
+
+// Backends returns the status of every backend the balancer is tracking
+func (lb *Balancer) Backends() []BackendStatus {
+	lb.backendsMu.Lock()
+	backends := make([]*backend, 0, len(lb.backends))
+	for _, b := range lb.backends {
+		backends = append(backends, b)
+	}
+	lb.backendsMu.Unlock()
+
+	now := time.Now()
+	statuses := make([]BackendStatus, 0, len(backends))
+	for _, b := range backends {
+		statuses = append(statuses, b.status(now))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Process != statuses[j].Process {
+			return statuses[i].Process < statuses[j].Process
+		}
+		return statuses[i].Address < statuses[j].Address
+	})
+	return statuses
+}
+
+// Status returns the balancer's configuration summary, routes and backends
+func (lb *Balancer) Status() BalancerStatus {
+	return BalancerStatus{
+		Name:       lb.config.Name,
+		ListenPort: lb.config.ListenPort,
+		Protocol:   lb.config.Protocol,
+		TLS:        lb.config.TLS != nil,
+		Routes:     lb.Splits(),
+		Backends:   lb.Backends(),
+	}
+}
+
+// SetDraining drains a backend (no new calls; in-flight calls finish) or
+// puts it back into rotation. The state is kept in memory only.
+func (lb *Balancer) SetDraining(instanceID string, draining bool) (BackendStatus, error) {
+	lb.backendsMu.Lock()
+	b, ok := lb.backends[instanceID]
+	lb.backendsMu.Unlock()
+	if !ok {
+		return BackendStatus{}, fmt.Errorf("backend %q not found", instanceID)
+	}
+
+	b.mu.Lock()
+	b.draining = draining
+	b.mu.Unlock()
+
+	return b.status(time.Now()), nil
+}
+
+// ResetStats resets the request, error and latency counters of all backends
+// and the per-target request counts of all routes. Prometheus counters are
+// not affected.
+func (lb *Balancer) ResetStats() {
+	lb.backendsMu.Lock()
+	for _, b := range lb.backends {
+		b.mu.Lock()
+		b.requests, b.errors, b.latencySum = 0, 0, 0
+		b.mu.Unlock()
+	}
+	lb.backendsMu.Unlock()
+
+	for _, route := range lb.routes {
+		for _, counter := range route.targetRequests {
+			atomic.StoreUint64(counter, 0)
+		}
+	}
+}
+
+// InstanceCalls counts the calls proxied to an instance
+type InstanceCalls struct {
+	Requests   uint64
+	Errors     uint64
+	LatencySum time.Duration
+}
+
+// addInstanceCalls adds the calls proxied to each instance of a process, by
+// instance ID
+func (lb *Balancer) addInstanceCalls(processName string, calls map[string]InstanceCalls) {
+	lb.backendsMu.Lock()
+	defer lb.backendsMu.Unlock()
+
+	for _, b := range lb.backends {
+		if b.process != processName {
+			continue
+		}
+		b.mu.Lock()
+		c := calls[b.instanceID]
+		c.Requests += b.requests
+		c.Errors += b.errors
+		c.LatencySum += b.latencySum
+		calls[b.instanceID] = c
+		b.mu.Unlock()
+	}
+}
@@ -0,0 +1,322 @@
+package loadbalancer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/certs"
+)
+
+// certReloadInterval is how often certificate files are checked for changes
+const certReloadInterval = 30 * time.Second
+
+// certReloader holds a certificate and a CA pool loaded from files and
+// reloads them when the files change. Handshakes always use the latest
+// successfully loaded version, so existing connections are unaffected.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	caFiles  []string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate // nil if no certificate is configured
+	pool    *x509.CertPool   // nil if no CA files are configured
+	modTime time.Time
+}
+
+// newCertReloader loads the certificate (optional) and CA files (optional)
+func newCertReloader(certFile, keyFile string, caFiles []string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, caFiles: caFiles}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.modTime = r.latestModTime()
+	return r, nil
+}
+
+// load reads all files and replaces the current certificate and pool
+func (r *certReloader) load() error {
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate %s: %w", r.certFile, err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if len(r.caFiles) > 0 {
+		pool = x509.NewCertPool()
+		for _, file := range r.caFiles {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read CA file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return fmt.Errorf("no certificates found in %s", file)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool = cert, pool
+	r.mu.Unlock()
+	return nil
+}
+
+// latestModTime returns the newest modification time of the files
+func (r *certReloader) latestModTime() time.Time {
+	var latest time.Time
+	for _, file := range append([]string{r.certFile, r.keyFile}, r.caFiles...) {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// reloadIfChanged reloads the files if any of them was modified. On error
+// the previous certificate stays in use.
+func (r *certReloader) reloadIfChanged() (bool, error) {
+	modTime := r.latestModTime()
+	if !modTime.After(r.modTime) {
+		return false, nil
+	}
+	if err := r.load(); err != nil {
+		return false, err
+	}
+	r.modTime = modTime
+	return true, nil
+}
+
+func (r *certReloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *certReloader) caPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+// SetCertManager sets the certificate manager used for generated listener
+// certificates and for verifying backends against their process certificates
+func (lb *Balancer) SetCertManager(cm *certs.Manager) {
+	lb.certManager = cm
+}
+
+// listenerTLSConfig builds the TLS configuration of the listener (nil if TLS
+// is not enabled)
+func (lb *Balancer) listenerTLSConfig() (*tls.Config, error) {
+	cfg := lb.config.TLS
+	if cfg == nil {
+		return nil, nil
+	}
+
+	certFile, keyFile := cfg.CertFile, cfg.KeyFile
+	if certFile == "" {
+		if lb.certManager == nil {
+			return nil, fmt.Errorf("tls.cert_file is required without a certificate manager")
+		}
+		var err error
+		certFile, keyFile, err = lb.generatedCertificate(cfg.Hosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var caFiles []string
+	if cfg.ClientCAFile != "" {
+		caFiles = []string{cfg.ClientCAFile}
+	}
+	reloader, err := newCertReloader(certFile, keyFile, caFiles)
+	if err != nil {
+		return nil, err
+	}
+	lb.reloaders = append(lb.reloaders, reloader)
+
+	clientAuth := tls.NoClientCert
+	if cfg.ClientCAFile != "" {
+		clientAuth = tls.RequireAndVerifyClientCert
+		if cfg.ClientAuth == "optional" {
+			clientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	// Set explicitly since configs from GetConfigForClient are used as is
+	nextProtos := []string{"h2"}
+	if lb.config.Protocol == "http" || lb.config.Web != nil {
+		nextProtos = append(nextProtos, "http/1.1")
+	}
+
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		ClientAuth: clientAuth,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return reloader.certificate(), nil
+		},
+	}
+	if clientAuth == tls.NoClientCert {
+		return base, nil
+	}
+
+	// The client CA pool is per handshake so a reloaded bundle applies to
+	// new connections
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := base.Clone()
+			c.ClientCAs = reloader.caPool()
+			return c, nil
+		},
+	}, nil
+}
+
+// backendTLSConfig builds the TLS configuration used to connect to backends
+// (nil if backend TLS is not enabled)
+func (lb *Balancer) backendTLSConfig() (*tls.Config, error) {
+	cfg := lb.config.BackendTLS
+	if cfg == nil {
+		return nil, nil
+	}
+
+	// Trust the configured CA, or the certificate generated for each target
+	var caFiles []string
+	if cfg.CAFile != "" {
+		caFiles = []string{cfg.CAFile}
+	} else {
+		if lb.certManager == nil {
+			return nil, fmt.Errorf("backend_tls.ca_file is required without a certificate manager")
+		}
+		for _, procName := range lb.targetProcesses() {
+			certPath, _ := lb.certManager.GetPaths(procName)
+			caFiles = append(caFiles, certPath)
+		}
+	}
+
+	certFile, keyFile := cfg.ClientCertFile, cfg.ClientKeyFile
+	if certFile == "" && cfg.Mutual {
+		if lb.certManager == nil {
+			return nil, fmt.Errorf("backend_tls.client_cert_file is required without a certificate manager")
+		}
+		var err error
+		var hosts []string
+		if lb.config.TLS != nil {
+			hosts = lb.config.TLS.Hosts
+		}
+		certFile, keyFile, err = lb.generatedCertificate(hosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reloader, err := newCertReloader(certFile, keyFile, caFiles)
+	if err != nil {
+		return nil, err
+	}
+	lb.reloaders = append(lb.reloaders, reloader)
+
+	serverName := cfg.ServerName
+	if serverName == "" {
+		serverName = "localhost"
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// Verification happens in VerifyConnection against the current pool,
+		// which changes when process certificates are regenerated
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("backend sent no certificate")
+			}
+			opts := x509.VerifyOptions{
+				Roots:         reloader.caPool(),
+				DNSName:       serverName,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, c := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(c)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if c := reloader.certificate(); c != nil {
+				return c, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}, nil
+}
+
+// generatedCertificate returns the certificate generated for the balancer
+// ("lb-<name>" in the certs directory), creating it if needed
+func (lb *Balancer) generatedCertificate(hosts []string) (certFile, keyFile string, err error) {
+	name := "lb-" + lb.config.Name
+	if !lb.certManager.CertificateExists(name) {
+		if len(hosts) == 0 {
+			hosts = []string{"localhost", "127.0.0.1"}
+		}
+		if _, _, err := lb.certManager.GenerateForProcess(name, hosts); err != nil {
+			return "", "", fmt.Errorf("failed to generate certificate: %w", err)
+		}
+	}
+	certFile, keyFile = lb.certManager.GetPaths(name)
+	return certFile, keyFile, nil
+}
+
+// targetProcesses returns every process the balancer sends calls to
+func (lb *Balancer) targetProcesses() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, route := range lb.routes {
+		procNames := route.targetProcs
+		if route.mirror != nil {
+			procNames = append(procNames[:len(procNames):len(procNames)], route.mirror.target)
+		}
+		for _, procName := range procNames {
+			if !seen[procName] {
+				seen[procName] = true
+				names = append(names, procName)
+			}
+		}
+	}
+	return names
+}
+
+// runCertReload reloads changed certificate files until Stop
+func (lb *Balancer) runCertReload() {
+	ticker := time.NewTicker(certReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lb.stopCh:
+			return
+		case <-ticker.C:
+			for _, r := range lb.reloaders {
+				reloaded, err := r.reloadIfChanged()
+				if err != nil {
+					log.Printf("Load balancer %q: failed to reload certificates: %v", lb.config.Name, err)
+				} else if reloaded {
+					log.Printf("Load balancer %q: reloaded certificates", lb.config.Name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package loadbalancer
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+)
+
+// corsMaxAge is how long browsers may cache a preflight response; the same
+// as the gRPC-Web wrapper uses
+const corsMaxAge = 10 * time.Minute
+
+// grpcWebRequestHeaders are the request headers of the gRPC-Web protocol
+// that cross-origin calls are always allowed to send
+var grpcWebRequestHeaders = []string{
+	"Content-Type",
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"Grpc-Timeout",
+}
+
+// connectRequestHeaders are the request headers of the Connect protocol that
+// cross-origin calls are always allowed to send
+var connectRequestHeaders = []string{
+	"Content-Type",
+	"Connect-Protocol-Version",
+	"Connect-Timeout-Ms",
+	"Connect-Content-Encoding",
+	"Connect-Accept-Encoding",
+	"X-User-Agent",
+}
+
+// newWebServer creates the listener-side server for a grpc load balancer
+// that accepts browser protocols. Native gRPC (HTTP/2), gRPC-Web and Connect
+// share the port; all of them end up in proxyHandler.
+func (lb *Balancer) newWebServer(tlsConfig *tls.Config) *http.Server {
+	if web := lb.config.Web; web.GRPCWeb {
+		lb.grpcWeb = grpcweb.WrapServer(lb.server,
+			grpcweb.WithCorsForRegisteredEndpointsOnly(false),
+			grpcweb.WithOriginFunc(lb.allowedOrigin),
+			grpcweb.WithAllowedRequestHeaders(append(grpcWebRequestHeaders[:len(grpcWebRequestHeaders):len(grpcWebRequestHeaders)], web.AllowedHeaders...)),
+		)
+	}
+
+	srv := &http.Server{
+		Handler:           http.HandlerFunc(lb.serveWeb),
+		ReadHeaderTimeout: 30 * time.Second,
+		Protocols:         new(http.Protocols),
+		TLSConfig:         tlsConfig,
+	}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP2(true)
+	srv.Protocols.SetUnencryptedHTTP2(true)
+	return srv
+}
+
+// serveWeb dispatches a request to the handler of its protocol
+func (lb *Balancer) serveWeb(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case lb.grpcWeb != nil && (lb.grpcWeb.IsGrpcWebRequest(r) || lb.grpcWeb.IsAcceptableGrpcCorsRequest(r)):
+		lb.grpcWeb.ServeHTTP(w, r)
+	case r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc"):
+		lb.server.ServeHTTP(w, r)
+	case lb.config.Web.Connect:
+		lb.serveConnect(w, r)
+	default:
+		http.Error(w, "unsupported protocol", http.StatusUnsupportedMediaType)
+	}
+}
+
+// allowedOrigin reports whether cross-origin calls from origin are allowed
+func (lb *Balancer) allowedOrigin(origin string) bool {
+	for _, allowed := range lb.config.Web.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleCORS adds the CORS headers of a Connect request from an allowed
+// origin. Returns true if the request was a preflight and has been answered.
+func (lb *Balancer) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+	origin := r.Header.Get("Origin")
+	if origin == "" || !lb.allowedOrigin(origin) {
+		// Without CORS headers the browser rejects the response
+		if preflight {
+			w.WriteHeader(http.StatusForbidden)
+		}
+		return preflight
+	}
+
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Set("Access-Control-Allow-Credentials", "true")
+	h.Add("Vary", "Origin")
+	if !preflight {
+		return false
+	}
+
+	allowed := append(connectRequestHeaders[:len(connectRequestHeaders):len(connectRequestHeaders)], lb.config.Web.AllowedHeaders...)
+	h.Set("Access-Control-Allow-Methods", http.MethodPost)
+	h.Set("Access-Control-Allow-Headers", strings.Join(allowed, ", "))
+	h.Set("Access-Control-Max-Age", strconv.Itoa(int(corsMaxAge.Seconds())))
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// exposeHeaders lets cross-origin callers read the response headers set so far
+func exposeHeaders(h http.Header) {
+	if h.Get("Access-Control-Allow-Origin") == "" {
+		return
+	}
+	names := make([]string, 0, len(h))
+	for name := range h {
+		if !strings.HasPrefix(name, "Access-Control-") && name != "Vary" {
+			names = append(names, name)
+		}
+	}
+	h.Set("Access-Control-Expose-Headers", strings.Join(names, ", "))
+}
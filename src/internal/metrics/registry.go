@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metric types
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// DefaultBuckets are the default histogram buckets in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Default is the registry used by the package-level constructors
+var Default = NewRegistry()
+
+// Registry holds metric families
+type Registry struct {
+	families map[string]*family
+	hooks    []func()
+	mu       sync.RWMutex
+}
+
+// family is a named metric with a fixed set of label names
+type family struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+	mu         sync.Mutex
+}
+
+// series is a single labelled time series of a family
+type series struct {
+	labelValues []string
+	value       float64
+	count       uint64
+	sum         float64
+	bucketCount []uint64
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// register returns the family with the given name, creating it if necessary
+func (r *Registry) register(name, help, metricType string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.metricType != metricType {
+			panic(fmt.Sprintf("metric %s already registered as %s", name, f.metricType))
+		}
+		return f
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// sortedFamilies returns all families sorted by name
+func (r *Registry) sortedFamilies() []*family {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+	return families
+}
+
+// with returns the series for the given label values, creating it if necessary.
+// Callers must hold f.mu.
+func (f *family) with(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.metricType == TypeHistogram {
+			s.bucketCount = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// reset removes all series of the family
+func (f *family) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.series = make(map[string]*series)
+}
+
+// CounterVec is a monotonically increasing counter partitioned by labels
+type CounterVec struct {
+	f *family
+}
+
+// NewCounterVec registers a counter in the default registry
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labelNames...)
+}
+
+// NewCounterVec registers a counter in the registry
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{f: r.register(name, help, TypeCounter, nil, labelNames)}
+}
+
+// Inc increments the counter by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter by v (negative values are ignored)
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.with(labelValues).value += v
+}
+
+// Reset removes all series of the counter
+func (c *CounterVec) Reset() {
+	c.f.reset()
+}
+
+// Sum returns the total of all series whose label values match (nil matches all)
+func (c *CounterVec) Sum(match func(labelValues []string) bool) float64 {
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+
+	var total float64
+	for _, s := range c.f.series {
+		if match == nil || match(s.labelValues) {
+			total += s.value
+		}
+	}
+	return total
+}
+
+// GaugeVec is a value that can go up and down, partitioned by labels
+type GaugeVec struct {
+	f *family
+}
+
+// NewGaugeVec registers a gauge in the default registry
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labelNames...)
+}
+
+// NewGaugeVec registers a gauge in the registry
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(name, help, TypeGauge, nil, labelNames)}
+}
+
+// Set sets the gauge to v
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.with(labelValues).value = v
+}
+
+// Add adds v to the gauge
+func (g *GaugeVec) Add(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.with(labelValues).value += v
+}
+
+// Inc increments the gauge by one
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements the gauge by one
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Delete removes the series with the given label values
+func (g *GaugeVec) Delete(labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	delete(g.f.series, strings.Join(labelValues, "\xff"))
+}
+
+// Reset removes all series of the gauge
+func (g *GaugeVec) Reset() {
+	g.f.reset()
+}
+
+// HistogramVec samples observations into buckets, partitioned by labels
+type HistogramVec struct {
+	f *family
+}
+
+// NewHistogramVec registers a histogram in the default registry.
+// If buckets is nil, DefaultBuckets is used.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labelNames...)
+}
+
+// NewHistogramVec registers a histogram in the registry.
+// If buckets is nil, DefaultBuckets is used.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &HistogramVec{f: r.register(name, help, TypeHistogram, sorted, labelNames)}
+}
+
+// Observe adds a single observation
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	if math.IsNaN(v) {
+		return
+	}
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	s := h.f.with(labelValues)
+	s.count++
+	s.sum += v
+	for i, upper := range h.f.buckets {
+		if v <= upper {
+			s.bucketCount[i]++
+		}
+	}
+}
+
+// Reset removes all series of the histogram
+func (h *HistogramVec) Reset() {
+	h.f.reset()
+}
@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"strconv"
+	"time"
+
+	gopsutilProcess "github.com/shirou/gopsutil/v4/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/update"
+)
+
+var (
+	instanceCPU = metrics.NewGaugeVec("gowinproc_instance_cpu_percent",
+		"CPU usage of a process instance in percent over the last sample interval", "process", "instance")
+	instanceMemory = metrics.NewGaugeVec("gowinproc_instance_memory_bytes",
+		"Resident memory of a process instance in bytes", "process", "instance")
+	instanceThreads = metrics.NewGaugeVec("gowinproc_instance_threads",
+		"Number of threads of a process instance", "process", "instance")
+	instanceUptime = metrics.NewGaugeVec("gowinproc_instance_uptime_seconds",
+		"Seconds since a process instance was started", "process", "instance")
+	instanceInfo = metrics.NewGaugeVec("gowinproc_instance_info",
+		"Process instance metadata, always 1", "process", "instance", "pid", "port", "status", "version")
+	processInstances = metrics.NewGaugeVec("gowinproc_process_instances",
+		"Number of instances of a process by status", "process", "status")
+	processRestarts = metrics.NewCounterVec("gowinproc_process_restarts_total",
+		"Automatic restarts of failed instances since startup", "process")
+	updateStage = metrics.NewGaugeVec("gowinproc_update_stage",
+		"Current or last update stage of a process, always 1", "process", "stage")
+	updateProgress = metrics.NewGaugeVec("gowinproc_update_progress_percent",
+		"Progress of the current or last update of a process", "process")
+)
+
+// RegisterCollector refreshes the per-process gauges of the default metrics
+// registry on every scrape. CPU usage is taken from the sampler's latest
+// samples.
+func RegisterCollector(procMgr *process.Manager, updateMgr *update.Manager, sampler *Sampler) {
+	metrics.OnCollect(func() {
+		collect(procMgr, updateMgr, sampler)
+	})
+}
+
+// collect samples all managed processes into the process gauges
+func collect(procMgr *process.Manager, updateMgr *update.Manager, sampler *Sampler) {
+	for _, g := range []*metrics.GaugeVec{
+		instanceCPU, instanceMemory, instanceThreads, instanceUptime, instanceInfo,
+		processInstances, updateStage, updateProgress,
+	} {
+		g.Reset()
+	}
+	processRestarts.Reset()
+
+	for _, name := range procMgr.ListProcesses() {
+		processRestarts.Add(float64(procMgr.GetRestartCount(name)), name)
+
+		if updateMgr != nil {
+			if status, exists := updateMgr.GetUpdateStatus(name); exists {
+				updateStage.Set(1, name, status.Stage)
+				updateProgress.Set(status.Progress, name)
+			}
+		}
+
+		instances, err := procMgr.GetProcessStatus(name)
+		if err != nil {
+			continue
+		}
+
+		// CPU percent is a rate between samples; gopsutil's CPUPercent would
+		// be the average over the process's lifetime
+		cpu := make(map[string]float64)
+		for _, sample := range sampler.Latest(name) {
+			cpu[sample.InstanceID] = sample.CPUPercent
+		}
+
+		byStatus := make(map[string]int)
+		for _, inst := range instances {
+			status := string(inst.GetStatus())
+			byStatus[status]++
+
+			instanceInfo.Set(1, name, inst.ID, strconv.Itoa(inst.PID), strconv.Itoa(inst.Port), status, inst.Version)
+			instanceUptime.Set(time.Since(inst.StartTime).Seconds(), name, inst.ID)
+
+			if percent, ok := cpu[inst.ID]; ok {
+				instanceCPU.Set(percent, name, inst.ID)
+			}
+
+			proc, err := gopsutilProcess.NewProcess(int32(inst.PID))
+			if err != nil {
+				continue
+			}
+			if mem, err := proc.MemoryInfo(); err == nil && mem != nil {
+				instanceMemory.Set(float64(mem.RSS), name, inst.ID)
+			}
+			if threads, err := proc.NumThreads(); err == nil {
+				instanceThreads.Set(float64(threads), name, inst.ID)
+			}
+		}
+		for status, count := range byStatus {
+			processInstances.Set(float64(count), name, status)
+		}
+	}
+}
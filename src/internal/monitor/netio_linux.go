@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+
+	gopsutilNet "github.com/shirou/gopsutil/v4/net"
+)
+
+// processNetIO returns cumulative network bytes received and sent by a process.
+// Linux only has per-namespace counters, so they are reported only for processes
+// running in their own network namespace (e.g. containers).
+func processNetIO(pid int32) (recv, sent uint64, ok bool) {
+	own, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		return 0, 0, false
+	}
+	self, err := os.Readlink("/proc/self/ns/net")
+	if err != nil || own == self {
+		return 0, 0, false
+	}
+
+	counters, err := gopsutilNet.IOCountersByFile(true, fmt.Sprintf("/proc/%d/net/dev", pid))
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, c := range counters {
+		if c.Name == "lo" {
+			continue
+		}
+		recv += c.BytesRecv
+		sent += c.BytesSent
+	}
+	return recv, sent, true
+}
@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package monitor
+
+// processNetIO returns cumulative network bytes received and sent by a process.
+// Per-process network counters are not available on this OS.
+func processNetIO(pid int32) (recv, sent uint64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,8 @@
+package monitor
+
+// processNetIO returns cumulative network bytes received and sent by a process.
+// Windows has no per-process network counters without ETW tracing, so this is
+// reported as unsupported.
+func processNetIO(pid int32) (recv, sent uint64, ok bool) {
+	return 0, 0, false
+}
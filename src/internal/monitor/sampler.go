@@ -0,0 +1,455 @@
+package monitor
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	gopsutilNet "github.com/shirou/gopsutil/v4/net"
+	gopsutilProcess "github.com/shirou/gopsutil/v4/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/storage"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// persistInterval is how often the metrics history is written to disk
+const persistInterval = 5 * time.Minute
+
+// Metric names recorded in the history store
+const (
+	MetricCPUPercent     = "cpu_percent"
+	MetricMemoryBytes    = "memory_bytes"
+	MetricThreads        = "threads"
+	MetricOpenHandles    = "open_handles"
+	MetricDiskReadRate   = "disk_read_bytes_per_sec"
+	MetricDiskWriteRate  = "disk_write_bytes_per_sec"
+	MetricNetRecvRate    = "network_recv_bytes_per_sec"
+	MetricNetSentRate    = "network_sent_bytes_per_sec"
+	MetricTCPConnections = "tcp_connections"
+)
+
+// Sample is a point-in-time resource sample of a process instance
+type Sample struct {
+	Process     string    `json:"process"`
+	InstanceID  string    `json:"instance_id"`
+	PID         int       `json:"pid"`
+	Port        int       `json:"port"`
+	StartTime   time.Time `json:"start_time"`
+	Timestamp   time.Time `json:"timestamp"`
+	CPUPercent  float64   `json:"cpu_percent"`
+	MemoryBytes uint64    `json:"memory_bytes"`
+	Threads     int32     `json:"threads"`
+	OpenHandles int32     `json:"open_handles"` // handles on Windows, file descriptors elsewhere
+
+	// Cumulative I/O counters since the process started
+	DiskReadBytes  uint64 `json:"disk_read_bytes"`
+	DiskWriteBytes uint64 `json:"disk_write_bytes"`
+	DiskReadOps    uint64 `json:"disk_read_ops"`
+	DiskWriteOps   uint64 `json:"disk_write_ops"`
+	NetRecvBytes   uint64 `json:"network_recv_bytes"`
+	NetSentBytes   uint64 `json:"network_sent_bytes"`
+	NetSupported   bool   `json:"network_supported"` // false if the OS has no per-process network counters
+
+	// Per-second rates since the previous sample (0 for the first sample)
+	DiskReadRate  float64 `json:"disk_read_rate"`
+	DiskWriteRate float64 `json:"disk_write_rate"`
+	NetRecvRate   float64 `json:"network_recv_rate"`
+	NetSentRate   float64 `json:"network_sent_rate"`
+
+	// TCP connections on the instance port by state (e.g. LISTEN, ESTABLISHED)
+	TCPConnections map[string]int `json:"tcp_connections"`
+}
+
+// TotalTCPConnections returns the number of TCP connections on the instance port
+func (s Sample) TotalTCPConnections() int {
+	total := 0
+	for _, n := range s.TCPConnections {
+		total += n
+	}
+	return total
+}
+
+// values returns the sample as metric name/value pairs for the history store
+func (s Sample) values() map[string]float64 {
+	v := map[string]float64{
+		MetricCPUPercent:     s.CPUPercent,
+		MetricMemoryBytes:    float64(s.MemoryBytes),
+		MetricThreads:        float64(s.Threads),
+		MetricOpenHandles:    float64(s.OpenHandles),
+		MetricDiskReadRate:   s.DiskReadRate,
+		MetricDiskWriteRate:  s.DiskWriteRate,
+		MetricTCPConnections: float64(s.TotalTCPConnections()),
+	}
+	if s.NetSupported {
+		v[MetricNetRecvRate] = s.NetRecvRate
+		v[MetricNetSentRate] = s.NetSentRate
+	}
+	return v
+}
+
+// add accumulates another instance sample into a process total
+func (s *Sample) add(o Sample) {
+	s.CPUPercent += o.CPUPercent
+	s.MemoryBytes += o.MemoryBytes
+	s.Threads += o.Threads
+	s.OpenHandles += o.OpenHandles
+	s.DiskReadBytes += o.DiskReadBytes
+	s.DiskWriteBytes += o.DiskWriteBytes
+	s.DiskReadOps += o.DiskReadOps
+	s.DiskWriteOps += o.DiskWriteOps
+	s.NetRecvBytes += o.NetRecvBytes
+	s.NetSentBytes += o.NetSentBytes
+	s.NetSupported = s.NetSupported || o.NetSupported
+	s.DiskReadRate += o.DiskReadRate
+	s.DiskWriteRate += o.DiskWriteRate
+	s.NetRecvRate += o.NetRecvRate
+	s.NetSentRate += o.NetSentRate
+
+	if s.TCPConnections == nil {
+		s.TCPConnections = make(map[string]int)
+	}
+	for state, n := range o.TCPConnections {
+		s.TCPConnections[state] += n
+	}
+}
+
+// Total sums instance samples into a process total
+func Total(processName string, samples []Sample) Sample {
+	total := Sample{Process: processName, InstanceID: storage.AggregateInstance}
+	for _, sample := range samples {
+		total.add(sample)
+		if sample.Timestamp.After(total.Timestamp) {
+			total.Timestamp = sample.Timestamp
+		}
+	}
+	return total
+}
+
+// Sampler periodically samples all running instances and records the results
+// in a time-series store
+type Sampler struct {
+	procMgr  *process.Manager
+	store    *storage.TimeSeriesStore
+	interval time.Duration
+
+	// gopsutil handles and previous samples are kept between samples so CPU
+	// percent and I/O rates cover the interval
+	handles  map[int32]*gopsutilProcess.Process
+	prev     map[int32]Sample
+	latest   map[string][]Sample // process name -> latest samples
+	latestAt time.Time
+	mu       sync.RWMutex
+
+	// Stream subscribers, notified after every sample
+	subscribers map[chan time.Time]struct{}
+	subMu       sync.Mutex
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSampler creates a new background sampler
+func NewSampler(procMgr *process.Manager, store *storage.TimeSeriesStore, interval time.Duration) *Sampler {
+	return &Sampler{
+		procMgr:  procMgr,
+		store:    store,
+		interval: interval,
+		handles:  make(map[int32]*gopsutilProcess.Process),
+		prev:     make(map[int32]Sample),
+		latest:   make(map[string][]Sample),
+		stopCh:   make(chan struct{}),
+
+		subscribers: make(map[chan time.Time]struct{}),
+	}
+}
+
+// Interval returns the sampling interval
+func (s *Sampler) Interval() time.Duration {
+	return s.interval
+}
+
+// Start starts the sampling loop
+func (s *Sampler) Start() {
+	s.wg.Add(1)
+	go s.run()
+	log.Printf("[Sampler] Started (interval: %v)", s.interval)
+}
+
+// Stop stops the sampling loop and persists the history
+func (s *Sampler) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+
+	if err := s.store.Save(); err != nil {
+		log.Printf("[Sampler] Warning: failed to save metrics history: %v", err)
+	}
+	log.Println("[Sampler] Stopped")
+}
+
+// Latest returns the most recent samples of a process
+func (s *Sampler) Latest(processName string) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.latest[processName]
+	out := make([]Sample, len(samples))
+	copy(out, samples)
+	return out
+}
+
+// LatestAll returns the most recent samples of all processes and when they were taken
+func (s *Sampler) LatestAll() (map[string][]Sample, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string][]Sample, len(s.latest))
+	for name, samples := range s.latest {
+		out[name] = append([]Sample(nil), samples...)
+	}
+	return out, s.latestAt
+}
+
+// Subscribe returns a channel that receives the sample time after every
+// sampling round. Slow subscribers miss rounds rather than blocking the sampler.
+// The returned function must be called to unsubscribe.
+func (s *Sampler) Subscribe() (<-chan time.Time, func()) {
+	ch := make(chan time.Time, 1)
+
+	s.subMu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.subMu.Unlock()
+
+	return ch, func() {
+		s.subMu.Lock()
+		delete(s.subscribers, ch)
+		s.subMu.Unlock()
+	}
+}
+
+// notify wakes all subscribers after a sampling round
+func (s *Sampler) notify(at time.Time) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- at:
+		default:
+		}
+	}
+}
+
+// run samples on every tick until stopped
+func (s *Sampler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	persist := time.NewTicker(persistInterval)
+	defer persist.Stop()
+
+	s.sample()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.sample()
+		case <-persist.C:
+			if err := s.store.Save(); err != nil {
+				log.Printf("[Sampler] Warning: failed to save metrics history: %v", err)
+			}
+		}
+	}
+}
+
+// sample collects one sample of every running instance
+func (s *Sampler) sample() {
+	now := time.Now()
+	latest := make(map[string][]Sample)
+	seen := make(map[int32]bool)
+	conns := tcpConnectionsByPort()
+
+	for _, name := range s.procMgr.ListProcesses() {
+		instances, err := s.procMgr.GetProcessStatus(name)
+		if err != nil {
+			continue
+		}
+
+		total := Sample{Process: name, InstanceID: storage.AggregateInstance, Timestamp: now}
+		var samples []Sample
+		for _, inst := range instances {
+			if inst.PID == 0 {
+				continue
+			}
+			pid := int32(inst.PID)
+			seen[pid] = true
+
+			proc := s.handle(pid)
+			if proc == nil {
+				continue
+			}
+
+			sample := newSample(name, inst, now)
+			if cpu, err := proc.Percent(0); err == nil {
+				sample.CPUPercent = cpu
+			}
+			readCounters(proc, &sample)
+			sample.TCPConnections = conns[inst.Port]
+
+			s.mu.RLock()
+			prev, hasPrev := s.prev[pid]
+			s.mu.RUnlock()
+			if hasPrev {
+				computeRates(&sample, prev)
+			}
+
+			s.mu.Lock()
+			s.prev[pid] = sample
+			s.mu.Unlock()
+
+			samples = append(samples, sample)
+			total.add(sample)
+		}
+
+		latest[name] = samples
+		for _, sample := range samples {
+			s.record(sample)
+		}
+		if len(samples) > 0 {
+			s.record(total)
+		}
+	}
+	s.store.Prune(now)
+
+	s.mu.Lock()
+	s.latest = latest
+	s.latestAt = now
+	for pid := range s.handles {
+		if !seen[pid] {
+			delete(s.handles, pid)
+			delete(s.prev, pid)
+		}
+	}
+	s.mu.Unlock()
+
+	s.notify(now)
+}
+
+// handle returns the cached gopsutil handle of a PID
+func (s *Sampler) handle(pid int32) *gopsutilProcess.Process {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if proc, ok := s.handles[pid]; ok {
+		return proc
+	}
+	proc, err := gopsutilProcess.NewProcess(pid)
+	if err != nil {
+		return nil
+	}
+	s.handles[pid] = proc
+	return proc
+}
+
+// record appends a sample to the history store
+func (s *Sampler) record(sample Sample) {
+	for metric, value := range sample.values() {
+		s.store.Append(storage.SeriesKey{
+			Process:  sample.Process,
+			Instance: sample.InstanceID,
+			Metric:   metric,
+		}, sample.Timestamp, value)
+	}
+}
+
+// CollectSample takes a one-off sample of an instance. CPU percent is the
+// average since the process started and rates are not available.
+func CollectSample(processName string, inst *models.ProcessInstance) (Sample, error) {
+	proc, err := gopsutilProcess.NewProcess(int32(inst.PID))
+	if err != nil {
+		return Sample{}, err
+	}
+
+	sample := newSample(processName, inst, time.Now())
+	if cpu, err := proc.CPUPercent(); err == nil {
+		sample.CPUPercent = cpu
+	}
+	readCounters(proc, &sample)
+	sample.TCPConnections = tcpConnectionsByPort()[inst.Port]
+	return sample, nil
+}
+
+// newSample creates an empty sample for an instance
+func newSample(processName string, inst *models.ProcessInstance, now time.Time) Sample {
+	return Sample{
+		Process:    processName,
+		InstanceID: inst.ID,
+		PID:        inst.PID,
+		Port:       inst.Port,
+		StartTime:  inst.StartTime,
+		Timestamp:  now,
+	}
+}
+
+// readCounters fills in memory, thread, handle and I/O counters
+func readCounters(proc *gopsutilProcess.Process, sample *Sample) {
+	if mem, err := proc.MemoryInfo(); err == nil && mem != nil {
+		sample.MemoryBytes = mem.RSS
+	}
+	if threads, err := proc.NumThreads(); err == nil {
+		sample.Threads = threads
+	}
+	if handles, err := proc.NumFDs(); err == nil {
+		sample.OpenHandles = handles
+	}
+	if io, err := proc.IOCounters(); err == nil && io != nil {
+		sample.DiskReadBytes = io.ReadBytes
+		sample.DiskWriteBytes = io.WriteBytes
+		sample.DiskReadOps = io.ReadCount
+		sample.DiskWriteOps = io.WriteCount
+	}
+	sample.NetRecvBytes, sample.NetSentBytes, sample.NetSupported = processNetIO(proc.Pid)
+}
+
+// computeRates sets the per-second rates of a sample from the previous sample
+func computeRates(sample *Sample, prev Sample) {
+	elapsed := sample.Timestamp.Sub(prev.Timestamp).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	rate := func(cur, old uint64) float64 {
+		if cur < old {
+			return 0 // counter reset
+		}
+		return float64(cur-old) / elapsed
+	}
+
+	sample.DiskReadRate = rate(sample.DiskReadBytes, prev.DiskReadBytes)
+	sample.DiskWriteRate = rate(sample.DiskWriteBytes, prev.DiskWriteBytes)
+	if sample.NetSupported && prev.NetSupported {
+		sample.NetRecvRate = rate(sample.NetRecvBytes, prev.NetRecvBytes)
+		sample.NetSentRate = rate(sample.NetSentBytes, prev.NetSentBytes)
+	}
+}
+
+// tcpConnectionsByPort counts TCP connections by local port and state
+func tcpConnectionsByPort() map[int]map[string]int {
+	result := make(map[int]map[string]int)
+
+	conns, err := gopsutilNet.Connections("tcp")
+	if err != nil {
+		return result
+	}
+	for _, c := range conns {
+		port := int(c.Laddr.Port)
+		if port == 0 {
+			continue
+		}
+		if result[port] == nil {
+			result[port] = make(map[string]int)
+		}
+		result[port][c.Status]++
+	}
+	return result
+}
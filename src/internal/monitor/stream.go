@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// StreamInterval rounds a client-requested stream interval up to a multiple of
+// the sampling interval. Zero or negative requests use the sampling interval.
+func (s *Sampler) StreamInterval(requested time.Duration) time.Duration {
+	if requested <= s.interval {
+		return s.interval
+	}
+	rounds := (requested + s.interval - 1) / s.interval
+	return rounds * s.interval
+}
+
+// Watch calls fn with the latest samples of all processes once immediately and
+// then after sampling rounds at most every interval, until ctx is done or fn
+// returns an error. Watchers share the sampler's samples and never trigger
+// sampling themselves.
+func (s *Sampler) Watch(ctx context.Context, interval time.Duration, fn func(samples map[string][]Sample, at time.Time) error) error {
+	interval = s.StreamInterval(interval)
+
+	updates, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	var lastSent time.Time
+	if samples, at := s.LatestAll(); !at.IsZero() {
+		if err := fn(samples, at); err != nil {
+			return err
+		}
+		lastSent = at
+	}
+
+	// Allow some jitter between rounds so a stream at the sampling interval doesn't skip every other one
+	slack := s.interval / 4
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case at := <-updates:
+			if !lastSent.IsZero() && at.Sub(lastSent) < interval-slack {
+				continue
+			}
+			samples, at := s.LatestAll()
+			if err := fn(samples, at); err != nil {
+				return err
+			}
+			lastSent = at
+		}
+	}
+}
@@ -10,9 +10,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/github"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/update"
 )
 
+var pollResults = metrics.NewCounterVec("gowinproc_poller_checks_total",
+	"GitHub poller checks by result (up_to_date, update_triggered, no_release, error)", "process", "result")
+
 // GitHubPoller polls GitHub API for version updates
 type GitHubPoller struct {
 	interval      time.Duration
@@ -94,20 +99,23 @@ func (p *GitHubPoller) pollLoop() {
 // pollAll polls version information for all configured processes
 func (p *GitHubPoller) pollAll() {
 	for _, proc := range p.processes {
-		if err := p.pollProcess(proc); err != nil {
+		result, err := p.pollProcess(proc)
+		if err != nil {
 			log.Printf("Failed to poll %s: %v", proc.Name, err)
+			result = "error"
 		}
+		pollResults.Inc(proc.Name, result)
 	}
 }
 
-// pollProcess polls version information for a single process
-func (p *GitHubPoller) pollProcess(proc ProcessConfig) error {
+// pollProcess polls version information for a single process and returns the check result
+func (p *GitHubPoller) pollProcess(proc ProcessConfig) (string, error) {
 	// Fetch latest release from GitHub API
 	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", proc.Repository)
 
 	req, err := http.NewRequestWithContext(p.ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set GitHub API headers
@@ -125,43 +133,49 @@ func (p *GitHubPoller) pollProcess(proc ProcessConfig) error {
 	req.Header.Set("User-Agent", "gowinproc")
 
 	resp, err := p.httpClient.Do(req)
+	github.ObserveResponse(resp, err)
 	if err != nil {
-		return fmt.Errorf("failed to fetch release: %w", err)
+		return "", fmt.Errorf("failed to fetch release: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
 		// No releases published yet
 		log.Printf("No releases found for %s (repository may not have any releases yet)", proc.Repository)
-		return nil
+		return "no_release", nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
 	}
 
 	var release GitHubRelease
 	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	log.Printf("Found release for %s: %s", proc.Repository, release.TagName)
 
 	// Check if update is needed
-	if err := p.checkAndUpdate(proc.Name, release.TagName); err != nil {
-		return fmt.Errorf("failed to check/update: %w", err)
+	triggered, err := p.checkAndUpdate(proc.Name, release.TagName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check/update: %w", err)
 	}
 
-	return nil
+	if triggered {
+		return "update_triggered", nil
+	}
+	return "up_to_date", nil
 }
 
-// checkAndUpdate checks if an update is needed and triggers it
-func (p *GitHubPoller) checkAndUpdate(processName, latestVersion string) error {
+// checkAndUpdate checks if an update is needed and triggers it.
+// Returns true if an update was triggered.
+func (p *GitHubPoller) checkAndUpdate(processName, latestVersion string) (bool, error) {
 	// Check if an update is already in progress
 	if status, exists := p.updateManager.GetUpdateStatus(processName); exists && !status.Completed {
 		// Update already in progress
-		return nil
+		return false, nil
 	}
 
 	// Find repository for this process
@@ -173,13 +187,13 @@ func (p *GitHubPoller) checkAndUpdate(processName, latestVersion string) error {
 		}
 	}
 	if repository == "" {
-		return fmt.Errorf("repository not found for process %s", processName)
+		return false, fmt.Errorf("repository not found for process %s", processName)
 	}
 
 	// Check for available updates through update manager
 	versionInfo, err := p.updateManager.CheckForUpdates(processName, repository)
 	if err != nil {
-		return fmt.Errorf("failed to check for updates: %w", err)
+		return false, fmt.Errorf("failed to check for updates: %w", err)
 	}
 
 	// If update is available, trigger automatic update
@@ -195,13 +209,14 @@ func (p *GitHubPoller) checkAndUpdate(processName, latestVersion string) error {
 
 		// Trigger update with latest version
 		if err := p.updateManager.UpdateProcess(processName, versionInfo.LatestVersion.Tag, false); err != nil {
-			return fmt.Errorf("failed to trigger update: %w", err)
+			return false, fmt.Errorf("failed to trigger update: %w", err)
 		}
 
 		log.Printf("Auto-update triggered for %s to version %s", processName, versionInfo.LatestVersion.Tag)
+		return true, nil
 	} else if versionInfo != nil && versionInfo.CurrentVersion != nil {
 		log.Printf("Process %s is up-to-date at version %s", processName, versionInfo.CurrentVersion.Tag)
 	}
 
-	return nil
+	return false, nil
 }
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// StartReadyInstances starts count instances and waits until all of them are
+// ready, as rolling updates do for each batch. If any fails to become ready,
+// all of them are stopped.
+func (m *Manager) StartReadyInstances(processName string, count int, opts StartOptions) ([]*models.ProcessInstance, error) {
+	procConfig, err := m.GetProcessConfig(processName)
+	if err != nil {
+		return nil, err
+	}
+	opts.AllowExceedMax = true
+	return m.startBatch(processName, count, opts, &procConfig)
+}
+
+// SwitchTraffic routes the traffic of load balancers and /proxy/ to the
+// active instances and away from the standby ones in a single step
+func (m *Manager) SwitchTraffic(processName string, active, standby []*models.ProcessInstance) error {
+	m.mu.RLock()
+	managedProc, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", processName)
+	}
+
+	managedProc.SetStandby(instanceIDs(active), instanceIDs(standby))
+	return nil
+}
+
+// GetRoutableInstances returns the running instances of a process that
+// receive traffic, leaving out standby instances and those of a process
+// scaled to zero
+func (m *Manager) GetRoutableInstances(processName string) ([]*models.ProcessInstance, error) {
+	m.mu.RLock()
+	managedProc, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("process %s not found", processName)
+	}
+
+	// Instances of a process scaled to zero are being stopped; callers wake it
+	if m.IsScaledToZero(processName) {
+		return nil, nil
+	}
+	return managedProc.GetRoutableInstances(), nil
+}
+
+func instanceIDs(instances []*models.ProcessInstance) []string {
+	ids := make([]string, len(instances))
+	for i, inst := range instances {
+		ids[i] = inst.ID
+	}
+	return ids
+}
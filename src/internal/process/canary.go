@@ -0,0 +1,63 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// SetCanary sends percent of the traffic of load balancers and /proxy/ for a
+// process to the canary instances and the rest to the others. No instances
+// end the split.
+func (m *Manager) SetCanary(processName string, canary []*models.ProcessInstance, percent int) error {
+	m.mu.RLock()
+	managedProc, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", processName)
+	}
+
+	managedProc.SetCanary(instanceIDs(canary), percent)
+	return nil
+}
+
+// TrafficSplit returns the canary instances of a process and the percentage
+// of its traffic they receive (nil if there is no canary). The map must not be
+// modified.
+func (m *Manager) TrafficSplit(processName string) (map[string]bool, int) {
+	m.mu.RLock()
+	managedProc, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, 0
+	}
+	return managedProc.GetCanary()
+}
+
+// SplitInstances narrows the instances a call may go to down to the canary
+// instances or the others, as drawn by draw(100) against the canary's share.
+// If the drawn group has no instances the call goes to the other group.
+func (m *Manager) SplitInstances(processName string, instances []*models.ProcessInstance, draw func(int) int) []*models.ProcessInstance {
+	canary, percent := m.TrafficSplit(processName)
+	if canary == nil {
+		return instances
+	}
+
+	var canaries, others []*models.ProcessInstance
+	for _, inst := range instances {
+		if canary[inst.ID] {
+			canaries = append(canaries, inst)
+		} else {
+			others = append(others, inst)
+		}
+	}
+	if len(canaries) == 0 || len(others) == 0 {
+		return instances
+	}
+	if draw(100) < percent {
+		return canaries
+	}
+	return others
+}
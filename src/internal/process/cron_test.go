@@ -0,0 +1,254 @@
+package process
+
+import (
+	"testing"
+	"time"
+)
+
+// cronBits returns a bitset with the given values set
+func cronBits(values ...int) uint64 {
+	var bits uint64
+	for _, v := range values {
+		bits |= 1 << uint(v)
+	}
+	return bits
+}
+
+func TestParseCronFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		field func(*cronSchedule) uint64
+		want  uint64
+	}{
+		{"single minute", "5 * * * *", func(s *cronSchedule) uint64 { return s.minute }, cronBits(5)},
+		{"minute list", "0,30 * * * *", func(s *cronSchedule) uint64 { return s.minute }, cronBits(0, 30)},
+		{"minute step", "*/15 * * * *", func(s *cronSchedule) uint64 { return s.minute }, cronBits(0, 15, 30, 45)},
+		{"step from value", "5/20 * * * *", func(s *cronSchedule) uint64 { return s.minute }, cronBits(5, 25, 45)},
+		{"hour range", "0 8-11 * * *", func(s *cronSchedule) uint64 { return s.hour }, cronBits(8, 9, 10, 11)},
+		{"hour range step", "0 8-18/2 * * *", func(s *cronSchedule) uint64 { return s.hour }, cronBits(8, 10, 12, 14, 16, 18)},
+		{"month names", "0 0 1 jan,jul *", func(s *cronSchedule) uint64 { return s.month }, cronBits(1, 7)},
+		{"weekday range", "0 0 * * mon-fri", func(s *cronSchedule) uint64 { return s.dow }, cronBits(1, 2, 3, 4, 5)},
+		{"names ignore case", "0 0 * * SAT,Sun", func(s *cronSchedule) uint64 { return s.dow }, cronBits(0, 6)},
+		{"7 is sunday", "0 0 * * 7", func(s *cronSchedule) uint64 { return s.dow }, cronBits(0, 7)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			if got := tt.field(s); got != tt.want {
+				t.Errorf("parseCron(%q) = %b, want %b", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"0 8 * *",
+		"0 8 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"* * * foo *",
+		"* * * * mon-",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := parseCron(expr); err == nil {
+				t.Errorf("parseCron(%q) succeeded, want error", expr)
+			}
+		})
+	}
+}
+
+func TestCronMatchesDay(t *testing.T) {
+	// 2026-02-13 is a Friday, 2026-02-14 a Saturday and 2026-02-20 a Friday
+	fri13 := time.Date(2026, 2, 13, 0, 0, 0, 0, time.UTC)
+	sat14 := time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC)
+	fri20 := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want [3]bool // fri13, sat14, fri20
+	}{
+		{"0 0 * * *", [3]bool{true, true, true}},
+		{"0 0 13 * *", [3]bool{true, false, false}},
+		{"0 0 * * fri", [3]bool{true, false, true}},
+		// Both restricted: either field may match
+		{"0 0 14 * fri", [3]bool{true, true, true}},
+		{"0 0 1 * mon", [3]bool{false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			for i, day := range []time.Time{fri13, sat14, fri20} {
+				if got := s.matchesDay(day); got != tt.want[i] {
+					t.Errorf("matchesDay(%s) = %v, want %v", day.Format("2006-01-02"), got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCronNextPrev(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		next time.Time
+		prev time.Time
+	}{
+		{
+			name: "same hour",
+			expr: "30 * * * *",
+			from: utc(2026, 3, 10, 8, 15),
+			next: utc(2026, 3, 10, 8, 30),
+			prev: utc(2026, 3, 10, 7, 30),
+		},
+		{
+			name: "exact match",
+			expr: "0 8 * * *",
+			from: utc(2026, 3, 10, 8, 0),
+			next: utc(2026, 3, 11, 8, 0),
+			prev: utc(2026, 3, 10, 8, 0),
+		},
+		{
+			name: "end of month",
+			expr: "0 0 * * *",
+			from: utc(2026, 1, 31, 23, 59),
+			next: utc(2026, 2, 1, 0, 0),
+			prev: utc(2026, 1, 31, 0, 0),
+		},
+		{
+			name: "end of year",
+			expr: "0 12 1 1 *",
+			from: utc(2026, 12, 31, 12, 0),
+			next: utc(2027, 1, 1, 12, 0),
+			prev: utc(2026, 1, 1, 12, 0),
+		},
+		{
+			name: "skips months without the day",
+			expr: "0 0 31 * *",
+			from: utc(2026, 4, 15, 0, 0),
+			next: utc(2026, 5, 31, 0, 0),
+			prev: utc(2026, 3, 31, 0, 0),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 feb *",
+			from: utc(2026, 3, 1, 0, 0),
+			next: utc(2028, 2, 29, 0, 0),
+			prev: utc(2024, 2, 29, 0, 0),
+		},
+		{
+			name: "weekdays over a weekend",
+			expr: "0 8 * * mon-fri",
+			from: utc(2026, 2, 13, 9, 0), // Friday
+			next: utc(2026, 2, 16, 8, 0), // Monday
+			prev: utc(2026, 2, 13, 8, 0),
+		},
+		{
+			name: "never matches",
+			expr: "0 0 30 feb *",
+			from: utc(2026, 1, 1, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.next) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.next)
+			}
+			if got := s.Prev(tt.from); !got.Equal(tt.prev) {
+				t.Errorf("Prev(%v) = %v, want %v", tt.from, got, tt.prev)
+			}
+		})
+	}
+}
+
+func TestCronNextPrevDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	local := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+
+	// Clocks go from 02:00 to 03:00 on 2026-03-08 and from 02:00 back to
+	// 01:00 on 2026-11-01
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		next time.Time
+		prev time.Time
+	}{
+		{
+			name: "wall clock kept over spring forward",
+			expr: "0 8 * * *",
+			from: local(2026, 3, 7, 12, 0),
+			next: local(2026, 3, 8, 8, 0),
+			prev: local(2026, 3, 7, 8, 0),
+		},
+		{
+			name: "wall clock kept over fall back",
+			expr: "0 8 * * *",
+			from: local(2026, 10, 31, 12, 0),
+			next: local(2026, 11, 1, 8, 0),
+			prev: local(2026, 10, 31, 8, 0),
+		},
+		{
+			name: "skipped time is not run that day",
+			expr: "30 2 * * *",
+			from: local(2026, 3, 8, 1, 0),
+			next: local(2026, 3, 9, 2, 30),
+			prev: local(2026, 3, 7, 2, 30),
+		},
+		{
+			name: "first hour after spring forward",
+			expr: "0 3 * * *",
+			from: local(2026, 3, 8, 1, 0),
+			next: local(2026, 3, 8, 3, 0),
+			prev: local(2026, 3, 7, 3, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.next) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.next)
+			}
+			if got := s.Prev(tt.from); !got.Equal(tt.prev) {
+				t.Errorf("Prev(%v) = %v, want %v", tt.from, got, tt.prev)
+			}
+		})
+	}
+}
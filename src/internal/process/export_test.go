@@ -0,0 +1,8 @@
+package process
+
+import "time"
+
+// Tick runs one round of the supervisor's checks
+func (m *Manager) Tick(now time.Time) {
+	m.tick(now)
+}
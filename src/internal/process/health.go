@@ -0,0 +1,152 @@
+package process
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// healthState tracks health check results of an instance
+type healthState struct {
+	process     string
+	lastCheck   time.Time
+	consecutive int // failures since the last success
+	total       int // failures since the instance started
+}
+
+// HealthStatus summarizes the health check results of a process
+type HealthStatus struct {
+	ConsecutiveFailures int // highest consecutive failure count of any instance
+	TotalFailures       int // failures of all current instances since they started
+	UnhealthyInstances  int // instances with at least health_check.retries consecutive failures
+}
+
+// GetHealthStatus returns the health check results of a process
+func (m *Manager) GetHealthStatus(processName string) HealthStatus {
+	procConfig, err := m.GetProcessConfig(processName)
+	if err != nil {
+		return HealthStatus{}
+	}
+
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	var status HealthStatus
+	for _, state := range m.health {
+		if state.process != processName {
+			continue
+		}
+		if state.consecutive > status.ConsecutiveFailures {
+			status.ConsecutiveFailures = state.consecutive
+		}
+		status.TotalFailures += state.total
+		if state.consecutive >= procConfig.HealthCheck.Retries {
+			status.UnhealthyInstances++
+		}
+	}
+	return status
+}
+
+// checkHealth probes running instances of processes with health checks enabled
+// whose check interval has elapsed
+func (m *Manager) checkHealth() {
+	m.mu.RLock()
+	procs := make([]*models.ManagedProcess, 0, len(m.processes))
+	for _, proc := range m.processes {
+		procs = append(procs, proc)
+	}
+	m.mu.RUnlock()
+
+	now := time.Now()
+	live := make(map[string]bool)
+	var wg sync.WaitGroup
+
+	for _, proc := range procs {
+		cfg := proc.Config.HealthCheck
+		for _, inst := range proc.GetInstances() {
+			live[inst.ID] = true
+			if !cfg.Enabled || inst.GetStatus() != models.StatusRunning {
+				continue
+			}
+
+			m.healthMu.Lock()
+			state, ok := m.health[inst.ID]
+			if !ok {
+				state = &healthState{process: proc.Config.Name}
+				m.health[inst.ID] = state
+			}
+			due := now.Sub(state.lastCheck) >= cfg.Interval
+			if due {
+				state.lastCheck = now
+			}
+			m.healthMu.Unlock()
+
+			if !due {
+				continue
+			}
+
+			wg.Add(1)
+			go func(name string, inst *models.ProcessInstance, cfg models.HealthCheckConfig) {
+				defer wg.Done()
+				err := probe(inst.Port, cfg)
+
+				m.healthMu.Lock()
+				defer m.healthMu.Unlock()
+				state := m.health[inst.ID]
+				if state == nil {
+					return
+				}
+				if err == nil {
+					if state.consecutive >= cfg.Retries {
+						log.Printf("[Health] %s instance %s is healthy again", name, inst.ID)
+					}
+					state.consecutive = 0
+					return
+				}
+				state.consecutive++
+				state.total++
+				if state.consecutive == cfg.Retries {
+					log.Printf("[Health] %s instance %s is unhealthy after %d failed checks: %v", name, inst.ID, state.consecutive, err)
+				}
+			}(proc.Config.Name, inst, cfg)
+		}
+	}
+	wg.Wait()
+
+	// Forget instances that are gone
+	m.healthMu.Lock()
+	for id := range m.health {
+		if !live[id] {
+			delete(m.health, id)
+		}
+	}
+	m.healthMu.Unlock()
+}
+
+// probe checks an instance with an HTTP GET on the configured endpoint, or a
+// TCP connect if no endpoint is configured
+func probe(port int, cfg models.HealthCheckConfig) error {
+	if cfg.Endpoint == "" {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), cfg.Timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	resp, err := client.Get(fmt.Sprintf("http://localhost:%d%s", port, cfg.Endpoint))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
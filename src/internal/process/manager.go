@@ -36,6 +36,26 @@ type Manager struct {
 	mu             sync.RWMutex
 	ctx            context.Context
 	cancel         context.CancelFunc
+
+	// Scale-to-zero state, guarded by idleMu
+	lastActivity map[string]time.Time
+	activeCalls  map[string]int
+	scaledToZero map[string]bool
+	wakes        map[string]*wakeCall
+	idleStops    map[string]chan struct{} // closed when an idle stop is done
+	idleMu       sync.Mutex
+
+	// Scaling schedules and manual overrides, guarded by scaleMu
+	scaling        map[string]*compiledScaling
+	scaleOverrides map[string]scaleOverride
+	scaleMu        sync.Mutex
+
+	// Health check state by instance ID, guarded by healthMu
+	health   map[string]*healthState
+	healthMu sync.Mutex
+
+	// Alert rules evaluated by the supervisor (optional), guarded by mu
+	alertEvaluator AlertEvaluator
 }
 
 // VersionManager interface for version management operations
@@ -51,32 +71,55 @@ type UpdateManager interface {
 	GetUpdateStatus(processName string) (*models.UpdateStatus, bool)
 }
 
+// AlertEvaluator interface for alert rule evaluation
+type AlertEvaluator interface {
+	Evaluate(now time.Time)
+}
+
 // NewManager creates a new process manager
 func NewManager(config *models.Config, certMgr *certs.Manager, secretMgr *secrets.Manager) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Manager{
-		config:        config,
-		processes:     make(map[string]*models.ManagedProcess),
-		certManager:   certMgr,
-		secretManager: secretMgr,
-		pidTracker:    NewPIDTracker("./tracked_pids.txt"),
-		nextPort:      5001, // Start port allocation from 5001
-		ctx:           ctx,
-		cancel:        cancel,
+		config:         config,
+		processes:      make(map[string]*models.ManagedProcess),
+		certManager:    certMgr,
+		secretManager:  secretMgr,
+		pidTracker:     NewPIDTracker("./tracked_pids.txt"),
+		nextPort:       5001, // Start port allocation from 5001
+		lastActivity:   make(map[string]time.Time),
+		activeCalls:    make(map[string]int),
+		scaledToZero:   make(map[string]bool),
+		wakes:          make(map[string]*wakeCall),
+		idleStops:      make(map[string]chan struct{}),
+		scaling:        make(map[string]*compiledScaling),
+		scaleOverrides: make(map[string]scaleOverride),
+		health:         make(map[string]*healthState),
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 }
 
-// SetVersionManager sets the version manager for binary downloads
+// SetVersionManager sets the version manager for binary downloads. It must be
+// called before Initialize.
 func (m *Manager) SetVersionManager(versionMgr VersionManager) {
 	m.versionManager = versionMgr
 }
 
-// SetUpdateManager sets the update manager for automatic downloads
+// SetUpdateManager sets the update manager for automatic downloads. It must be
+// called before Initialize.
 func (m *Manager) SetUpdateManager(updateMgr UpdateManager) {
 	m.updateManager = updateMgr
 }
 
+// SetAlertEvaluator sets the alert rules evaluated on every supervisor tick.
+// It may be called while the supervisor is running.
+func (m *Manager) SetAlertEvaluator(evaluator AlertEvaluator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alertEvaluator = evaluator
+}
+
 // Initialize initializes all configured processes
 func (m *Manager) Initialize() error {
 	// Clean up orphaned processes from previous session
@@ -94,6 +137,18 @@ func (m *Manager) Initialize() error {
 			Instances: make([]*models.ProcessInstance, 0),
 		}
 		m.mu.Unlock()
+		m.RecordActivity(procConfig.Name)
+
+		// Parse scaling schedules
+		if procConfig.Scaling != nil && len(procConfig.Scaling.Schedules) > 0 {
+			scaling, err := compileScaling(procConfig.Scaling)
+			if err != nil {
+				return fmt.Errorf("invalid scaling configuration for %s: %w", procConfig.Name, err)
+			}
+			m.scaleMu.Lock()
+			m.scaling[procConfig.Name] = scaling
+			m.scaleMu.Unlock()
+		}
 
 		// Generate certificates if they don't exist
 		if !m.certManager.CertificateExists(procConfig.Name) {
@@ -117,6 +172,9 @@ func (m *Manager) Initialize() error {
 		}
 	}
 
+	// Start background supervision (idle shutdown, scaling schedules)
+	go m.supervise()
+
 	return nil
 }
 
@@ -201,9 +259,29 @@ func (m *Manager) StartProcess(processName string) (*models.ProcessInstance, err
 	return m.StartProcessWithOptions(processName, false)
 }
 
+// StartOptions controls how a new instance is started
+type StartOptions struct {
+	// Start even if max_instances are already running (hot deploy)
+	AllowExceedMax bool
+
+	// Binary to run and its version; when empty the deployed, configured or
+	// latest detected binary is used
+	BinaryPath string
+	Version    string
+
+	// Start without traffic from load balancers and /proxy/ until
+	// SwitchTraffic makes the instance active (blue/green deploy)
+	Standby bool
+}
+
 // StartProcessWithOptions starts a new instance of a process with options
 // allowExceedMax: if true, allows starting a new instance even if max_instances is reached (for hot restart)
 func (m *Manager) StartProcessWithOptions(processName string, allowExceedMax bool) (*models.ProcessInstance, error) {
+	return m.StartInstance(processName, StartOptions{AllowExceedMax: allowExceedMax})
+}
+
+// StartInstance starts a new instance of a process
+func (m *Manager) StartInstance(processName string, opts StartOptions) (*models.ProcessInstance, error) {
 	m.mu.RLock()
 	managedProc, exists := m.processes[processName]
 	m.mu.RUnlock()
@@ -213,7 +291,7 @@ func (m *Manager) StartProcessWithOptions(processName string, allowExceedMax boo
 	}
 
 	// Check if we can start more instances (skip check during hot restart)
-	if !allowExceedMax {
+	if !opts.AllowExceedMax {
 		runningInstances := managedProc.GetRunningInstances()
 		if len(runningInstances) >= managedProc.Config.MaxInstances {
 			return nil, fmt.Errorf("maximum instances (%d) already running", managedProc.Config.MaxInstances)
@@ -245,9 +323,18 @@ func (m *Manager) StartProcessWithOptions(processName string, allowExceedMax boo
 		EnvFilePath: m.secretManager.GetEnvFilePath(processName),
 	}
 
-	// Determine binary path
-	var binaryPath string
-	if managedProc.Config.BinaryPath != "" {
+	// Determine binary path: explicitly requested, installed by the last
+	// deploy, configured, or the latest one in the binaries directory
+	binaryPath, binaryVersion := opts.BinaryPath, opts.Version
+	if binaryPath == "" {
+		binaryPath, binaryVersion = managedProc.GetDeployedBinary()
+	}
+	deployed := binaryPath != ""
+	if deployed {
+		if abs, err := filepath.Abs(binaryPath); err == nil {
+			binaryPath = abs
+		}
+	} else if managedProc.Config.BinaryPath != "" {
 		// Use configured binary path
 		binaryPath = managedProc.Config.BinaryPath
 		if !filepath.IsAbs(binaryPath) {
@@ -302,8 +389,15 @@ func (m *Manager) StartProcessWithOptions(processName string, allowExceedMax boo
 		}
 	}
 
-	// Extract and record version from binary filename if version manager is set
-	if m.versionManager != nil {
+	if binaryVersion == "" {
+		binaryVersion = extractVersionFromFilename(binaryPath)
+	}
+	instance.Version = binaryVersion
+	instance.BinaryPath = binaryPath
+
+	// Extract and record version from binary filename if version manager is
+	// set; deployed binaries are recorded by the update that installed them
+	if m.versionManager != nil && !deployed {
 		if version := extractVersionFromFilename(binaryPath); version != "" {
 			// Record the current version
 			versionInfo := &models.Version{
@@ -381,8 +475,17 @@ func (m *Manager) StartProcessWithOptions(processName string, allowExceedMax boo
 	}
 
 	// Add instance to managed process
+	if opts.Standby {
+		managedProc.SetStandby(nil, []string{instance.ID})
+	}
 	managedProc.AddInstance(instance)
 
+	// A started instance ends any scaled-to-zero period
+	m.idleMu.Lock()
+	m.scaledToZero[processName] = false
+	m.lastActivity[processName] = time.Now()
+	m.idleMu.Unlock()
+
 	// Monitor process
 	go m.monitorProcess(instance)
 
@@ -609,13 +712,13 @@ func (m *Manager) StopAllInstances(processName string) error {
 }
 
 // StopProcessesByBinaryName finds and stops all processes matching a binary name pattern,
-// excluding the specified PID (typically the new instance during Hot Deploy)
-func (m *Manager) StopProcessesByBinaryName(repository string, excludePID int, gracefulTimeout time.Duration) (int, error) {
+// excluding the instances tracked by the manager (such as the new instances during Hot Deploy)
+func (m *Manager) StopProcessesByBinaryName(repository string, gracefulTimeout time.Duration) (int, error) {
 	// Extract binary name from repository (e.g., "owner/repo" -> "repo")
 	binaryName := filepath.Base(repository)
 	binaryPattern := fmt.Sprintf("%s.exe", binaryName)
 
-	log.Printf("[Process Manager] Searching for processes matching binary pattern: %s (excluding PID %d)", binaryPattern, excludePID)
+	log.Printf("[Process Manager] Searching for processes matching binary pattern: %s", binaryPattern)
 
 	// Use PowerShell to find all processes matching the binary name
 	cmd := exec.Command("powershell", "-Command",
@@ -653,10 +756,11 @@ func (m *Manager) StopProcessesByBinaryName(repository string, excludePID int, g
 		processes = []ProcessInfo{single}
 	}
 
+	managed := m.trackedPIDs()
 	stoppedCount := 0
 	for _, proc := range processes {
-		if proc.ID == excludePID {
-			log.Printf("[Process Manager] Skipping PID %d (new instance)", proc.ID)
+		if managed[proc.ID] {
+			log.Printf("[Process Manager] Skipping PID %d (tracked instance)", proc.ID)
 			continue
 		}
 
@@ -734,6 +838,59 @@ func (m *Manager) ListProcesses() []string {
 	return names
 }
 
+// trackedPIDs returns the PIDs of all instances of all processes
+func (m *Manager) trackedPIDs() map[int]bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pids := make(map[int]bool)
+	for _, managedProc := range m.processes {
+		for _, inst := range managedProc.GetInstances() {
+			pids[inst.PID] = true
+		}
+	}
+	return pids
+}
+
+// GetRestartCount returns the number of automatic restarts of a process since startup
+func (m *Manager) GetRestartCount(processName string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if proc, exists := m.processes[processName]; exists {
+		return proc.GetRestartCount()
+	}
+	return 0
+}
+
+// SetDeployedBinary makes new instances of a process run the given binary,
+// e.g. after an update installed it. An empty path reverts to the configured
+// or detected binary.
+func (m *Manager) SetDeployedBinary(processName, binaryPath, version string) error {
+	m.mu.RLock()
+	managedProc, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("process %s not found", processName)
+	}
+	managedProc.SetDeployedBinary(binaryPath, version)
+	return nil
+}
+
+// GetDeployedBinary returns the binary installed by the last deploy of a
+// process ("" if none)
+func (m *Manager) GetDeployedBinary(processName string) (binaryPath, version string) {
+	m.mu.RLock()
+	managedProc, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return "", ""
+	}
+	return managedProc.GetDeployedBinary()
+}
+
 // GetProcessRepository returns the repository for a process
 func (m *Manager) GetProcessRepository(processName string) string {
 	m.mu.RLock()
@@ -779,6 +936,11 @@ func (m *Manager) monitorProcess(instance *models.ProcessInstance) {
 	// Wait for process to exit
 	err := instance.Command.Wait()
 
+	// Exits caused by StopProcess/StopProcessGracefully are not failures
+	if status := instance.GetStatus(); status == models.StatusStopping || status == models.StatusStopped {
+		err = nil
+	}
+
 	// If process exited, update status and log error details
 	if err != nil {
 		instance.SetStatus(models.StatusFailed)
@@ -812,15 +974,27 @@ func (m *Manager) monitorProcess(instance *models.ProcessInstance) {
 	managedProc := m.processes[instance.ProcessName]
 	m.mu.RUnlock()
 
+	// Canary and standby instances belong to the update that started them,
+	// which notices the crash; a restart would run the deployed binary and
+	// join the active instances
+	canary, _ := managedProc.GetCanary()
+	owned := canary[instance.ID] || managedProc.IsStandby(instance.ID)
+
 	// Remove instance from list
 	managedProc.RemoveInstance(instance.ID)
 
 	// Auto-restart if configured
 	if managedProc.Config.AutoRestart && instance.GetStatus() == models.StatusFailed {
+		if owned {
+			log.Printf("Not restarting %s instance %s: it belongs to an update in progress", instance.ProcessName, instance.ID)
+			return
+		}
+
 		// Wait a bit before restarting
 		time.Sleep(5 * time.Second)
 
 		// Try to restart
+		managedProc.IncrementRestarts()
 		if _, err := m.StartProcess(instance.ProcessName); err != nil {
 			log.Printf("Failed to auto-restart %s: %v", instance.ProcessName, err)
 		}
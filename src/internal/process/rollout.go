@@ -0,0 +1,197 @@
+package process
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// readyPollInterval is how often a new instance is checked while waiting for
+// it to become ready
+const readyPollInterval = 500 * time.Millisecond
+
+// Rollout describes instances to replace with new ones
+type Rollout struct {
+	Old      []*models.ProcessInstance // running instances to replace
+	Updated  int                       // instances already replaced (resuming a paused rollout)
+	Start    StartOptions              // how new instances are started
+	Progress func(RolloutProgress)     // called after every step (optional)
+}
+
+// RolloutProgress reports the state of a rollout
+type RolloutProgress struct {
+	Batch   int // current batch, starting at 1
+	Desired int // instances running when the rollout is done
+	Updated int // ready new instances
+	Old     int // old instances still running
+}
+
+// RollingReplace replaces instances in batches limited by the process's
+// update.max_surge and update.max_unavailable. Each batch starts new instances
+// and waits until they are ready before stopping old ones. If a new instance
+// fails to become ready, the new instances of that batch are stopped and the
+// rollout pauses: the error is returned and the remaining old instances keep
+// running. Returns the new instances that were started and kept.
+func (m *Manager) RollingReplace(processName string, r Rollout) ([]*models.ProcessInstance, error) {
+	procConfig, err := m.GetProcessConfig(processName)
+	if err != nil {
+		return nil, err
+	}
+	cfg := procConfig.Update
+	surge, unavailable := cfg.MaxSurge, cfg.MaxUnavailable
+	if surge <= 0 && unavailable <= 0 {
+		surge = 1
+	}
+
+	old := append([]*models.ProcessInstance(nil), r.Old...)
+	updated := r.Updated
+	desired := max(1, len(old)+updated)
+	start := r.Start
+	start.AllowExceedMax = true // surge instances exceed max_instances
+
+	var started []*models.ProcessInstance
+	progress := RolloutProgress{Desired: desired}
+	report := func() {
+		progress.Updated, progress.Old = updated, len(old)
+		if r.Progress != nil {
+			r.Progress(progress)
+		}
+	}
+
+	// stopOld stops as many old instances as the unavailability budget allows
+	// given the ready instances
+	stopOld := func() int {
+		n := min(len(old), len(old)+updated-(desired-unavailable))
+		if n <= 0 {
+			return 0
+		}
+		m.StopInstances(processName, old[:n], cfg.StopTimeout)
+		old = old[n:]
+		return n
+	}
+
+	for len(old) > 0 || updated < desired {
+		progress.Batch++
+		stopped := stopOld()
+
+		count := min(desired-updated, desired+surge-(len(old)+updated))
+		report()
+		if count <= 0 && stopped == 0 {
+			return started, fmt.Errorf("rollout of %s cannot progress (max_surge %d, max_unavailable %d)", processName, surge, unavailable)
+		}
+
+		if count > 0 {
+			log.Printf("[Rollout] %s batch %d: starting %d new instance(s), %d/%d updated, %d old", processName, progress.Batch, count, updated, desired, len(old))
+			batch, err := m.startBatch(processName, count, start, &procConfig)
+			if err != nil {
+				report()
+				return started, fmt.Errorf("batch %d: %w", progress.Batch, err)
+			}
+			started = append(started, batch...)
+			updated += count
+		}
+
+		stopOld()
+		report()
+	}
+
+	log.Printf("[Rollout] %s: %d instance(s) replaced in %d batch(es)", processName, len(r.Old), progress.Batch)
+	return started, nil
+}
+
+// startBatch starts count instances and waits until all are ready. If any
+// fails, the instances of the batch are stopped.
+func (m *Manager) startBatch(processName string, count int, opts StartOptions, procConfig *models.ProcessConfig) ([]*models.ProcessInstance, error) {
+	batch := make([]*models.ProcessInstance, 0, count)
+	fail := func(err error) ([]*models.ProcessInstance, error) {
+		m.StopInstances(processName, batch, procConfig.Update.StopTimeout)
+		return nil, err
+	}
+
+	for i := 0; i < count; i++ {
+		inst, err := m.StartInstance(processName, opts)
+		if err != nil {
+			return fail(fmt.Errorf("failed to start new instance: %w", err))
+		}
+		batch = append(batch, inst)
+	}
+
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	for i, inst := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = m.waitReady(inst, procConfig)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return fail(err)
+		}
+	}
+	return batch, nil
+}
+
+// waitReady waits until a new instance is ready: it passes its health check
+// (if enabled) within update.ready_timeout and keeps running for
+// update.min_ready_time
+func (m *Manager) waitReady(inst *models.ProcessInstance, procConfig *models.ProcessConfig) error {
+	running := func() error {
+		if status := inst.GetStatus(); status != models.StatusRunning {
+			return fmt.Errorf("new instance %s is %s", inst.ID, status)
+		}
+		return nil
+	}
+
+	if hc := procConfig.HealthCheck; hc.Enabled {
+		deadline := time.Now().Add(procConfig.Update.ReadyTimeout)
+		for {
+			if err := running(); err != nil {
+				return err
+			}
+			err := probe(inst.Port, hc)
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("new instance %s not ready after %v: %w", inst.ID, procConfig.Update.ReadyTimeout, err)
+			}
+			time.Sleep(readyPollInterval)
+		}
+	}
+
+	// Catch instances that crash shortly after starting
+	stable := time.Now().Add(procConfig.Update.MinReadyTime)
+	for {
+		if err := running(); err != nil {
+			return err
+		}
+		wait := time.Until(stable)
+		if wait <= 0 {
+			return nil
+		}
+		time.Sleep(min(wait, readyPollInterval))
+	}
+}
+
+// StopInstances stops instances gracefully in parallel; failures are only
+// logged since the instances may have exited already
+func (m *Manager) StopInstances(processName string, instances []*models.ProcessInstance, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, inst := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.StopProcessGracefully(processName, inst.ID, timeout); err != nil {
+				log.Printf("[Rollout] Warning: failed to stop instance %s of %s: %v", inst.ID, processName, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
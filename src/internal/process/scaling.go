@@ -0,0 +1,228 @@
+package process
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// compiledScaling holds the parsed scaling schedules of a process
+type compiledScaling struct {
+	location         *time.Location
+	defaultInstances int
+	schedules        []compiledSchedule
+}
+
+// compiledSchedule is a scaling schedule with parsed cron boundaries
+type compiledSchedule struct {
+	name      string
+	start     *cronSchedule
+	end       *cronSchedule
+	instances int
+}
+
+// scaleOverride is a manual instance count that holds until the next schedule boundary
+type scaleOverride struct {
+	instances int
+	until     time.Time
+}
+
+// compileScaling parses the scaling schedules of a process configuration
+func compileScaling(cfg *models.ScalingConfig) (*compiledScaling, error) {
+	loc := time.Local
+	if cfg.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", cfg.Timezone, err)
+		}
+	}
+
+	compiled := &compiledScaling{
+		location:         loc,
+		defaultInstances: cfg.DefaultInstances,
+	}
+
+	for i, sched := range cfg.Schedules {
+		start, err := parseCron(sched.Start)
+		if err != nil {
+			return nil, fmt.Errorf("schedule[%d]: start: %w", i, err)
+		}
+		end, err := parseCron(sched.End)
+		if err != nil {
+			return nil, fmt.Errorf("schedule[%d]: end: %w", i, err)
+		}
+		if sched.Instances < 0 {
+			return nil, fmt.Errorf("schedule[%d]: instances must not be negative", i)
+		}
+
+		name := sched.Name
+		if name == "" {
+			name = fmt.Sprintf("schedule_%d", i)
+		}
+		compiled.schedules = append(compiled.schedules, compiledSchedule{
+			name:      name,
+			start:     start,
+			end:       end,
+			instances: sched.Instances,
+		})
+	}
+
+	return compiled, nil
+}
+
+// desired returns the scheduled instance count at now, the name of the active
+// schedule ("" for the default) and the next time any schedule boundary fires
+func (c *compiledScaling) desired(now time.Time) (int, string, time.Time) {
+	now = now.In(c.location)
+
+	count := c.defaultInstances
+	active := ""
+	var activeStart, boundary time.Time
+
+	for _, sched := range c.schedules {
+		// A schedule is active if it started more recently than it ended;
+		// the most recently started active schedule wins
+		lastStart := sched.start.Prev(now)
+		lastEnd := sched.end.Prev(now)
+		if !lastStart.IsZero() && lastStart.After(lastEnd) && lastStart.After(activeStart) {
+			count = sched.instances
+			active = sched.name
+			activeStart = lastStart
+		}
+
+		for _, next := range []time.Time{sched.start.Next(now), sched.end.Next(now)} {
+			if !next.IsZero() && (boundary.IsZero() || next.Before(boundary)) {
+				boundary = next
+			}
+		}
+	}
+
+	return count, active, boundary
+}
+
+// ScaleProcess scales a process to a target number of instances. For processes
+// with scaling schedules the target is kept as a manual override until the next
+// schedule boundary.
+func (m *Manager) ScaleProcess(processName string, targetInstances int) error {
+	procConfig, err := m.GetProcessConfig(processName)
+	if err != nil {
+		return err
+	}
+	if targetInstances < 0 {
+		return fmt.Errorf("target instances must not be negative")
+	}
+	if targetInstances > procConfig.MaxInstances {
+		return fmt.Errorf("target instances %d exceeds max_instances (%d)", targetInstances, procConfig.MaxInstances)
+	}
+
+	m.scaleMu.Lock()
+	if scaling := m.scaling[processName]; scaling != nil {
+		_, _, boundary := scaling.desired(time.Now())
+		m.scaleOverrides[processName] = scaleOverride{instances: targetInstances, until: boundary}
+		log.Printf("[Scaling] Manual override for %s: %d instances until %v", processName, targetInstances, boundary)
+	}
+	m.scaleMu.Unlock()
+
+	return m.scaleTo(processName, targetInstances)
+}
+
+// scaleTo starts or gracefully stops instances until the process has targetInstances
+func (m *Manager) scaleTo(processName string, targetInstances int) error {
+	instances, err := m.GetProcessStatus(processName)
+	if err != nil {
+		return err
+	}
+
+	currentCount := len(instances)
+
+	if targetInstances > currentCount {
+		// Scale up - start more instances
+		for i := 0; i < targetInstances-currentCount; i++ {
+			if _, err := m.StartProcess(processName); err != nil {
+				return fmt.Errorf("failed to scale up: %w", err)
+			}
+		}
+	} else if targetInstances < currentCount {
+		// Scale down - stop instances gracefully
+		for i := 0; i < currentCount-targetInstances; i++ {
+			// Get fresh instance list each time
+			currentInstances, err := m.GetProcessStatus(processName)
+			if err != nil {
+				return fmt.Errorf("failed to get instances during scale down: %w", err)
+			}
+
+			if len(currentInstances) > targetInstances {
+				// Stop the last instance gracefully
+				last := currentInstances[len(currentInstances)-1]
+				if err := m.StopProcessGracefully(processName, last.ID, 10*time.Second); err != nil {
+					return fmt.Errorf("failed to scale down instance %s: %w", last.ID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// applySchedules brings every scheduled process to its desired instance count
+func (m *Manager) applySchedules() {
+	m.scaleMu.Lock()
+	names := make([]string, 0, len(m.scaling))
+	for name := range m.scaling {
+		names = append(names, name)
+	}
+	m.scaleMu.Unlock()
+
+	now := time.Now()
+	for _, name := range names {
+		procConfig, err := m.GetProcessConfig(name)
+		if err != nil {
+			continue
+		}
+
+		// Idle processes stay stopped until a request wakes them
+		if m.IsScaledToZero(name) {
+			continue
+		}
+
+		// Don't fight an update that temporarily runs extra instances
+		if m.updateManager != nil {
+			if status, exists := m.updateManager.GetUpdateStatus(name); exists && !status.Completed {
+				continue
+			}
+		}
+
+		m.scaleMu.Lock()
+		desired, active, _ := m.scaling[name].desired(now)
+		source := "default"
+		if active != "" {
+			source = "schedule " + active
+		}
+		if override, ok := m.scaleOverrides[name]; ok {
+			if override.until.IsZero() || now.Before(override.until) {
+				desired = override.instances
+				source = "manual override"
+			} else {
+				delete(m.scaleOverrides, name)
+				log.Printf("[Scaling] Manual override for %s expired at schedule boundary", name)
+			}
+		}
+		m.scaleMu.Unlock()
+
+		if desired > procConfig.MaxInstances {
+			desired = procConfig.MaxInstances
+		}
+
+		instances, err := m.GetProcessStatus(name)
+		if err != nil || len(instances) == desired {
+			continue
+		}
+
+		log.Printf("[Scaling] Scaling %s from %d to %d instances (%s)", name, len(instances), desired, source)
+		if err := m.scaleTo(name, desired); err != nil {
+			log.Printf("[Scaling] Failed to scale %s: %v", name, err)
+		}
+	}
+}
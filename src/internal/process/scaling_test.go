@@ -0,0 +1,61 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestScalingDesiredOverlap(t *testing.T) {
+	// office_hours runs 08:00-20:00 and lunch_peak 12:00-14:00 inside it
+	schedules := []models.ScalingSchedule{
+		{Name: "office_hours", Start: "0 8 * * *", End: "0 20 * * *", Instances: 2},
+		{Name: "lunch_peak", Start: "0 12 * * *", End: "0 14 * * *", Instances: 4},
+	}
+	reversed := []models.ScalingSchedule{schedules[1], schedules[0]}
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		instances int
+		active    string
+		boundary  time.Time
+	}{
+		{"before both", at(7, 0), 1, "", at(8, 0)},
+		{"outer only", at(10, 0), 2, "office_hours", at(12, 0)},
+		{"inner started last", at(13, 0), 4, "lunch_peak", at(14, 0)},
+		{"inner ended", at(15, 0), 2, "office_hours", at(20, 0)},
+		{"outer ended", at(21, 0), 1, "", time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, order := range []struct {
+		name      string
+		schedules []models.ScalingSchedule
+	}{{"listed", schedules}, {"reversed", reversed}} {
+		scaling, err := compileScaling(&models.ScalingConfig{
+			Timezone:         "UTC",
+			DefaultInstances: 1,
+			Schedules:        order.schedules,
+		})
+		if err != nil {
+			t.Fatalf("compileScaling: %v", err)
+		}
+
+		for _, tt := range tests {
+			t.Run(order.name+"/"+tt.name, func(t *testing.T) {
+				instances, active, boundary := scaling.desired(tt.now)
+				if instances != tt.instances || active != tt.active {
+					t.Errorf("desired(%v) = %d (%q), want %d (%q)", tt.now, instances, active, tt.instances, tt.active)
+				}
+				if !boundary.Equal(tt.boundary) {
+					t.Errorf("desired(%v) boundary = %v, want %v", tt.now, boundary, tt.boundary)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package process
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/metrics"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// supervisorInterval is how often the supervisor evaluates idle processes, schedules,
+// health checks and alert rules
+const supervisorInterval = 10 * time.Second
+
+var coldStartSeconds = metrics.NewHistogramVec(
+	"gowinproc_cold_start_seconds",
+	"Time from a wake request until a scaled-to-zero process accepted connections",
+	[]float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+	"process",
+)
+
+// wakeCall tracks an in-flight wake so concurrent requests share one cold start
+type wakeCall struct {
+	done     chan struct{}
+	instance *models.ProcessInstance
+	err      error
+}
+
+// GetProcessConfig returns the configuration of a process
+func (m *Manager) GetProcessConfig(processName string) (models.ProcessConfig, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	proc, exists := m.processes[processName]
+	if !exists {
+		return models.ProcessConfig{}, fmt.Errorf("process %s not found", processName)
+	}
+	return proc.Config, nil
+}
+
+// RecordActivity marks a process as recently used, resetting its idle timer
+func (m *Manager) RecordActivity(processName string) {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+	m.lastActivity[processName] = time.Now()
+}
+
+// BeginCall marks the start of a call proxied to a process. The process is not
+// stopped by its idle timeout until the returned function ends the call.
+func (m *Manager) BeginCall(processName string) (end func()) {
+	m.idleMu.Lock()
+	m.lastActivity[processName] = time.Now()
+	m.activeCalls[processName]++
+	m.idleMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.idleMu.Lock()
+			defer m.idleMu.Unlock()
+			m.lastActivity[processName] = time.Now()
+			if m.activeCalls[processName]--; m.activeCalls[processName] <= 0 {
+				delete(m.activeCalls, processName)
+			}
+		})
+	}
+}
+
+// IsScaledToZero reports whether a process was stopped by the idle timeout
+// and has not been woken since
+func (m *Manager) IsScaledToZero(processName string) bool {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+	return m.scaledToZero[processName]
+}
+
+// WakeProcess starts an instance of a scaled-to-zero process and waits until it
+// accepts connections. Concurrent callers share the same cold start.
+func (m *Manager) WakeProcess(processName string) (*models.ProcessInstance, error) {
+	procConfig, err := m.GetProcessConfig(processName)
+	if err != nil {
+		return nil, err
+	}
+
+	m.idleMu.Lock()
+	// Let an idle stop in progress finish first
+	for stopped := m.idleStops[processName]; stopped != nil; stopped = m.idleStops[processName] {
+		m.idleMu.Unlock()
+		<-stopped
+		m.idleMu.Lock()
+	}
+	if call, ok := m.wakes[processName]; ok {
+		m.idleMu.Unlock()
+		<-call.done
+		return call.instance, call.err
+	}
+	call := &wakeCall{done: make(chan struct{})}
+	m.wakes[processName] = call
+	m.lastActivity[processName] = time.Now()
+	m.idleMu.Unlock()
+
+	defer func() {
+		m.idleMu.Lock()
+		delete(m.wakes, processName)
+		if call.err == nil {
+			m.scaledToZero[processName] = false
+			m.lastActivity[processName] = time.Now()
+		}
+		m.idleMu.Unlock()
+		close(call.done)
+	}()
+
+	// Another caller may already have started an instance
+	instances, _ := m.GetProcessStatus(processName)
+	for _, inst := range instances {
+		if inst.GetStatus() == models.StatusRunning {
+			call.instance = inst
+			return inst, nil
+		}
+	}
+
+	maxWait := 30 * time.Second
+	if procConfig.ScaleToZero != nil && procConfig.ScaleToZero.MaxWakeWait > 0 {
+		maxWait = procConfig.ScaleToZero.MaxWakeWait
+	}
+
+	log.Printf("[ScaleToZero] Waking %s (max wait: %v)", processName, maxWait)
+	start := time.Now()
+
+	instance, err := m.StartProcess(processName)
+	if err != nil {
+		call.err = fmt.Errorf("failed to wake %s: %w", processName, err)
+		return nil, call.err
+	}
+
+	if err := m.WaitForReady(instance, maxWait); err != nil {
+		call.err = fmt.Errorf("failed to wake %s: %w", processName, err)
+		return nil, call.err
+	}
+
+	elapsed := time.Since(start)
+	coldStartSeconds.Observe(elapsed.Seconds(), processName)
+	log.Printf("[ScaleToZero] %s woke in %v (instance: %s, port: %d)", processName, elapsed, instance.ID, instance.Port)
+
+	call.instance = instance
+	return instance, nil
+}
+
+// WaitForReady waits until an instance accepts TCP connections on its port
+func (m *Manager) WaitForReady(instance *models.ProcessInstance, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	addr := fmt.Sprintf("localhost:%d", instance.Port)
+
+	for {
+		switch instance.GetStatus() {
+		case models.StatusFailed, models.StatusStopped:
+			return fmt.Errorf("instance %s exited before becoming ready", instance.ID)
+		}
+
+		conn, err := net.DialTimeout("tcp", addr, 500*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("instance %s not ready on %s after %v", instance.ID, addr, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// supervise runs periodic background checks until the manager is shut down
+func (m *Manager) supervise() {
+	ticker := time.NewTicker(supervisorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.tick(time.Now())
+		}
+	}
+}
+
+// tick runs one round of the supervisor's checks
+func (m *Manager) tick(now time.Time) {
+	m.stopIdleProcesses()
+	m.applySchedules()
+	m.checkHealth()
+
+	m.mu.RLock()
+	evaluator := m.alertEvaluator
+	m.mu.RUnlock()
+	if evaluator != nil {
+		evaluator.Evaluate(now)
+	}
+}
+
+// stopIdleProcesses stops all instances of processes whose idle timeout elapsed
+// and that have no calls in flight
+func (m *Manager) stopIdleProcesses() {
+	m.mu.RLock()
+	procs := make([]*models.ManagedProcess, 0, len(m.processes))
+	for _, proc := range m.processes {
+		procs = append(procs, proc)
+	}
+	m.mu.RUnlock()
+
+	for _, proc := range procs {
+		cfg := proc.Config.ScaleToZero
+		if cfg == nil || !cfg.Enabled || cfg.IdleTimeout <= 0 {
+			continue
+		}
+
+		// Marking the process under the same lock as BeginCall means a call
+		// either keeps it running or finds it scaled to zero and wakes it
+		name := proc.Config.Name
+		m.idleMu.Lock()
+		idle := time.Since(m.lastActivity[name])
+		_, waking := m.wakes[name]
+		stop := !waking && m.activeCalls[name] == 0 && idle >= cfg.IdleTimeout && len(proc.GetInstances()) > 0
+		stopped := make(chan struct{})
+		if stop {
+			m.scaledToZero[name] = true
+			m.idleStops[name] = stopped
+		}
+		m.idleMu.Unlock()
+
+		if !stop {
+			continue
+		}
+
+		log.Printf("[ScaleToZero] %s idle for %v (timeout: %v), stopping all instances", name, idle.Round(time.Second), cfg.IdleTimeout)
+
+		for _, inst := range proc.GetInstances() {
+			if err := m.StopProcessGracefully(name, inst.ID, 10*time.Second); err != nil {
+				log.Printf("[ScaleToZero] Warning: failed to stop %s instance %s: %v", name, inst.ID, err)
+			}
+		}
+
+		m.idleMu.Lock()
+		delete(m.idleStops, name)
+		m.idleMu.Unlock()
+		close(stopped)
+	}
+}
@@ -0,0 +1,55 @@
+package process_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/alerting"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestSupervisorTickFiresAlerts(t *testing.T) {
+	notified := make(chan alerting.Notification, 1)
+	notifier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n alerting.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("failed to decode notification: %v", err)
+		}
+		notified <- n
+	}))
+	defer notifier.Close()
+
+	procMgr := process.NewManager(&models.Config{}, nil, nil)
+	engine, err := alerting.NewEngine(&models.AlertingConfig{
+		Rules: []models.AlertRule{{
+			Name:     "always",
+			Expr:     "github_api_errors_total >= 0",
+			Severity: "warning",
+		}},
+		Notifiers: []models.NotifierConfig{{Name: "fake", Type: "webhook", URL: notifier.URL}},
+	}, procMgr)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	procMgr.SetAlertEvaluator(engine)
+
+	procMgr.Tick(time.Now())
+
+	alerts := engine.ActiveAlerts()
+	if len(alerts) != 1 || alerts[0].State != alerting.StateFiring {
+		t.Fatalf("active alerts = %+v, want one firing alert", alerts)
+	}
+
+	select {
+	case n := <-notified:
+		if n.Status != alerting.StateFiring || n.Alert.Name != "always" {
+			t.Errorf("notification = %s %s, want firing always", n.Status, n.Alert.Name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification was sent")
+	}
+}
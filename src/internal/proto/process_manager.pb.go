@@ -894,17 +894,28 @@ func (x *Metrics) GetAggregated() *AggregatedMetrics {
 }
 
 type ProcessMetrics struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	CpuUsage      float64                `protobuf:"fixed64,2,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`         // percentage
-	MemoryUsage   uint64                 `protobuf:"varint,3,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"` // bytes
-	DiskRead      uint64                 `protobuf:"varint,4,opt,name=disk_read,json=diskRead,proto3" json:"disk_read,omitempty"`          // bytes
-	DiskWrite     uint64                 `protobuf:"varint,5,opt,name=disk_write,json=diskWrite,proto3" json:"disk_write,omitempty"`       // bytes
-	NetworkRecv   uint64                 `protobuf:"varint,6,opt,name=network_recv,json=networkRecv,proto3" json:"network_recv,omitempty"` // bytes
-	NetworkSent   uint64                 `protobuf:"varint,7,opt,name=network_sent,json=networkSent,proto3" json:"network_sent,omitempty"` // bytes
-	Uptime        int64                  `protobuf:"varint,8,opt,name=uptime,proto3" json:"uptime,omitempty"`                              // seconds
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId  string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	CpuUsage    float64                `protobuf:"fixed64,2,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`         // percentage
+	MemoryUsage uint64                 `protobuf:"varint,3,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"` // bytes
+	DiskRead    uint64                 `protobuf:"varint,4,opt,name=disk_read,json=diskRead,proto3" json:"disk_read,omitempty"`          // bytes
+	DiskWrite   uint64                 `protobuf:"varint,5,opt,name=disk_write,json=diskWrite,proto3" json:"disk_write,omitempty"`       // bytes
+	NetworkRecv uint64                 `protobuf:"varint,6,opt,name=network_recv,json=networkRecv,proto3" json:"network_recv,omitempty"` // bytes
+	NetworkSent uint64                 `protobuf:"varint,7,opt,name=network_sent,json=networkSent,proto3" json:"network_sent,omitempty"` // bytes
+	Uptime      int64                  `protobuf:"varint,8,opt,name=uptime,proto3" json:"uptime,omitempty"`                              // seconds
+	// Per-second rates computed between background samples
+	DiskReadRate     float64          `protobuf:"fixed64,9,opt,name=disk_read_rate,json=diskReadRate,proto3" json:"disk_read_rate,omitempty"`           // bytes/s
+	DiskWriteRate    float64          `protobuf:"fixed64,10,opt,name=disk_write_rate,json=diskWriteRate,proto3" json:"disk_write_rate,omitempty"`       // bytes/s
+	NetworkRecvRate  float64          `protobuf:"fixed64,11,opt,name=network_recv_rate,json=networkRecvRate,proto3" json:"network_recv_rate,omitempty"` // bytes/s
+	NetworkSentRate  float64          `protobuf:"fixed64,12,opt,name=network_sent_rate,json=networkSentRate,proto3" json:"network_sent_rate,omitempty"` // bytes/s
+	DiskReadOps      uint64           `protobuf:"varint,13,opt,name=disk_read_ops,json=diskReadOps,proto3" json:"disk_read_ops,omitempty"`
+	DiskWriteOps     uint64           `protobuf:"varint,14,opt,name=disk_write_ops,json=diskWriteOps,proto3" json:"disk_write_ops,omitempty"`
+	OpenHandles      int32            `protobuf:"varint,15,opt,name=open_handles,json=openHandles,proto3" json:"open_handles,omitempty"` // handles on Windows, file descriptors elsewhere
+	Threads          int32            `protobuf:"varint,16,opt,name=threads,proto3" json:"threads,omitempty"`
+	TcpConnections   map[string]int32 `protobuf:"bytes,17,rep,name=tcp_connections,json=tcpConnections,proto3" json:"tcp_connections,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // connections on the instance port by state
+	NetworkSupported bool             `protobuf:"varint,18,opt,name=network_supported,json=networkSupported,proto3" json:"network_supported,omitempty"`                                                                     // false if per-process network counters are unavailable on this OS
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *ProcessMetrics) Reset() {
@@ -993,17 +1004,94 @@ func (x *ProcessMetrics) GetUptime() int64 {
 	return 0
 }
 
+func (x *ProcessMetrics) GetDiskReadRate() float64 {
+	if x != nil {
+		return x.DiskReadRate
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetDiskWriteRate() float64 {
+	if x != nil {
+		return x.DiskWriteRate
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetNetworkRecvRate() float64 {
+	if x != nil {
+		return x.NetworkRecvRate
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetNetworkSentRate() float64 {
+	if x != nil {
+		return x.NetworkSentRate
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetDiskReadOps() uint64 {
+	if x != nil {
+		return x.DiskReadOps
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetDiskWriteOps() uint64 {
+	if x != nil {
+		return x.DiskWriteOps
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetOpenHandles() int32 {
+	if x != nil {
+		return x.OpenHandles
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetThreads() int32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+func (x *ProcessMetrics) GetTcpConnections() map[string]int32 {
+	if x != nil {
+		return x.TcpConnections
+	}
+	return nil
+}
+
+func (x *ProcessMetrics) GetNetworkSupported() bool {
+	if x != nil {
+		return x.NetworkSupported
+	}
+	return false
+}
+
 type AggregatedMetrics struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	TotalCpuUsage    float64                `protobuf:"fixed64,1,opt,name=total_cpu_usage,json=totalCpuUsage,proto3" json:"total_cpu_usage,omitempty"`
-	TotalMemoryUsage uint64                 `protobuf:"varint,2,opt,name=total_memory_usage,json=totalMemoryUsage,proto3" json:"total_memory_usage,omitempty"`
-	TotalDiskRead    uint64                 `protobuf:"varint,3,opt,name=total_disk_read,json=totalDiskRead,proto3" json:"total_disk_read,omitempty"`
-	TotalDiskWrite   uint64                 `protobuf:"varint,4,opt,name=total_disk_write,json=totalDiskWrite,proto3" json:"total_disk_write,omitempty"`
-	TotalNetworkRecv uint64                 `protobuf:"varint,5,opt,name=total_network_recv,json=totalNetworkRecv,proto3" json:"total_network_recv,omitempty"`
-	TotalNetworkSent uint64                 `protobuf:"varint,6,opt,name=total_network_sent,json=totalNetworkSent,proto3" json:"total_network_sent,omitempty"`
-	InstanceCount    int32                  `protobuf:"varint,7,opt,name=instance_count,json=instanceCount,proto3" json:"instance_count,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TotalCpuUsage        float64                `protobuf:"fixed64,1,opt,name=total_cpu_usage,json=totalCpuUsage,proto3" json:"total_cpu_usage,omitempty"`
+	TotalMemoryUsage     uint64                 `protobuf:"varint,2,opt,name=total_memory_usage,json=totalMemoryUsage,proto3" json:"total_memory_usage,omitempty"`
+	TotalDiskRead        uint64                 `protobuf:"varint,3,opt,name=total_disk_read,json=totalDiskRead,proto3" json:"total_disk_read,omitempty"`
+	TotalDiskWrite       uint64                 `protobuf:"varint,4,opt,name=total_disk_write,json=totalDiskWrite,proto3" json:"total_disk_write,omitempty"`
+	TotalNetworkRecv     uint64                 `protobuf:"varint,5,opt,name=total_network_recv,json=totalNetworkRecv,proto3" json:"total_network_recv,omitempty"`
+	TotalNetworkSent     uint64                 `protobuf:"varint,6,opt,name=total_network_sent,json=totalNetworkSent,proto3" json:"total_network_sent,omitempty"`
+	InstanceCount        int32                  `protobuf:"varint,7,opt,name=instance_count,json=instanceCount,proto3" json:"instance_count,omitempty"`
+	TotalDiskReadRate    float64                `protobuf:"fixed64,8,opt,name=total_disk_read_rate,json=totalDiskReadRate,proto3" json:"total_disk_read_rate,omitempty"`           // bytes/s
+	TotalDiskWriteRate   float64                `protobuf:"fixed64,9,opt,name=total_disk_write_rate,json=totalDiskWriteRate,proto3" json:"total_disk_write_rate,omitempty"`        // bytes/s
+	TotalNetworkRecvRate float64                `protobuf:"fixed64,10,opt,name=total_network_recv_rate,json=totalNetworkRecvRate,proto3" json:"total_network_recv_rate,omitempty"` // bytes/s
+	TotalNetworkSentRate float64                `protobuf:"fixed64,11,opt,name=total_network_sent_rate,json=totalNetworkSentRate,proto3" json:"total_network_sent_rate,omitempty"` // bytes/s
+	TotalOpenHandles     int32                  `protobuf:"varint,12,opt,name=total_open_handles,json=totalOpenHandles,proto3" json:"total_open_handles,omitempty"`
+	TotalThreads         int32                  `protobuf:"varint,13,opt,name=total_threads,json=totalThreads,proto3" json:"total_threads,omitempty"`
+	TotalTcpConnections  int32                  `protobuf:"varint,14,opt,name=total_tcp_connections,json=totalTcpConnections,proto3" json:"total_tcp_connections,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *AggregatedMetrics) Reset() {
@@ -1085,28 +1173,77 @@ func (x *AggregatedMetrics) GetInstanceCount() int32 {
 	return 0
 }
 
-type ScaleProcessRequest struct {
+func (x *AggregatedMetrics) GetTotalDiskReadRate() float64 {
+	if x != nil {
+		return x.TotalDiskReadRate
+	}
+	return 0
+}
+
+func (x *AggregatedMetrics) GetTotalDiskWriteRate() float64 {
+	if x != nil {
+		return x.TotalDiskWriteRate
+	}
+	return 0
+}
+
+func (x *AggregatedMetrics) GetTotalNetworkRecvRate() float64 {
+	if x != nil {
+		return x.TotalNetworkRecvRate
+	}
+	return 0
+}
+
+func (x *AggregatedMetrics) GetTotalNetworkSentRate() float64 {
+	if x != nil {
+		return x.TotalNetworkSentRate
+	}
+	return 0
+}
+
+func (x *AggregatedMetrics) GetTotalOpenHandles() int32 {
+	if x != nil {
+		return x.TotalOpenHandles
+	}
+	return 0
+}
+
+func (x *AggregatedMetrics) GetTotalThreads() int32 {
+	if x != nil {
+		return x.TotalThreads
+	}
+	return 0
+}
+
+func (x *AggregatedMetrics) GetTotalTcpConnections() int32 {
+	if x != nil {
+		return x.TotalTcpConnections
+	}
+	return 0
+}
+
+type StreamMetricsRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
-	ProcessName     string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	TargetInstances int32                  `protobuf:"varint,2,opt,name=target_instances,json=targetInstances,proto3" json:"target_instances,omitempty"`
+	ProcessName     string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`              // Optional - if empty, streams all processes
+	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // Optional - rounded up to the sampler interval
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ScaleProcessRequest) Reset() {
-	*x = ScaleProcessRequest{}
+func (x *StreamMetricsRequest) Reset() {
+	*x = StreamMetricsRequest{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScaleProcessRequest) String() string {
+func (x *StreamMetricsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScaleProcessRequest) ProtoMessage() {}
+func (*StreamMetricsRequest) ProtoMessage() {}
 
-func (x *ScaleProcessRequest) ProtoReflect() protoreflect.Message {
+func (x *StreamMetricsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1118,49 +1255,47 @@ func (x *ScaleProcessRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScaleProcessRequest.ProtoReflect.Descriptor instead.
-func (*ScaleProcessRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use StreamMetricsRequest.ProtoReflect.Descriptor instead.
+func (*StreamMetricsRequest) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ScaleProcessRequest) GetProcessName() string {
+func (x *StreamMetricsRequest) GetProcessName() string {
 	if x != nil {
 		return x.ProcessName
 	}
 	return ""
 }
 
-func (x *ScaleProcessRequest) GetTargetInstances() int32 {
+func (x *StreamMetricsRequest) GetIntervalSeconds() int32 {
 	if x != nil {
-		return x.TargetInstances
+		return x.IntervalSeconds
 	}
 	return 0
 }
 
-type UpdateAllRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Strategy         string                 `protobuf:"bytes,1,opt,name=strategy,proto3" json:"strategy,omitempty"` // rolling, blue-green, immediate
-	Force            bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
-	Timeout          int32                  `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`                                             // seconds
-	HealthCheckDelay int32                  `protobuf:"varint,4,opt,name=health_check_delay,json=healthCheckDelay,proto3" json:"health_check_delay,omitempty"` // seconds
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type MetricsSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix seconds of the sample
+	Processes     []*Metrics             `protobuf:"bytes,2,rep,name=processes,proto3" json:"processes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateAllRequest) Reset() {
-	*x = UpdateAllRequest{}
+func (x *MetricsSnapshot) Reset() {
+	*x = MetricsSnapshot{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateAllRequest) String() string {
+func (x *MetricsSnapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateAllRequest) ProtoMessage() {}
+func (*MetricsSnapshot) ProtoMessage() {}
 
-func (x *UpdateAllRequest) ProtoReflect() protoreflect.Message {
+func (x *MetricsSnapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1172,64 +1307,51 @@ func (x *UpdateAllRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateAllRequest.ProtoReflect.Descriptor instead.
-func (*UpdateAllRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use MetricsSnapshot.ProtoReflect.Descriptor instead.
+func (*MetricsSnapshot) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *UpdateAllRequest) GetStrategy() string {
-	if x != nil {
-		return x.Strategy
-	}
-	return ""
-}
-
-func (x *UpdateAllRequest) GetForce() bool {
-	if x != nil {
-		return x.Force
-	}
-	return false
-}
-
-func (x *UpdateAllRequest) GetTimeout() int32 {
+func (x *MetricsSnapshot) GetTimestamp() int64 {
 	if x != nil {
-		return x.Timeout
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *UpdateAllRequest) GetHealthCheckDelay() int32 {
+func (x *MetricsSnapshot) GetProcesses() []*Metrics {
 	if x != nil {
-		return x.HealthCheckDelay
+		return x.Processes
 	}
-	return 0
+	return nil
 }
 
-type UpdateProcessRequest struct {
+type QueryMetricsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // empty string = latest version
-	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
-	Strategy      string                 `protobuf:"bytes,4,opt,name=strategy,proto3" json:"strategy,omitempty"`
-	Instances     []int32                `protobuf:"varint,5,rep,packed,name=instances,proto3" json:"instances,omitempty"` // specific instance indices (optional)
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // Optional - "total" for the process aggregate, empty for all
+	Metrics       []string               `protobuf:"bytes,3,rep,name=metrics,proto3" json:"metrics,omitempty"`                         // Optional - empty for all metrics
+	StartTime     int64                  `protobuf:"varint,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`   // Unix seconds, default: 1 hour before end_time
+	EndTime       int64                  `protobuf:"varint,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`         // Unix seconds, default: now
+	Step          int64                  `protobuf:"varint,6,opt,name=step,proto3" json:"step,omitempty"`                              // Seconds, 0 = native resolution
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateProcessRequest) Reset() {
-	*x = UpdateProcessRequest{}
+func (x *QueryMetricsRequest) Reset() {
+	*x = QueryMetricsRequest{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateProcessRequest) String() string {
+func (x *QueryMetricsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateProcessRequest) ProtoMessage() {}
+func (*QueryMetricsRequest) ProtoMessage() {}
 
-func (x *UpdateProcessRequest) ProtoReflect() protoreflect.Message {
+func (x *QueryMetricsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1241,70 +1363,74 @@ func (x *UpdateProcessRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateProcessRequest.ProtoReflect.Descriptor instead.
-func (*UpdateProcessRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryMetricsRequest.ProtoReflect.Descriptor instead.
+func (*QueryMetricsRequest) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *UpdateProcessRequest) GetProcessName() string {
+func (x *QueryMetricsRequest) GetProcessName() string {
 	if x != nil {
 		return x.ProcessName
 	}
 	return ""
 }
 
-func (x *UpdateProcessRequest) GetVersion() string {
+func (x *QueryMetricsRequest) GetInstanceId() string {
 	if x != nil {
-		return x.Version
+		return x.InstanceId
 	}
 	return ""
 }
 
-func (x *UpdateProcessRequest) GetForce() bool {
+func (x *QueryMetricsRequest) GetMetrics() []string {
 	if x != nil {
-		return x.Force
+		return x.Metrics
 	}
-	return false
+	return nil
 }
 
-func (x *UpdateProcessRequest) GetStrategy() string {
+func (x *QueryMetricsRequest) GetStartTime() int64 {
 	if x != nil {
-		return x.Strategy
+		return x.StartTime
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateProcessRequest) GetInstances() []int32 {
+func (x *QueryMetricsRequest) GetEndTime() int64 {
 	if x != nil {
-		return x.Instances
+		return x.EndTime
 	}
-	return nil
+	return 0
 }
 
-type UpdateResponse struct {
+func (x *QueryMetricsRequest) GetStep() int64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+type QueryMetricsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	UpdateId      string                 `protobuf:"bytes,3,opt,name=update_id,json=updateId,proto3" json:"update_id,omitempty"`
-	Processes     []*ProcessUpdateStatus `protobuf:"bytes,4,rep,name=processes,proto3" json:"processes,omitempty"`
+	Series        []*MetricSeries        `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateResponse) Reset() {
-	*x = UpdateResponse{}
+func (x *QueryMetricsResponse) Reset() {
+	*x = QueryMetricsResponse{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateResponse) String() string {
+func (x *QueryMetricsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateResponse) ProtoMessage() {}
+func (*QueryMetricsResponse) ProtoMessage() {}
 
-func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+func (x *QueryMetricsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1316,64 +1442,42 @@ func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
-func (*UpdateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryMetricsResponse.ProtoReflect.Descriptor instead.
+func (*QueryMetricsResponse) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *UpdateResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
-}
-
-func (x *UpdateResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
-}
-
-func (x *UpdateResponse) GetUpdateId() string {
-	if x != nil {
-		return x.UpdateId
-	}
-	return ""
-}
-
-func (x *UpdateResponse) GetProcesses() []*ProcessUpdateStatus {
+func (x *QueryMetricsResponse) GetSeries() []*MetricSeries {
 	if x != nil {
-		return x.Processes
+		return x.Series
 	}
 	return nil
 }
 
-type ProcessUpdateStatus struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	CurrentVersion    string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
-	TargetVersion     string                 `protobuf:"bytes,3,opt,name=target_version,json=targetVersion,proto3" json:"target_version,omitempty"`
-	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                                 // updating, completed, failed
-	EstimatedDuration int32                  `protobuf:"varint,5,opt,name=estimated_duration,json=estimatedDuration,proto3" json:"estimated_duration,omitempty"` // seconds
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+type MetricSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Metric        string                 `protobuf:"bytes,3,opt,name=metric,proto3" json:"metric,omitempty"`
+	Points        []*MetricPoint         `protobuf:"bytes,4,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProcessUpdateStatus) Reset() {
-	*x = ProcessUpdateStatus{}
+func (x *MetricSeries) Reset() {
+	*x = MetricSeries{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProcessUpdateStatus) String() string {
+func (x *MetricSeries) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProcessUpdateStatus) ProtoMessage() {}
+func (*MetricSeries) ProtoMessage() {}
 
-func (x *ProcessUpdateStatus) ProtoReflect() protoreflect.Message {
+func (x *MetricSeries) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1385,67 +1489,61 @@ func (x *ProcessUpdateStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProcessUpdateStatus.ProtoReflect.Descriptor instead.
-func (*ProcessUpdateStatus) Descriptor() ([]byte, []int) {
+// Deprecated: Use MetricSeries.ProtoReflect.Descriptor instead.
+func (*MetricSeries) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *ProcessUpdateStatus) GetName() string {
+func (x *MetricSeries) GetProcessName() string {
 	if x != nil {
-		return x.Name
+		return x.ProcessName
 	}
 	return ""
 }
 
-func (x *ProcessUpdateStatus) GetCurrentVersion() string {
+func (x *MetricSeries) GetInstanceId() string {
 	if x != nil {
-		return x.CurrentVersion
+		return x.InstanceId
 	}
 	return ""
 }
 
-func (x *ProcessUpdateStatus) GetTargetVersion() string {
+func (x *MetricSeries) GetMetric() string {
 	if x != nil {
-		return x.TargetVersion
+		return x.Metric
 	}
 	return ""
 }
 
-func (x *ProcessUpdateStatus) GetStatus() string {
+func (x *MetricSeries) GetPoints() []*MetricPoint {
 	if x != nil {
-		return x.Status
+		return x.Points
 	}
-	return ""
+	return nil
 }
 
-func (x *ProcessUpdateStatus) GetEstimatedDuration() int32 {
-	if x != nil {
-		return x.EstimatedDuration
-	}
-	return 0
+type MetricPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix seconds
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type GetVersionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetVersionRequest) Reset() {
-	*x = GetVersionRequest{}
+func (x *MetricPoint) Reset() {
+	*x = MetricPoint{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetVersionRequest) String() string {
+func (x *MetricPoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetVersionRequest) ProtoMessage() {}
+func (*MetricPoint) ProtoMessage() {}
 
-func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+func (x *MetricPoint) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1457,43 +1555,47 @@ func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
-func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use MetricPoint.ProtoReflect.Descriptor instead.
+func (*MetricPoint) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *GetVersionRequest) GetProcessName() string {
+func (x *MetricPoint) GetTimestamp() int64 {
 	if x != nil {
-		return x.ProcessName
+		return x.Timestamp
 	}
-	return ""
+	return 0
 }
 
-type VersionInfo struct {
+func (x *MetricPoint) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type ScaleProcessRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	ProcessName     string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	CurrentVersion  string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
-	LatestVersion   string                 `protobuf:"bytes,3,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
-	UpdateAvailable bool                   `protobuf:"varint,4,opt,name=update_available,json=updateAvailable,proto3" json:"update_available,omitempty"`
-	Instances       []*InstanceVersion     `protobuf:"bytes,5,rep,name=instances,proto3" json:"instances,omitempty"`
+	TargetInstances int32                  `protobuf:"varint,2,opt,name=target_instances,json=targetInstances,proto3" json:"target_instances,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *VersionInfo) Reset() {
-	*x = VersionInfo{}
+func (x *ScaleProcessRequest) Reset() {
+	*x = ScaleProcessRequest{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *VersionInfo) String() string {
+func (x *ScaleProcessRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VersionInfo) ProtoMessage() {}
+func (*ScaleProcessRequest) ProtoMessage() {}
 
-func (x *VersionInfo) ProtoReflect() protoreflect.Message {
+func (x *ScaleProcessRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1505,69 +1607,49 @@ func (x *VersionInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VersionInfo.ProtoReflect.Descriptor instead.
-func (*VersionInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use ScaleProcessRequest.ProtoReflect.Descriptor instead.
+func (*ScaleProcessRequest) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *VersionInfo) GetProcessName() string {
+func (x *ScaleProcessRequest) GetProcessName() string {
 	if x != nil {
 		return x.ProcessName
 	}
 	return ""
 }
 
-func (x *VersionInfo) GetCurrentVersion() string {
-	if x != nil {
-		return x.CurrentVersion
-	}
-	return ""
-}
-
-func (x *VersionInfo) GetLatestVersion() string {
-	if x != nil {
-		return x.LatestVersion
-	}
-	return ""
-}
-
-func (x *VersionInfo) GetUpdateAvailable() bool {
-	if x != nil {
-		return x.UpdateAvailable
-	}
-	return false
-}
-
-func (x *VersionInfo) GetInstances() []*InstanceVersion {
+func (x *ScaleProcessRequest) GetTargetInstances() int32 {
 	if x != nil {
-		return x.Instances
+		return x.TargetInstances
 	}
-	return nil
+	return 0
 }
 
-type InstanceVersion struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Uptime        int64                  `protobuf:"varint,3,opt,name=uptime,proto3" json:"uptime,omitempty"` // seconds
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type UpdateAllRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Strategy         string                 `protobuf:"bytes,1,opt,name=strategy,proto3" json:"strategy,omitempty"` // rolling, blue-green, immediate
+	Force            bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	Timeout          int32                  `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`                                             // seconds
+	HealthCheckDelay int32                  `protobuf:"varint,4,opt,name=health_check_delay,json=healthCheckDelay,proto3" json:"health_check_delay,omitempty"` // seconds
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *InstanceVersion) Reset() {
-	*x = InstanceVersion{}
+func (x *UpdateAllRequest) Reset() {
+	*x = UpdateAllRequest{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InstanceVersion) String() string {
+func (x *UpdateAllRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InstanceVersion) ProtoMessage() {}
+func (*UpdateAllRequest) ProtoMessage() {}
 
-func (x *InstanceVersion) ProtoReflect() protoreflect.Message {
+func (x *UpdateAllRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1579,52 +1661,64 @@ func (x *InstanceVersion) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InstanceVersion.ProtoReflect.Descriptor instead.
-func (*InstanceVersion) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateAllRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAllRequest) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *InstanceVersion) GetId() string {
+func (x *UpdateAllRequest) GetStrategy() string {
 	if x != nil {
-		return x.Id
+		return x.Strategy
 	}
 	return ""
 }
 
-func (x *InstanceVersion) GetVersion() string {
+func (x *UpdateAllRequest) GetForce() bool {
 	if x != nil {
-		return x.Version
+		return x.Force
 	}
-	return ""
+	return false
 }
 
-func (x *InstanceVersion) GetUptime() int64 {
+func (x *UpdateAllRequest) GetTimeout() int32 {
 	if x != nil {
-		return x.Uptime
+		return x.Timeout
 	}
 	return 0
 }
 
-type ListUpdatesRequest struct {
+func (x *UpdateAllRequest) GetHealthCheckDelay() int32 {
+	if x != nil {
+		return x.HealthCheckDelay
+	}
+	return 0
+}
+
+type UpdateProcessRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // empty string = latest version
+	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
+	Strategy      string                 `protobuf:"bytes,4,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Instances     []int32                `protobuf:"varint,5,rep,packed,name=instances,proto3" json:"instances,omitempty"` // specific instance indices (optional)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUpdatesRequest) Reset() {
-	*x = ListUpdatesRequest{}
+func (x *UpdateProcessRequest) Reset() {
+	*x = UpdateProcessRequest{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUpdatesRequest) String() string {
+func (x *UpdateProcessRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUpdatesRequest) ProtoMessage() {}
+func (*UpdateProcessRequest) ProtoMessage() {}
 
-func (x *ListUpdatesRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateProcessRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1636,32 +1730,70 @@ func (x *ListUpdatesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUpdatesRequest.ProtoReflect.Descriptor instead.
-func (*ListUpdatesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateProcessRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProcessRequest) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{24}
 }
 
-type ListUpdatesResponse struct {
+func (x *UpdateProcessRequest) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *UpdateProcessRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *UpdateProcessRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *UpdateProcessRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *UpdateProcessRequest) GetInstances() []int32 {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+type UpdateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Updates       []*UpdateAvailable     `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	UpdateId      string                 `protobuf:"bytes,3,opt,name=update_id,json=updateId,proto3" json:"update_id,omitempty"`
+	Processes     []*ProcessUpdateStatus `protobuf:"bytes,4,rep,name=processes,proto3" json:"processes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUpdatesResponse) Reset() {
-	*x = ListUpdatesResponse{}
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUpdatesResponse) String() string {
+func (x *UpdateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUpdatesResponse) ProtoMessage() {}
+func (*UpdateResponse) ProtoMessage() {}
 
-func (x *ListUpdatesResponse) ProtoReflect() protoreflect.Message {
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1673,44 +1805,64 @@ func (x *ListUpdatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUpdatesResponse.ProtoReflect.Descriptor instead.
-func (*ListUpdatesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *ListUpdatesResponse) GetUpdates() []*UpdateAvailable {
+func (x *UpdateResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Updates
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateResponse) GetUpdateId() string {
+	if x != nil {
+		return x.UpdateId
+	}
+	return ""
+}
+
+func (x *UpdateResponse) GetProcesses() []*ProcessUpdateStatus {
+	if x != nil {
+		return x.Processes
 	}
 	return nil
 }
 
-type UpdateAvailable struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ProcessName    string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	CurrentVersion string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
-	LatestVersion  string                 `protobuf:"bytes,3,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
-	ReleaseDate    string                 `protobuf:"bytes,4,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
-	ReleaseNotes   string                 `protobuf:"bytes,5,opt,name=release_notes,json=releaseNotes,proto3" json:"release_notes,omitempty"`
-	UpToDate       bool                   `protobuf:"varint,6,opt,name=up_to_date,json=upToDate,proto3" json:"up_to_date,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type ProcessUpdateStatus struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CurrentVersion    string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	TargetVersion     string                 `protobuf:"bytes,3,opt,name=target_version,json=targetVersion,proto3" json:"target_version,omitempty"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                                 // updating, completed, failed
+	EstimatedDuration int32                  `protobuf:"varint,5,opt,name=estimated_duration,json=estimatedDuration,proto3" json:"estimated_duration,omitempty"` // seconds
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *UpdateAvailable) Reset() {
-	*x = UpdateAvailable{}
+func (x *ProcessUpdateStatus) Reset() {
+	*x = ProcessUpdateStatus{}
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateAvailable) String() string {
+func (x *ProcessUpdateStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateAvailable) ProtoMessage() {}
+func (*ProcessUpdateStatus) ProtoMessage() {}
 
-func (x *UpdateAvailable) ProtoReflect() protoreflect.Message {
+func (x *ProcessUpdateStatus) ProtoReflect() protoreflect.Message {
 	mi := &file_src_internal_proto_process_manager_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1722,76 +1874,1020 @@ func (x *UpdateAvailable) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateAvailable.ProtoReflect.Descriptor instead.
-func (*UpdateAvailable) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProcessUpdateStatus.ProtoReflect.Descriptor instead.
+func (*ProcessUpdateStatus) Descriptor() ([]byte, []int) {
 	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *UpdateAvailable) GetProcessName() string {
+func (x *ProcessUpdateStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProcessUpdateStatus) GetCurrentVersion() string {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return ""
+}
+
+func (x *ProcessUpdateStatus) GetTargetVersion() string {
+	if x != nil {
+		return x.TargetVersion
+	}
+	return ""
+}
+
+func (x *ProcessUpdateStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProcessUpdateStatus) GetEstimatedDuration() int32 {
+	if x != nil {
+		return x.EstimatedDuration
+	}
+	return 0
+}
+
+type GetVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionRequest) ProtoMessage() {}
+
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetVersionRequest) GetProcessName() string {
 	if x != nil {
 		return x.ProcessName
 	}
 	return ""
 }
 
-func (x *UpdateAvailable) GetCurrentVersion() string {
+type VersionInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName     string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	CurrentVersion  string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	LatestVersion   string                 `protobuf:"bytes,3,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	UpdateAvailable bool                   `protobuf:"varint,4,opt,name=update_available,json=updateAvailable,proto3" json:"update_available,omitempty"`
+	Instances       []*InstanceVersion     `protobuf:"bytes,5,rep,name=instances,proto3" json:"instances,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VersionInfo) Reset() {
+	*x = VersionInfo{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionInfo) ProtoMessage() {}
+
+func (x *VersionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionInfo.ProtoReflect.Descriptor instead.
+func (*VersionInfo) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *VersionInfo) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetCurrentVersion() string {
 	if x != nil {
 		return x.CurrentVersion
 	}
 	return ""
 }
 
-func (x *UpdateAvailable) GetLatestVersion() string {
+func (x *VersionInfo) GetLatestVersion() string {
 	if x != nil {
 		return x.LatestVersion
 	}
 	return ""
 }
 
-func (x *UpdateAvailable) GetReleaseDate() string {
+func (x *VersionInfo) GetUpdateAvailable() bool {
+	if x != nil {
+		return x.UpdateAvailable
+	}
+	return false
+}
+
+func (x *VersionInfo) GetInstances() []*InstanceVersion {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+type InstanceVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Uptime        int64                  `protobuf:"varint,3,opt,name=uptime,proto3" json:"uptime,omitempty"` // seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceVersion) Reset() {
+	*x = InstanceVersion{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceVersion) ProtoMessage() {}
+
+func (x *InstanceVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceVersion.ProtoReflect.Descriptor instead.
+func (*InstanceVersion) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *InstanceVersion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InstanceVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *InstanceVersion) GetUptime() int64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type ListUpdatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUpdatesRequest) Reset() {
+	*x = ListUpdatesRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUpdatesRequest) ProtoMessage() {}
+
+func (x *ListUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*ListUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{30}
+}
+
+type ListUpdatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updates       []*UpdateAvailable     `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUpdatesResponse) Reset() {
+	*x = ListUpdatesResponse{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUpdatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUpdatesResponse) ProtoMessage() {}
+
+func (x *ListUpdatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUpdatesResponse.ProtoReflect.Descriptor instead.
+func (*ListUpdatesResponse) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListUpdatesResponse) GetUpdates() []*UpdateAvailable {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+type UpdateAvailable struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName    string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	CurrentVersion string                 `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	LatestVersion  string                 `protobuf:"bytes,3,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	ReleaseDate    string                 `protobuf:"bytes,4,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	ReleaseNotes   string                 `protobuf:"bytes,5,opt,name=release_notes,json=releaseNotes,proto3" json:"release_notes,omitempty"`
+	UpToDate       bool                   `protobuf:"varint,6,opt,name=up_to_date,json=upToDate,proto3" json:"up_to_date,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateAvailable) Reset() {
+	*x = UpdateAvailable{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAvailable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAvailable) ProtoMessage() {}
+
+func (x *UpdateAvailable) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAvailable.ProtoReflect.Descriptor instead.
+func (*UpdateAvailable) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateAvailable) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *UpdateAvailable) GetCurrentVersion() string {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return ""
+}
+
+func (x *UpdateAvailable) GetLatestVersion() string {
+	if x != nil {
+		return x.LatestVersion
+	}
+	return ""
+}
+
+func (x *UpdateAvailable) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *UpdateAvailable) GetReleaseNotes() string {
+	if x != nil {
+		return x.ReleaseNotes
+	}
+	return ""
+}
+
+func (x *UpdateAvailable) GetUpToDate() bool {
+	if x != nil {
+		return x.UpToDate
+	}
+	return false
+}
+
+type RollbackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // empty string = previous version
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackRequest) Reset() {
+	*x = RollbackRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackRequest) ProtoMessage() {}
+
+func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackRequest.ProtoReflect.Descriptor instead.
+func (*RollbackRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RollbackRequest) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *RollbackRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type RollbackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ProcessName   string                 `protobuf:"bytes,2,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	FromVersion   string                 `protobuf:"bytes,3,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion     string                 `protobuf:"bytes,4,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+	RollbackId    string                 `protobuf:"bytes,5,opt,name=rollback_id,json=rollbackId,proto3" json:"rollback_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackResponse) Reset() {
+	*x = RollbackResponse{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackResponse) ProtoMessage() {}
+
+func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackResponse.ProtoReflect.Descriptor instead.
+func (*RollbackResponse) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RollbackResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RollbackResponse) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *RollbackResponse) GetFromVersion() string {
+	if x != nil {
+		return x.FromVersion
+	}
+	return ""
+}
+
+func (x *RollbackResponse) GetToVersion() string {
+	if x != nil {
+		return x.ToVersion
+	}
+	return ""
+}
+
+func (x *RollbackResponse) GetRollbackId() string {
+	if x != nil {
+		return x.RollbackId
+	}
+	return ""
+}
+
+type WatchUpdateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdateId      string                 `protobuf:"bytes,1,opt,name=update_id,json=updateId,proto3" json:"update_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUpdateRequest) Reset() {
+	*x = WatchUpdateRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUpdateRequest) ProtoMessage() {}
+
+func (x *WatchUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUpdateRequest.ProtoReflect.Descriptor instead.
+func (*WatchUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WatchUpdateRequest) GetUpdateId() string {
+	if x != nil {
+		return x.UpdateId
+	}
+	return ""
+}
+
+type UpdateStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdateId      string                 `protobuf:"bytes,1,opt,name=update_id,json=updateId,proto3" json:"update_id,omitempty"`
+	ProcessName   string                 `protobuf:"bytes,2,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`      // downloading, extracting, stopping, starting, completed, failed
+	Progress      int32                  `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"` // 0-100
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatus) Reset() {
+	*x = UpdateStatus{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatus) ProtoMessage() {}
+
+func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatus.ProtoReflect.Descriptor instead.
+func (*UpdateStatus) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UpdateStatus) GetUpdateId() string {
+	if x != nil {
+		return x.UpdateId
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ListRepositoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRepositoriesRequest) Reset() {
+	*x = ListRepositoriesRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRepositoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRepositoriesRequest) ProtoMessage() {}
+
+func (x *ListRepositoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRepositoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListRepositoriesRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{37}
+}
+
+type ListRepositoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Repositories  []string               `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRepositoriesResponse) Reset() {
+	*x = ListRepositoriesResponse{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRepositoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRepositoriesResponse) ProtoMessage() {}
+
+func (x *ListRepositoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRepositoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListRepositoriesResponse) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListRepositoriesResponse) GetRepositories() []string {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+func (x *ListRepositoriesResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ListAlertsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"` // Optional filter
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`                          // Optional filter
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListAlertsRequest) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *ListAlertsRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Severity      string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
+	ProcessName   string                 `protobuf:"bytes,4,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	Summary       string                 `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	Expr          string                 `protobuf:"bytes,6,opt,name=expr,proto3" json:"expr,omitempty"`
+	Value         string                 `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`                                 // Expression variables at the last evaluation
+	State         string                 `protobuf:"bytes,8,opt,name=state,proto3" json:"state,omitempty"`                                 // "pending" or "firing"
+	ActiveSince   int64                  `protobuf:"varint,9,opt,name=active_since,json=activeSince,proto3" json:"active_since,omitempty"` // Unix seconds
+	FiredAt       int64                  `protobuf:"varint,10,opt,name=fired_at,json=firedAt,proto3" json:"fired_at,omitempty"`            // Unix seconds, 0 while pending
+	Silenced      bool                   `protobuf:"varint,11,opt,name=silenced,proto3" json:"silenced,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Alert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Alert) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Alert) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Alert) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *Alert) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Alert) GetExpr() string {
+	if x != nil {
+		return x.Expr
+	}
+	return ""
+}
+
+func (x *Alert) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Alert) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Alert) GetActiveSince() int64 {
+	if x != nil {
+		return x.ActiveSince
+	}
+	return 0
+}
+
+func (x *Alert) GetFiredAt() int64 {
+	if x != nil {
+		return x.FiredAt
+	}
+	return 0
+}
+
+func (x *Alert) GetSilenced() bool {
+	if x != nil {
+		return x.Silenced
+	}
+	return false
+}
+
+type ListLoadBalancersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // Optional filter
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoadBalancersRequest) Reset() {
+	*x = ListLoadBalancersRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoadBalancersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoadBalancersRequest) ProtoMessage() {}
+
+func (x *ListLoadBalancersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[42]
 	if x != nil {
-		return x.ReleaseDate
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *UpdateAvailable) GetReleaseNotes() string {
-	if x != nil {
-		return x.ReleaseNotes
-	}
-	return ""
+// Deprecated: Use ListLoadBalancersRequest.ProtoReflect.Descriptor instead.
+func (*ListLoadBalancersRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *UpdateAvailable) GetUpToDate() bool {
+func (x *ListLoadBalancersRequest) GetName() string {
 	if x != nil {
-		return x.UpToDate
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-type RollbackRequest struct {
+type ListLoadBalancersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // empty string = previous version
+	LoadBalancers []*LoadBalancer        `protobuf:"bytes,1,rep,name=load_balancers,json=loadBalancers,proto3" json:"load_balancers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RollbackRequest) Reset() {
-	*x = RollbackRequest{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[27]
+func (x *ListLoadBalancersResponse) Reset() {
+	*x = ListLoadBalancersResponse{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RollbackRequest) String() string {
+func (x *ListLoadBalancersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RollbackRequest) ProtoMessage() {}
+func (*ListLoadBalancersResponse) ProtoMessage() {}
 
-func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[27]
+func (x *ListLoadBalancersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1802,51 +2898,45 @@ func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RollbackRequest.ProtoReflect.Descriptor instead.
-func (*RollbackRequest) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{27}
-}
-
-func (x *RollbackRequest) GetProcessName() string {
-	if x != nil {
-		return x.ProcessName
-	}
-	return ""
+// Deprecated: Use ListLoadBalancersResponse.ProtoReflect.Descriptor instead.
+func (*ListLoadBalancersResponse) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *RollbackRequest) GetVersion() string {
+func (x *ListLoadBalancersResponse) GetLoadBalancers() []*LoadBalancer {
 	if x != nil {
-		return x.Version
+		return x.LoadBalancers
 	}
-	return ""
+	return nil
 }
 
-type RollbackResponse struct {
+type LoadBalancer struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ProcessName   string                 `protobuf:"bytes,2,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	FromVersion   string                 `protobuf:"bytes,3,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
-	ToVersion     string                 `protobuf:"bytes,4,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
-	RollbackId    string                 `protobuf:"bytes,5,opt,name=rollback_id,json=rollbackId,proto3" json:"rollback_id,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ListenPort    int32                  `protobuf:"varint,2,opt,name=listen_port,json=listenPort,proto3" json:"listen_port,omitempty"`
+	Protocol      string                 `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"` // "grpc" or "http"
+	Tls           bool                   `protobuf:"varint,4,opt,name=tls,proto3" json:"tls,omitempty"`
+	Routes        []*LoadBalancerRoute   `protobuf:"bytes,5,rep,name=routes,proto3" json:"routes,omitempty"`
+	Backends      []*LoadBalancerBackend `protobuf:"bytes,6,rep,name=backends,proto3" json:"backends,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RollbackResponse) Reset() {
-	*x = RollbackResponse{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[28]
+func (x *LoadBalancer) Reset() {
+	*x = LoadBalancer{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RollbackResponse) String() string {
+func (x *LoadBalancer) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RollbackResponse) ProtoMessage() {}
+func (*LoadBalancer) ProtoMessage() {}
 
-func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[28]
+func (x *LoadBalancer) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1857,68 +2947,80 @@ func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RollbackResponse.ProtoReflect.Descriptor instead.
-func (*RollbackResponse) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use LoadBalancer.ProtoReflect.Descriptor instead.
+func (*LoadBalancer) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *RollbackResponse) GetSuccess() bool {
+func (x *LoadBalancer) GetName() string {
 	if x != nil {
-		return x.Success
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-func (x *RollbackResponse) GetProcessName() string {
+func (x *LoadBalancer) GetListenPort() int32 {
 	if x != nil {
-		return x.ProcessName
+		return x.ListenPort
 	}
-	return ""
+	return 0
 }
 
-func (x *RollbackResponse) GetFromVersion() string {
+func (x *LoadBalancer) GetProtocol() string {
 	if x != nil {
-		return x.FromVersion
+		return x.Protocol
 	}
 	return ""
 }
 
-func (x *RollbackResponse) GetToVersion() string {
+func (x *LoadBalancer) GetTls() bool {
 	if x != nil {
-		return x.ToVersion
+		return x.Tls
 	}
-	return ""
+	return false
 }
 
-func (x *RollbackResponse) GetRollbackId() string {
+func (x *LoadBalancer) GetRoutes() []*LoadBalancerRoute {
 	if x != nil {
-		return x.RollbackId
+		return x.Routes
 	}
-	return ""
+	return nil
 }
 
-type WatchUpdateRequest struct {
+func (x *LoadBalancer) GetBackends() []*LoadBalancerBackend {
+	if x != nil {
+		return x.Backends
+	}
+	return nil
+}
+
+type LoadBalancerRoute struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UpdateId      string                 `protobuf:"bytes,1,opt,name=update_id,json=updateId,proto3" json:"update_id,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Strategy      string                 `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Targets       []string               `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	Weights       map[string]int32       `protobuf:"bytes,4,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`   // Empty if traffic is not split
+	Requests      map[string]uint64      `protobuf:"bytes,5,rep,name=requests,proto3" json:"requests,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Requests sent to each target
+	Default       bool                   `protobuf:"varint,6,opt,name=default,proto3" json:"default,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchUpdateRequest) Reset() {
-	*x = WatchUpdateRequest{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[29]
+func (x *LoadBalancerRoute) Reset() {
+	*x = LoadBalancerRoute{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchUpdateRequest) String() string {
+func (x *LoadBalancerRoute) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchUpdateRequest) ProtoMessage() {}
+func (*LoadBalancerRoute) ProtoMessage() {}
 
-func (x *WatchUpdateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[29]
+func (x *LoadBalancerRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1929,45 +3031,86 @@ func (x *WatchUpdateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchUpdateRequest.ProtoReflect.Descriptor instead.
-func (*WatchUpdateRequest) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use LoadBalancerRoute.ProtoReflect.Descriptor instead.
+func (*LoadBalancerRoute) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *WatchUpdateRequest) GetUpdateId() string {
+func (x *LoadBalancerRoute) GetName() string {
 	if x != nil {
-		return x.UpdateId
+		return x.Name
 	}
 	return ""
 }
 
-type UpdateStatus struct {
+func (x *LoadBalancerRoute) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *LoadBalancerRoute) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *LoadBalancerRoute) GetWeights() map[string]int32 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+func (x *LoadBalancerRoute) GetRequests() map[string]uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *LoadBalancerRoute) GetDefault() bool {
+	if x != nil {
+		return x.Default
+	}
+	return false
+}
+
+type LoadBalancerBackend struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UpdateId      string                 `protobuf:"bytes,1,opt,name=update_id,json=updateId,proto3" json:"update_id,omitempty"`
-	ProcessName   string                 `protobuf:"bytes,2,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`      // downloading, extracting, stopping, starting, completed, failed
-	Progress      int32                  `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"` // 0-100
-	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp
+	ProcessName   string                 `protobuf:"bytes,1,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	State         string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"` // "healthy", "draining", "ejected" or "unhealthy"
+	Connectivity  string                 `protobuf:"bytes,5,opt,name=connectivity,proto3" json:"connectivity,omitempty"`
+	InFlight      int64                  `protobuf:"varint,6,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	Requests      uint64                 `protobuf:"varint,7,opt,name=requests,proto3" json:"requests,omitempty"`
+	Errors        uint64                 `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
+	LatencyAvgMs  float64                `protobuf:"fixed64,9,opt,name=latency_avg_ms,json=latencyAvgMs,proto3" json:"latency_avg_ms,omitempty"`
+	LatencyEwmaMs float64                `protobuf:"fixed64,10,opt,name=latency_ewma_ms,json=latencyEwmaMs,proto3" json:"latency_ewma_ms,omitempty"`
+	EjectedUntil  int64                  `protobuf:"varint,11,opt,name=ejected_until,json=ejectedUntil,proto3" json:"ejected_until,omitempty"` // Unix seconds, 0 if not ejected
+	Ejections     int32                  `protobuf:"varint,12,opt,name=ejections,proto3" json:"ejections,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateStatus) Reset() {
-	*x = UpdateStatus{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[30]
+func (x *LoadBalancerBackend) Reset() {
+	*x = LoadBalancerBackend{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateStatus) String() string {
+func (x *LoadBalancerBackend) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateStatus) ProtoMessage() {}
+func (*LoadBalancerBackend) ProtoMessage() {}
 
-func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[30]
+func (x *LoadBalancerBackend) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1978,74 +3121,119 @@ func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateStatus.ProtoReflect.Descriptor instead.
-func (*UpdateStatus) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use LoadBalancerBackend.ProtoReflect.Descriptor instead.
+func (*LoadBalancerBackend) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *UpdateStatus) GetUpdateId() string {
+func (x *LoadBalancerBackend) GetProcessName() string {
 	if x != nil {
-		return x.UpdateId
+		return x.ProcessName
 	}
 	return ""
 }
 
-func (x *UpdateStatus) GetProcessName() string {
+func (x *LoadBalancerBackend) GetInstanceId() string {
 	if x != nil {
-		return x.ProcessName
+		return x.InstanceId
 	}
 	return ""
 }
 
-func (x *UpdateStatus) GetStatus() string {
+func (x *LoadBalancerBackend) GetAddress() string {
 	if x != nil {
-		return x.Status
+		return x.Address
 	}
 	return ""
 }
 
-func (x *UpdateStatus) GetProgress() int32 {
+func (x *LoadBalancerBackend) GetState() string {
 	if x != nil {
-		return x.Progress
+		return x.State
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdateStatus) GetMessage() string {
+func (x *LoadBalancerBackend) GetConnectivity() string {
 	if x != nil {
-		return x.Message
+		return x.Connectivity
 	}
 	return ""
 }
 
-func (x *UpdateStatus) GetTimestamp() int64 {
+func (x *LoadBalancerBackend) GetInFlight() int64 {
 	if x != nil {
-		return x.Timestamp
+		return x.InFlight
 	}
 	return 0
 }
 
-type ListRepositoriesRequest struct {
+func (x *LoadBalancerBackend) GetRequests() uint64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetLatencyAvgMs() float64 {
+	if x != nil {
+		return x.LatencyAvgMs
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetLatencyEwmaMs() float64 {
+	if x != nil {
+		return x.LatencyEwmaMs
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetEjectedUntil() int64 {
+	if x != nil {
+		return x.EjectedUntil
+	}
+	return 0
+}
+
+func (x *LoadBalancerBackend) GetEjections() int32 {
+	if x != nil {
+		return x.Ejections
+	}
+	return 0
+}
+
+type SetBackendDrainingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoadBalancer  string                 `protobuf:"bytes,1,opt,name=load_balancer,json=loadBalancer,proto3" json:"load_balancer,omitempty"`
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Draining      bool                   `protobuf:"varint,3,opt,name=draining,proto3" json:"draining,omitempty"` // false puts the backend back into rotation
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRepositoriesRequest) Reset() {
-	*x = ListRepositoriesRequest{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[31]
+func (x *SetBackendDrainingRequest) Reset() {
+	*x = SetBackendDrainingRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRepositoriesRequest) String() string {
+func (x *SetBackendDrainingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRepositoriesRequest) ProtoMessage() {}
+func (*SetBackendDrainingRequest) ProtoMessage() {}
 
-func (x *ListRepositoriesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[31]
+func (x *SetBackendDrainingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2056,34 +3244,54 @@ func (x *ListRepositoriesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRepositoriesRequest.ProtoReflect.Descriptor instead.
-func (*ListRepositoriesRequest) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use SetBackendDrainingRequest.ProtoReflect.Descriptor instead.
+func (*SetBackendDrainingRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{47}
 }
 
-type ListRepositoriesResponse struct {
+func (x *SetBackendDrainingRequest) GetLoadBalancer() string {
+	if x != nil {
+		return x.LoadBalancer
+	}
+	return ""
+}
+
+func (x *SetBackendDrainingRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *SetBackendDrainingRequest) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+type ResetLoadBalancerStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Repositories  []string               `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
-	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	LoadBalancer  string                 `protobuf:"bytes,1,opt,name=load_balancer,json=loadBalancer,proto3" json:"load_balancer,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRepositoriesResponse) Reset() {
-	*x = ListRepositoriesResponse{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[32]
+func (x *ResetLoadBalancerStatsRequest) Reset() {
+	*x = ResetLoadBalancerStatsRequest{}
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRepositoriesResponse) String() string {
+func (x *ResetLoadBalancerStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRepositoriesResponse) ProtoMessage() {}
+func (*ResetLoadBalancerStatsRequest) ProtoMessage() {}
 
-func (x *ListRepositoriesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[32]
+func (x *ResetLoadBalancerStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2094,23 +3302,16 @@ func (x *ListRepositoriesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRepositoriesResponse.ProtoReflect.Descriptor instead.
-func (*ListRepositoriesResponse) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{32}
-}
-
-func (x *ListRepositoriesResponse) GetRepositories() []string {
-	if x != nil {
-		return x.Repositories
-	}
-	return nil
+// Deprecated: Use ResetLoadBalancerStatsRequest.ProtoReflect.Descriptor instead.
+func (*ResetLoadBalancerStatsRequest) Descriptor() ([]byte, []int) {
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *ListRepositoriesResponse) GetCount() int32 {
+func (x *ResetLoadBalancerStatsRequest) GetLoadBalancer() string {
 	if x != nil {
-		return x.Count
+		return x.LoadBalancer
 	}
-	return 0
+	return ""
 }
 
 type Empty struct {
@@ -2121,7 +3322,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[33]
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2133,7 +3334,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_src_internal_proto_process_manager_proto_msgTypes[33]
+	mi := &file_src_internal_proto_process_manager_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2146,7 +3347,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{33}
+	return file_src_internal_proto_process_manager_proto_rawDescGZIP(), []int{49}
 }
 
 var File_src_internal_proto_process_manager_proto protoreflect.FileDescriptor
@@ -2222,7 +3423,7 @@ const file_src_internal_proto_process_manager_proto_rawDesc = "" +
 	"\tinstances\x18\x02 \x03(\v2\x15.proto.ProcessMetricsR\tinstances\x128\n" +
 	"\n" +
 	"aggregated\x18\x03 \x01(\v2\x18.proto.AggregatedMetricsR\n" +
-	"aggregated\"\x8b\x02\n" +
+	"aggregated\"\xfc\x05\n" +
 	"\x0eProcessMetrics\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\tR\n" +
 	"instanceId\x12\x1b\n" +
@@ -2233,7 +3434,21 @@ const file_src_internal_proto_process_manager_proto_rawDesc = "" +
 	"disk_write\x18\x05 \x01(\x04R\tdiskWrite\x12!\n" +
 	"\fnetwork_recv\x18\x06 \x01(\x04R\vnetworkRecv\x12!\n" +
 	"\fnetwork_sent\x18\a \x01(\x04R\vnetworkSent\x12\x16\n" +
-	"\x06uptime\x18\b \x01(\x03R\x06uptime\"\xbe\x02\n" +
+	"\x06uptime\x18\b \x01(\x03R\x06uptime\x12$\n" +
+	"\x0edisk_read_rate\x18\t \x01(\x01R\fdiskReadRate\x12&\n" +
+	"\x0fdisk_write_rate\x18\n" +
+	" \x01(\x01R\rdiskWriteRate\x12*\n" +
+	"\x11network_recv_rate\x18\v \x01(\x01R\x0fnetworkRecvRate\x12*\n" +
+	"\x11network_sent_rate\x18\f \x01(\x01R\x0fnetworkSentRate\x12\"\n" +
+	"\rdisk_read_ops\x18\r \x01(\x04R\vdiskReadOps\x12$\n" +
+	"\x0edisk_write_ops\x18\x0e \x01(\x04R\fdiskWriteOps\x12!\n" +
+	"\fopen_handles\x18\x0f \x01(\x05R\vopenHandles\x12\x18\n" +
+	"\athreads\x18\x10 \x01(\x05R\athreads\x12R\n" +
+	"\x0ftcp_connections\x18\x11 \x03(\v2).proto.ProcessMetrics.TcpConnectionsEntryR\x0etcpConnections\x12+\n" +
+	"\x11network_supported\x18\x12 \x01(\bR\x10networkSupported\x1aA\n" +
+	"\x13TcpConnectionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x97\x05\n" +
 	"\x11AggregatedMetrics\x12&\n" +
 	"\x0ftotal_cpu_usage\x18\x01 \x01(\x01R\rtotalCpuUsage\x12,\n" +
 	"\x12total_memory_usage\x18\x02 \x01(\x04R\x10totalMemoryUsage\x12&\n" +
@@ -2241,7 +3456,41 @@ const file_src_internal_proto_process_manager_proto_rawDesc = "" +
 	"\x10total_disk_write\x18\x04 \x01(\x04R\x0etotalDiskWrite\x12,\n" +
 	"\x12total_network_recv\x18\x05 \x01(\x04R\x10totalNetworkRecv\x12,\n" +
 	"\x12total_network_sent\x18\x06 \x01(\x04R\x10totalNetworkSent\x12%\n" +
-	"\x0einstance_count\x18\a \x01(\x05R\rinstanceCount\"c\n" +
+	"\x0einstance_count\x18\a \x01(\x05R\rinstanceCount\x12/\n" +
+	"\x14total_disk_read_rate\x18\b \x01(\x01R\x11totalDiskReadRate\x121\n" +
+	"\x15total_disk_write_rate\x18\t \x01(\x01R\x12totalDiskWriteRate\x125\n" +
+	"\x17total_network_recv_rate\x18\n" +
+	" \x01(\x01R\x14totalNetworkRecvRate\x125\n" +
+	"\x17total_network_sent_rate\x18\v \x01(\x01R\x14totalNetworkSentRate\x12,\n" +
+	"\x12total_open_handles\x18\f \x01(\x05R\x10totalOpenHandles\x12#\n" +
+	"\rtotal_threads\x18\r \x01(\x05R\ftotalThreads\x122\n" +
+	"\x15total_tcp_connections\x18\x0e \x01(\x05R\x13totalTcpConnections\"d\n" +
+	"\x14StreamMetricsRequest\x12!\n" +
+	"\fprocess_name\x18\x01 \x01(\tR\vprocessName\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"]\n" +
+	"\x0fMetricsSnapshot\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12,\n" +
+	"\tprocesses\x18\x02 \x03(\v2\x0e.proto.MetricsR\tprocesses\"\xc1\x01\n" +
+	"\x13QueryMetricsRequest\x12!\n" +
+	"\fprocess_name\x18\x01 \x01(\tR\vprocessName\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12\x18\n" +
+	"\ametrics\x18\x03 \x03(\tR\ametrics\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x05 \x01(\x03R\aendTime\x12\x12\n" +
+	"\x04step\x18\x06 \x01(\x03R\x04step\"C\n" +
+	"\x14QueryMetricsResponse\x12+\n" +
+	"\x06series\x18\x01 \x03(\v2\x13.proto.MetricSeriesR\x06series\"\x96\x01\n" +
+	"\fMetricSeries\x12!\n" +
+	"\fprocess_name\x18\x01 \x01(\tR\vprocessName\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12\x16\n" +
+	"\x06metric\x18\x03 \x01(\tR\x06metric\x12*\n" +
+	"\x06points\x18\x04 \x03(\v2\x12.proto.MetricPointR\x06points\"A\n" +
+	"\vMetricPoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"c\n" +
 	"\x13ScaleProcessRequest\x12!\n" +
 	"\fprocess_name\x18\x01 \x01(\tR\vprocessName\x12)\n" +
 	"\x10target_instances\x18\x02 \x01(\x05R\x0ftargetInstances\"\x8c\x01\n" +
@@ -2313,8 +3562,73 @@ const file_src_internal_proto_process_manager_proto_rawDesc = "" +
 	"\x17ListRepositoriesRequest\"T\n" +
 	"\x18ListRepositoriesResponse\x12\"\n" +
 	"\frepositories\x18\x01 \x03(\tR\frepositories\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\x05R\x05count\"\a\n" +
-	"\x05Empty2\xc3\a\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"R\n" +
+	"\x11ListAlertsRequest\x12!\n" +
+	"\fprocess_name\x18\x01 \x01(\tR\vprocessName\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\":\n" +
+	"\x12ListAlertsResponse\x12$\n" +
+	"\x06alerts\x18\x01 \x03(\v2\f.proto.AlertR\x06alerts\"\x9e\x02\n" +
+	"\x05Alert\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bseverity\x18\x03 \x01(\tR\bseverity\x12!\n" +
+	"\fprocess_name\x18\x04 \x01(\tR\vprocessName\x12\x18\n" +
+	"\asummary\x18\x05 \x01(\tR\asummary\x12\x12\n" +
+	"\x04expr\x18\x06 \x01(\tR\x04expr\x12\x14\n" +
+	"\x05value\x18\a \x01(\tR\x05value\x12\x14\n" +
+	"\x05state\x18\b \x01(\tR\x05state\x12!\n" +
+	"\factive_since\x18\t \x01(\x03R\vactiveSince\x12\x19\n" +
+	"\bfired_at\x18\n" +
+	" \x01(\x03R\afiredAt\x12\x1a\n" +
+	"\bsilenced\x18\v \x01(\bR\bsilenced\".\n" +
+	"\x18ListLoadBalancersRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"W\n" +
+	"\x19ListLoadBalancersResponse\x12:\n" +
+	"\x0eload_balancers\x18\x01 \x03(\v2\x13.proto.LoadBalancerR\rloadBalancers\"\xdb\x01\n" +
+	"\fLoadBalancer\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vlisten_port\x18\x02 \x01(\x05R\n" +
+	"listenPort\x12\x1a\n" +
+	"\bprotocol\x18\x03 \x01(\tR\bprotocol\x12\x10\n" +
+	"\x03tls\x18\x04 \x01(\bR\x03tls\x120\n" +
+	"\x06routes\x18\x05 \x03(\v2\x18.proto.LoadBalancerRouteR\x06routes\x126\n" +
+	"\bbackends\x18\x06 \x03(\v2\x1a.proto.LoadBalancerBackendR\bbackends\"\xf5\x02\n" +
+	"\x11LoadBalancerRoute\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bstrategy\x18\x02 \x01(\tR\bstrategy\x12\x18\n" +
+	"\atargets\x18\x03 \x03(\tR\atargets\x12?\n" +
+	"\aweights\x18\x04 \x03(\v2%.proto.LoadBalancerRoute.WeightsEntryR\aweights\x12B\n" +
+	"\brequests\x18\x05 \x03(\v2&.proto.LoadBalancerRoute.RequestsEntryR\brequests\x12\x18\n" +
+	"\adefault\x18\x06 \x01(\bR\adefault\x1a:\n" +
+	"\fWeightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a;\n" +
+	"\rRequestsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"\x8f\x03\n" +
+	"\x13LoadBalancerBackend\x12!\n" +
+	"\fprocess_name\x18\x01 \x01(\tR\vprocessName\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12\"\n" +
+	"\fconnectivity\x18\x05 \x01(\tR\fconnectivity\x12\x1b\n" +
+	"\tin_flight\x18\x06 \x01(\x03R\binFlight\x12\x1a\n" +
+	"\brequests\x18\a \x01(\x04R\brequests\x12\x16\n" +
+	"\x06errors\x18\b \x01(\x04R\x06errors\x12$\n" +
+	"\x0elatency_avg_ms\x18\t \x01(\x01R\flatencyAvgMs\x12&\n" +
+	"\x0flatency_ewma_ms\x18\n" +
+	" \x01(\x01R\rlatencyEwmaMs\x12#\n" +
+	"\rejected_until\x18\v \x01(\x03R\fejectedUntil\x12\x1c\n" +
+	"\tejections\x18\f \x01(\x05R\tejections\"}\n" +
+	"\x19SetBackendDrainingRequest\x12#\n" +
+	"\rload_balancer\x18\x01 \x01(\tR\floadBalancer\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12\x1a\n" +
+	"\bdraining\x18\x03 \x01(\bR\bdraining\"D\n" +
+	"\x1dResetLoadBalancerStatsRequest\x12#\n" +
+	"\rload_balancer\x18\x01 \x01(\tR\floadBalancer\"\a\n" +
+	"\x05Empty2\x91\v\n" +
 	"\x0eProcessManager\x12J\n" +
 	"\rListProcesses\x12\x1b.proto.ListProcessesRequest\x1a\x1c.proto.ListProcessesResponse\x12:\n" +
 	"\n" +
@@ -2324,14 +3638,21 @@ const file_src_internal_proto_process_manager_proto_rawDesc = "" +
 	"\x0eRestartProcess\x12\x1c.proto.RestartProcessRequest\x1a\x12.proto.ProcessInfo\x126\n" +
 	"\n" +
 	"GetMetrics\x12\x18.proto.GetMetricsRequest\x1a\x0e.proto.Metrics\x12>\n" +
-	"\fScaleProcess\x12\x1a.proto.ScaleProcessRequest\x1a\x12.proto.ProcessInfo\x12D\n" +
+	"\fScaleProcess\x12\x1a.proto.ScaleProcessRequest\x1a\x12.proto.ProcessInfo\x12G\n" +
+	"\fQueryMetrics\x12\x1a.proto.QueryMetricsRequest\x1a\x1b.proto.QueryMetricsResponse\x12D\n" +
 	"\x12UpdateAllProcesses\x12\x17.proto.UpdateAllRequest\x1a\x15.proto.UpdateResponse\x12C\n" +
 	"\rUpdateProcess\x12\x1b.proto.UpdateProcessRequest\x1a\x15.proto.UpdateResponse\x12A\n" +
 	"\x11GetProcessVersion\x12\x18.proto.GetVersionRequest\x1a\x12.proto.VersionInfo\x12M\n" +
 	"\x14ListAvailableUpdates\x12\x19.proto.ListUpdatesRequest\x1a\x1a.proto.ListUpdatesResponse\x12B\n" +
 	"\x0fRollbackProcess\x12\x16.proto.RollbackRequest\x1a\x17.proto.RollbackResponse\x12?\n" +
-	"\vWatchUpdate\x12\x19.proto.WatchUpdateRequest\x1a\x13.proto.UpdateStatus0\x01\x12S\n" +
-	"\x10ListRepositories\x12\x1e.proto.ListRepositoriesRequest\x1a\x1f.proto.ListRepositoriesResponseB?Z=github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/protob\x06proto3"
+	"\vWatchUpdate\x12\x19.proto.WatchUpdateRequest\x1a\x13.proto.UpdateStatus0\x01\x12F\n" +
+	"\rStreamMetrics\x12\x1b.proto.StreamMetricsRequest\x1a\x16.proto.MetricsSnapshot0\x01\x12S\n" +
+	"\x10ListRepositories\x12\x1e.proto.ListRepositoriesRequest\x1a\x1f.proto.ListRepositoriesResponse\x12A\n" +
+	"\n" +
+	"ListAlerts\x12\x18.proto.ListAlertsRequest\x1a\x19.proto.ListAlertsResponse\x12V\n" +
+	"\x11ListLoadBalancers\x12\x1f.proto.ListLoadBalancersRequest\x1a .proto.ListLoadBalancersResponse\x12R\n" +
+	"\x12SetBackendDraining\x12 .proto.SetBackendDrainingRequest\x1a\x1a.proto.LoadBalancerBackend\x12L\n" +
+	"\x16ResetLoadBalancerStats\x12$.proto.ResetLoadBalancerStatsRequest\x1a\f.proto.EmptyB?Z=github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/protob\x06proto3"
 
 var (
 	file_src_internal_proto_process_manager_proto_rawDescOnce sync.Once
@@ -2345,42 +3666,61 @@ func file_src_internal_proto_process_manager_proto_rawDescGZIP() []byte {
 	return file_src_internal_proto_process_manager_proto_rawDescData
 }
 
-var file_src_internal_proto_process_manager_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_src_internal_proto_process_manager_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
 var file_src_internal_proto_process_manager_proto_goTypes = []any{
-	(*ListProcessesRequest)(nil),     // 0: proto.ListProcessesRequest
-	(*ListProcessesResponse)(nil),    // 1: proto.ListProcessesResponse
-	(*GetProcessRequest)(nil),        // 2: proto.GetProcessRequest
-	(*ProcessInfo)(nil),              // 3: proto.ProcessInfo
-	(*ProcessInstance)(nil),          // 4: proto.ProcessInstance
-	(*ProcessConfig)(nil),            // 5: proto.ProcessConfig
-	(*GitHubConfig)(nil),             // 6: proto.GitHubConfig
-	(*SecretsConfig)(nil),            // 7: proto.SecretsConfig
-	(*CertificatesConfig)(nil),       // 8: proto.CertificatesConfig
-	(*StartProcessRequest)(nil),      // 9: proto.StartProcessRequest
-	(*StopProcessRequest)(nil),       // 10: proto.StopProcessRequest
-	(*RestartProcessRequest)(nil),    // 11: proto.RestartProcessRequest
-	(*GetMetricsRequest)(nil),        // 12: proto.GetMetricsRequest
-	(*Metrics)(nil),                  // 13: proto.Metrics
-	(*ProcessMetrics)(nil),           // 14: proto.ProcessMetrics
-	(*AggregatedMetrics)(nil),        // 15: proto.AggregatedMetrics
-	(*ScaleProcessRequest)(nil),      // 16: proto.ScaleProcessRequest
-	(*UpdateAllRequest)(nil),         // 17: proto.UpdateAllRequest
-	(*UpdateProcessRequest)(nil),     // 18: proto.UpdateProcessRequest
-	(*UpdateResponse)(nil),           // 19: proto.UpdateResponse
-	(*ProcessUpdateStatus)(nil),      // 20: proto.ProcessUpdateStatus
-	(*GetVersionRequest)(nil),        // 21: proto.GetVersionRequest
-	(*VersionInfo)(nil),              // 22: proto.VersionInfo
-	(*InstanceVersion)(nil),          // 23: proto.InstanceVersion
-	(*ListUpdatesRequest)(nil),       // 24: proto.ListUpdatesRequest
-	(*ListUpdatesResponse)(nil),      // 25: proto.ListUpdatesResponse
-	(*UpdateAvailable)(nil),          // 26: proto.UpdateAvailable
-	(*RollbackRequest)(nil),          // 27: proto.RollbackRequest
-	(*RollbackResponse)(nil),         // 28: proto.RollbackResponse
-	(*WatchUpdateRequest)(nil),       // 29: proto.WatchUpdateRequest
-	(*UpdateStatus)(nil),             // 30: proto.UpdateStatus
-	(*ListRepositoriesRequest)(nil),  // 31: proto.ListRepositoriesRequest
-	(*ListRepositoriesResponse)(nil), // 32: proto.ListRepositoriesResponse
-	(*Empty)(nil),                    // 33: proto.Empty
+	(*ListProcessesRequest)(nil),          // 0: proto.ListProcessesRequest
+	(*ListProcessesResponse)(nil),         // 1: proto.ListProcessesResponse
+	(*GetProcessRequest)(nil),             // 2: proto.GetProcessRequest
+	(*ProcessInfo)(nil),                   // 3: proto.ProcessInfo
+	(*ProcessInstance)(nil),               // 4: proto.ProcessInstance
+	(*ProcessConfig)(nil),                 // 5: proto.ProcessConfig
+	(*GitHubConfig)(nil),                  // 6: proto.GitHubConfig
+	(*SecretsConfig)(nil),                 // 7: proto.SecretsConfig
+	(*CertificatesConfig)(nil),            // 8: proto.CertificatesConfig
+	(*StartProcessRequest)(nil),           // 9: proto.StartProcessRequest
+	(*StopProcessRequest)(nil),            // 10: proto.StopProcessRequest
+	(*RestartProcessRequest)(nil),         // 11: proto.RestartProcessRequest
+	(*GetMetricsRequest)(nil),             // 12: proto.GetMetricsRequest
+	(*Metrics)(nil),                       // 13: proto.Metrics
+	(*ProcessMetrics)(nil),                // 14: proto.ProcessMetrics
+	(*AggregatedMetrics)(nil),             // 15: proto.AggregatedMetrics
+	(*StreamMetricsRequest)(nil),          // 16: proto.StreamMetricsRequest
+	(*MetricsSnapshot)(nil),               // 17: proto.MetricsSnapshot
+	(*QueryMetricsRequest)(nil),           // 18: proto.QueryMetricsRequest
+	(*QueryMetricsResponse)(nil),          // 19: proto.QueryMetricsResponse
+	(*MetricSeries)(nil),                  // 20: proto.MetricSeries
+	(*MetricPoint)(nil),                   // 21: proto.MetricPoint
+	(*ScaleProcessRequest)(nil),           // 22: proto.ScaleProcessRequest
+	(*UpdateAllRequest)(nil),              // 23: proto.UpdateAllRequest
+	(*UpdateProcessRequest)(nil),          // 24: proto.UpdateProcessRequest
+	(*UpdateResponse)(nil),                // 25: proto.UpdateResponse
+	(*ProcessUpdateStatus)(nil),           // 26: proto.ProcessUpdateStatus
+	(*GetVersionRequest)(nil),             // 27: proto.GetVersionRequest
+	(*VersionInfo)(nil),                   // 28: proto.VersionInfo
+	(*InstanceVersion)(nil),               // 29: proto.InstanceVersion
+	(*ListUpdatesRequest)(nil),            // 30: proto.ListUpdatesRequest
+	(*ListUpdatesResponse)(nil),           // 31: proto.ListUpdatesResponse
+	(*UpdateAvailable)(nil),               // 32: proto.UpdateAvailable
+	(*RollbackRequest)(nil),               // 33: proto.RollbackRequest
+	(*RollbackResponse)(nil),              // 34: proto.RollbackResponse
+	(*WatchUpdateRequest)(nil),            // 35: proto.WatchUpdateRequest
+	(*UpdateStatus)(nil),                  // 36: proto.UpdateStatus
+	(*ListRepositoriesRequest)(nil),       // 37: proto.ListRepositoriesRequest
+	(*ListRepositoriesResponse)(nil),      // 38: proto.ListRepositoriesResponse
+	(*ListAlertsRequest)(nil),             // 39: proto.ListAlertsRequest
+	(*ListAlertsResponse)(nil),            // 40: proto.ListAlertsResponse
+	(*Alert)(nil),                         // 41: proto.Alert
+	(*ListLoadBalancersRequest)(nil),      // 42: proto.ListLoadBalancersRequest
+	(*ListLoadBalancersResponse)(nil),     // 43: proto.ListLoadBalancersResponse
+	(*LoadBalancer)(nil),                  // 44: proto.LoadBalancer
+	(*LoadBalancerRoute)(nil),             // 45: proto.LoadBalancerRoute
+	(*LoadBalancerBackend)(nil),           // 46: proto.LoadBalancerBackend
+	(*SetBackendDrainingRequest)(nil),     // 47: proto.SetBackendDrainingRequest
+	(*ResetLoadBalancerStatsRequest)(nil), // 48: proto.ResetLoadBalancerStatsRequest
+	(*Empty)(nil),                         // 49: proto.Empty
+	nil,                                   // 50: proto.ProcessMetrics.TcpConnectionsEntry
+	nil,                                   // 51: proto.LoadBalancerRoute.WeightsEntry
+	nil,                                   // 52: proto.LoadBalancerRoute.RequestsEntry
 }
 var file_src_internal_proto_process_manager_proto_depIdxs = []int32{
 	4,  // 0: proto.ProcessInfo.instances:type_name -> proto.ProcessInstance
@@ -2391,42 +3731,64 @@ var file_src_internal_proto_process_manager_proto_depIdxs = []int32{
 	8,  // 5: proto.ProcessConfig.certificates:type_name -> proto.CertificatesConfig
 	14, // 6: proto.Metrics.instances:type_name -> proto.ProcessMetrics
 	15, // 7: proto.Metrics.aggregated:type_name -> proto.AggregatedMetrics
-	20, // 8: proto.UpdateResponse.processes:type_name -> proto.ProcessUpdateStatus
-	23, // 9: proto.VersionInfo.instances:type_name -> proto.InstanceVersion
-	26, // 10: proto.ListUpdatesResponse.updates:type_name -> proto.UpdateAvailable
-	0,  // 11: proto.ProcessManager.ListProcesses:input_type -> proto.ListProcessesRequest
-	2,  // 12: proto.ProcessManager.GetProcess:input_type -> proto.GetProcessRequest
-	9,  // 13: proto.ProcessManager.StartProcess:input_type -> proto.StartProcessRequest
-	10, // 14: proto.ProcessManager.StopProcess:input_type -> proto.StopProcessRequest
-	11, // 15: proto.ProcessManager.RestartProcess:input_type -> proto.RestartProcessRequest
-	12, // 16: proto.ProcessManager.GetMetrics:input_type -> proto.GetMetricsRequest
-	16, // 17: proto.ProcessManager.ScaleProcess:input_type -> proto.ScaleProcessRequest
-	17, // 18: proto.ProcessManager.UpdateAllProcesses:input_type -> proto.UpdateAllRequest
-	18, // 19: proto.ProcessManager.UpdateProcess:input_type -> proto.UpdateProcessRequest
-	21, // 20: proto.ProcessManager.GetProcessVersion:input_type -> proto.GetVersionRequest
-	24, // 21: proto.ProcessManager.ListAvailableUpdates:input_type -> proto.ListUpdatesRequest
-	27, // 22: proto.ProcessManager.RollbackProcess:input_type -> proto.RollbackRequest
-	29, // 23: proto.ProcessManager.WatchUpdate:input_type -> proto.WatchUpdateRequest
-	31, // 24: proto.ProcessManager.ListRepositories:input_type -> proto.ListRepositoriesRequest
-	1,  // 25: proto.ProcessManager.ListProcesses:output_type -> proto.ListProcessesResponse
-	3,  // 26: proto.ProcessManager.GetProcess:output_type -> proto.ProcessInfo
-	3,  // 27: proto.ProcessManager.StartProcess:output_type -> proto.ProcessInfo
-	33, // 28: proto.ProcessManager.StopProcess:output_type -> proto.Empty
-	3,  // 29: proto.ProcessManager.RestartProcess:output_type -> proto.ProcessInfo
-	13, // 30: proto.ProcessManager.GetMetrics:output_type -> proto.Metrics
-	3,  // 31: proto.ProcessManager.ScaleProcess:output_type -> proto.ProcessInfo
-	19, // 32: proto.ProcessManager.UpdateAllProcesses:output_type -> proto.UpdateResponse
-	19, // 33: proto.ProcessManager.UpdateProcess:output_type -> proto.UpdateResponse
-	22, // 34: proto.ProcessManager.GetProcessVersion:output_type -> proto.VersionInfo
-	25, // 35: proto.ProcessManager.ListAvailableUpdates:output_type -> proto.ListUpdatesResponse
-	28, // 36: proto.ProcessManager.RollbackProcess:output_type -> proto.RollbackResponse
-	30, // 37: proto.ProcessManager.WatchUpdate:output_type -> proto.UpdateStatus
-	32, // 38: proto.ProcessManager.ListRepositories:output_type -> proto.ListRepositoriesResponse
-	25, // [25:39] is the sub-list for method output_type
-	11, // [11:25] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	50, // 8: proto.ProcessMetrics.tcp_connections:type_name -> proto.ProcessMetrics.TcpConnectionsEntry
+	13, // 9: proto.MetricsSnapshot.processes:type_name -> proto.Metrics
+	20, // 10: proto.QueryMetricsResponse.series:type_name -> proto.MetricSeries
+	21, // 11: proto.MetricSeries.points:type_name -> proto.MetricPoint
+	26, // 12: proto.UpdateResponse.processes:type_name -> proto.ProcessUpdateStatus
+	29, // 13: proto.VersionInfo.instances:type_name -> proto.InstanceVersion
+	32, // 14: proto.ListUpdatesResponse.updates:type_name -> proto.UpdateAvailable
+	41, // 15: proto.ListAlertsResponse.alerts:type_name -> proto.Alert
+	44, // 16: proto.ListLoadBalancersResponse.load_balancers:type_name -> proto.LoadBalancer
+	45, // 17: proto.LoadBalancer.routes:type_name -> proto.LoadBalancerRoute
+	46, // 18: proto.LoadBalancer.backends:type_name -> proto.LoadBalancerBackend
+	51, // 19: proto.LoadBalancerRoute.weights:type_name -> proto.LoadBalancerRoute.WeightsEntry
+	52, // 20: proto.LoadBalancerRoute.requests:type_name -> proto.LoadBalancerRoute.RequestsEntry
+	0,  // 21: proto.ProcessManager.ListProcesses:input_type -> proto.ListProcessesRequest
+	2,  // 22: proto.ProcessManager.GetProcess:input_type -> proto.GetProcessRequest
+	9,  // 23: proto.ProcessManager.StartProcess:input_type -> proto.StartProcessRequest
+	10, // 24: proto.ProcessManager.StopProcess:input_type -> proto.StopProcessRequest
+	11, // 25: proto.ProcessManager.RestartProcess:input_type -> proto.RestartProcessRequest
+	12, // 26: proto.ProcessManager.GetMetrics:input_type -> proto.GetMetricsRequest
+	22, // 27: proto.ProcessManager.ScaleProcess:input_type -> proto.ScaleProcessRequest
+	18, // 28: proto.ProcessManager.QueryMetrics:input_type -> proto.QueryMetricsRequest
+	23, // 29: proto.ProcessManager.UpdateAllProcesses:input_type -> proto.UpdateAllRequest
+	24, // 30: proto.ProcessManager.UpdateProcess:input_type -> proto.UpdateProcessRequest
+	27, // 31: proto.ProcessManager.GetProcessVersion:input_type -> proto.GetVersionRequest
+	30, // 32: proto.ProcessManager.ListAvailableUpdates:input_type -> proto.ListUpdatesRequest
+	33, // 33: proto.ProcessManager.RollbackProcess:input_type -> proto.RollbackRequest
+	35, // 34: proto.ProcessManager.WatchUpdate:input_type -> proto.WatchUpdateRequest
+	16, // 35: proto.ProcessManager.StreamMetrics:input_type -> proto.StreamMetricsRequest
+	37, // 36: proto.ProcessManager.ListRepositories:input_type -> proto.ListRepositoriesRequest
+	39, // 37: proto.ProcessManager.ListAlerts:input_type -> proto.ListAlertsRequest
+	42, // 38: proto.ProcessManager.ListLoadBalancers:input_type -> proto.ListLoadBalancersRequest
+	47, // 39: proto.ProcessManager.SetBackendDraining:input_type -> proto.SetBackendDrainingRequest
+	48, // 40: proto.ProcessManager.ResetLoadBalancerStats:input_type -> proto.ResetLoadBalancerStatsRequest
+	1,  // 41: proto.ProcessManager.ListProcesses:output_type -> proto.ListProcessesResponse
+	3,  // 42: proto.ProcessManager.GetProcess:output_type -> proto.ProcessInfo
+	3,  // 43: proto.ProcessManager.StartProcess:output_type -> proto.ProcessInfo
+	49, // 44: proto.ProcessManager.StopProcess:output_type -> proto.Empty
+	3,  // 45: proto.ProcessManager.RestartProcess:output_type -> proto.ProcessInfo
+	13, // 46: proto.ProcessManager.GetMetrics:output_type -> proto.Metrics
+	3,  // 47: proto.ProcessManager.ScaleProcess:output_type -> proto.ProcessInfo
+	19, // 48: proto.ProcessManager.QueryMetrics:output_type -> proto.QueryMetricsResponse
+	25, // 49: proto.ProcessManager.UpdateAllProcesses:output_type -> proto.UpdateResponse
+	25, // 50: proto.ProcessManager.UpdateProcess:output_type -> proto.UpdateResponse
+	28, // 51: proto.ProcessManager.GetProcessVersion:output_type -> proto.VersionInfo
+	31, // 52: proto.ProcessManager.ListAvailableUpdates:output_type -> proto.ListUpdatesResponse
+	34, // 53: proto.ProcessManager.RollbackProcess:output_type -> proto.RollbackResponse
+	36, // 54: proto.ProcessManager.WatchUpdate:output_type -> proto.UpdateStatus
+	17, // 55: proto.ProcessManager.StreamMetrics:output_type -> proto.MetricsSnapshot
+	38, // 56: proto.ProcessManager.ListRepositories:output_type -> proto.ListRepositoriesResponse
+	40, // 57: proto.ProcessManager.ListAlerts:output_type -> proto.ListAlertsResponse
+	43, // 58: proto.ProcessManager.ListLoadBalancers:output_type -> proto.ListLoadBalancersResponse
+	46, // 59: proto.ProcessManager.SetBackendDraining:output_type -> proto.LoadBalancerBackend
+	49, // 60: proto.ProcessManager.ResetLoadBalancerStats:output_type -> proto.Empty
+	41, // [41:61] is the sub-list for method output_type
+	21, // [21:41] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_src_internal_proto_process_manager_proto_init() }
@@ -2440,7 +3802,7 @@ func file_src_internal_proto_process_manager_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_src_internal_proto_process_manager_proto_rawDesc), len(file_src_internal_proto_process_manager_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   34,
+			NumMessages:   53,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
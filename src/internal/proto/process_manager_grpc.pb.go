@@ -19,20 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ProcessManager_ListProcesses_FullMethodName        = "/proto.ProcessManager/ListProcesses"
-	ProcessManager_GetProcess_FullMethodName           = "/proto.ProcessManager/GetProcess"
-	ProcessManager_StartProcess_FullMethodName         = "/proto.ProcessManager/StartProcess"
-	ProcessManager_StopProcess_FullMethodName          = "/proto.ProcessManager/StopProcess"
-	ProcessManager_RestartProcess_FullMethodName       = "/proto.ProcessManager/RestartProcess"
-	ProcessManager_GetMetrics_FullMethodName           = "/proto.ProcessManager/GetMetrics"
-	ProcessManager_ScaleProcess_FullMethodName         = "/proto.ProcessManager/ScaleProcess"
-	ProcessManager_UpdateAllProcesses_FullMethodName   = "/proto.ProcessManager/UpdateAllProcesses"
-	ProcessManager_UpdateProcess_FullMethodName        = "/proto.ProcessManager/UpdateProcess"
-	ProcessManager_GetProcessVersion_FullMethodName    = "/proto.ProcessManager/GetProcessVersion"
-	ProcessManager_ListAvailableUpdates_FullMethodName = "/proto.ProcessManager/ListAvailableUpdates"
-	ProcessManager_RollbackProcess_FullMethodName      = "/proto.ProcessManager/RollbackProcess"
-	ProcessManager_WatchUpdate_FullMethodName          = "/proto.ProcessManager/WatchUpdate"
-	ProcessManager_ListRepositories_FullMethodName     = "/proto.ProcessManager/ListRepositories"
+	ProcessManager_ListProcesses_FullMethodName          = "/proto.ProcessManager/ListProcesses"
+	ProcessManager_GetProcess_FullMethodName             = "/proto.ProcessManager/GetProcess"
+	ProcessManager_StartProcess_FullMethodName           = "/proto.ProcessManager/StartProcess"
+	ProcessManager_StopProcess_FullMethodName            = "/proto.ProcessManager/StopProcess"
+	ProcessManager_RestartProcess_FullMethodName         = "/proto.ProcessManager/RestartProcess"
+	ProcessManager_GetMetrics_FullMethodName             = "/proto.ProcessManager/GetMetrics"
+	ProcessManager_ScaleProcess_FullMethodName           = "/proto.ProcessManager/ScaleProcess"
+	ProcessManager_QueryMetrics_FullMethodName           = "/proto.ProcessManager/QueryMetrics"
+	ProcessManager_UpdateAllProcesses_FullMethodName     = "/proto.ProcessManager/UpdateAllProcesses"
+	ProcessManager_UpdateProcess_FullMethodName          = "/proto.ProcessManager/UpdateProcess"
+	ProcessManager_GetProcessVersion_FullMethodName      = "/proto.ProcessManager/GetProcessVersion"
+	ProcessManager_ListAvailableUpdates_FullMethodName   = "/proto.ProcessManager/ListAvailableUpdates"
+	ProcessManager_RollbackProcess_FullMethodName        = "/proto.ProcessManager/RollbackProcess"
+	ProcessManager_WatchUpdate_FullMethodName            = "/proto.ProcessManager/WatchUpdate"
+	ProcessManager_StreamMetrics_FullMethodName          = "/proto.ProcessManager/StreamMetrics"
+	ProcessManager_ListRepositories_FullMethodName       = "/proto.ProcessManager/ListRepositories"
+	ProcessManager_ListAlerts_FullMethodName             = "/proto.ProcessManager/ListAlerts"
+	ProcessManager_ListLoadBalancers_FullMethodName      = "/proto.ProcessManager/ListLoadBalancers"
+	ProcessManager_SetBackendDraining_FullMethodName     = "/proto.ProcessManager/SetBackendDraining"
+	ProcessManager_ResetLoadBalancerStats_FullMethodName = "/proto.ProcessManager/ResetLoadBalancerStats"
 )
 
 // ProcessManagerClient is the client API for ProcessManager service.
@@ -49,6 +55,7 @@ type ProcessManagerClient interface {
 	RestartProcess(ctx context.Context, in *RestartProcessRequest, opts ...grpc.CallOption) (*ProcessInfo, error)
 	GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*Metrics, error)
 	ScaleProcess(ctx context.Context, in *ScaleProcessRequest, opts ...grpc.CallOption) (*ProcessInfo, error)
+	QueryMetrics(ctx context.Context, in *QueryMetricsRequest, opts ...grpc.CallOption) (*QueryMetricsResponse, error)
 	// Update Management
 	UpdateAllProcesses(ctx context.Context, in *UpdateAllRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	UpdateProcess(ctx context.Context, in *UpdateProcessRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
@@ -57,8 +64,16 @@ type ProcessManagerClient interface {
 	RollbackProcess(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error)
 	// Streaming - Update Progress Monitoring
 	WatchUpdate(ctx context.Context, in *WatchUpdateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UpdateStatus], error)
+	// Streaming - Live Metrics from the shared background sampler
+	StreamMetrics(ctx context.Context, in *StreamMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MetricsSnapshot], error)
 	// Repository Management
 	ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error)
+	// Alerting
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	// Load Balancer Administration
+	ListLoadBalancers(ctx context.Context, in *ListLoadBalancersRequest, opts ...grpc.CallOption) (*ListLoadBalancersResponse, error)
+	SetBackendDraining(ctx context.Context, in *SetBackendDrainingRequest, opts ...grpc.CallOption) (*LoadBalancerBackend, error)
+	ResetLoadBalancerStats(ctx context.Context, in *ResetLoadBalancerStatsRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type processManagerClient struct {
@@ -139,6 +154,16 @@ func (c *processManagerClient) ScaleProcess(ctx context.Context, in *ScaleProces
 	return out, nil
 }
 
+func (c *processManagerClient) QueryMetrics(ctx context.Context, in *QueryMetricsRequest, opts ...grpc.CallOption) (*QueryMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryMetricsResponse)
+	err := c.cc.Invoke(ctx, ProcessManager_QueryMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *processManagerClient) UpdateAllProcesses(ctx context.Context, in *UpdateAllRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateResponse)
@@ -208,6 +233,25 @@ func (c *processManagerClient) WatchUpdate(ctx context.Context, in *WatchUpdateR
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ProcessManager_WatchUpdateClient = grpc.ServerStreamingClient[UpdateStatus]
 
+func (c *processManagerClient) StreamMetrics(ctx context.Context, in *StreamMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MetricsSnapshot], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProcessManager_ServiceDesc.Streams[1], ProcessManager_StreamMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamMetricsRequest, MetricsSnapshot]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProcessManager_StreamMetricsClient = grpc.ServerStreamingClient[MetricsSnapshot]
+
 func (c *processManagerClient) ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListRepositoriesResponse)
@@ -218,6 +262,46 @@ func (c *processManagerClient) ListRepositories(ctx context.Context, in *ListRep
 	return out, nil
 }
 
+func (c *processManagerClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, ProcessManager_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processManagerClient) ListLoadBalancers(ctx context.Context, in *ListLoadBalancersRequest, opts ...grpc.CallOption) (*ListLoadBalancersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoadBalancersResponse)
+	err := c.cc.Invoke(ctx, ProcessManager_ListLoadBalancers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processManagerClient) SetBackendDraining(ctx context.Context, in *SetBackendDrainingRequest, opts ...grpc.CallOption) (*LoadBalancerBackend, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadBalancerBackend)
+	err := c.cc.Invoke(ctx, ProcessManager_SetBackendDraining_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processManagerClient) ResetLoadBalancerStats(ctx context.Context, in *ResetLoadBalancerStatsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ProcessManager_ResetLoadBalancerStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProcessManagerServer is the server API for ProcessManager service.
 // All implementations must embed UnimplementedProcessManagerServer
 // for forward compatibility.
@@ -232,6 +316,7 @@ type ProcessManagerServer interface {
 	RestartProcess(context.Context, *RestartProcessRequest) (*ProcessInfo, error)
 	GetMetrics(context.Context, *GetMetricsRequest) (*Metrics, error)
 	ScaleProcess(context.Context, *ScaleProcessRequest) (*ProcessInfo, error)
+	QueryMetrics(context.Context, *QueryMetricsRequest) (*QueryMetricsResponse, error)
 	// Update Management
 	UpdateAllProcesses(context.Context, *UpdateAllRequest) (*UpdateResponse, error)
 	UpdateProcess(context.Context, *UpdateProcessRequest) (*UpdateResponse, error)
@@ -240,8 +325,16 @@ type ProcessManagerServer interface {
 	RollbackProcess(context.Context, *RollbackRequest) (*RollbackResponse, error)
 	// Streaming - Update Progress Monitoring
 	WatchUpdate(*WatchUpdateRequest, grpc.ServerStreamingServer[UpdateStatus]) error
+	// Streaming - Live Metrics from the shared background sampler
+	StreamMetrics(*StreamMetricsRequest, grpc.ServerStreamingServer[MetricsSnapshot]) error
 	// Repository Management
 	ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error)
+	// Alerting
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	// Load Balancer Administration
+	ListLoadBalancers(context.Context, *ListLoadBalancersRequest) (*ListLoadBalancersResponse, error)
+	SetBackendDraining(context.Context, *SetBackendDrainingRequest) (*LoadBalancerBackend, error)
+	ResetLoadBalancerStats(context.Context, *ResetLoadBalancerStatsRequest) (*Empty, error)
 	mustEmbedUnimplementedProcessManagerServer()
 }
 
@@ -273,6 +366,9 @@ func (UnimplementedProcessManagerServer) GetMetrics(context.Context, *GetMetrics
 func (UnimplementedProcessManagerServer) ScaleProcess(context.Context, *ScaleProcessRequest) (*ProcessInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ScaleProcess not implemented")
 }
+func (UnimplementedProcessManagerServer) QueryMetrics(context.Context, *QueryMetricsRequest) (*QueryMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryMetrics not implemented")
+}
 func (UnimplementedProcessManagerServer) UpdateAllProcesses(context.Context, *UpdateAllRequest) (*UpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateAllProcesses not implemented")
 }
@@ -291,9 +387,24 @@ func (UnimplementedProcessManagerServer) RollbackProcess(context.Context, *Rollb
 func (UnimplementedProcessManagerServer) WatchUpdate(*WatchUpdateRequest, grpc.ServerStreamingServer[UpdateStatus]) error {
 	return status.Errorf(codes.Unimplemented, "method WatchUpdate not implemented")
 }
+func (UnimplementedProcessManagerServer) StreamMetrics(*StreamMetricsRequest, grpc.ServerStreamingServer[MetricsSnapshot]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMetrics not implemented")
+}
 func (UnimplementedProcessManagerServer) ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListRepositories not implemented")
 }
+func (UnimplementedProcessManagerServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedProcessManagerServer) ListLoadBalancers(context.Context, *ListLoadBalancersRequest) (*ListLoadBalancersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoadBalancers not implemented")
+}
+func (UnimplementedProcessManagerServer) SetBackendDraining(context.Context, *SetBackendDrainingRequest) (*LoadBalancerBackend, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBackendDraining not implemented")
+}
+func (UnimplementedProcessManagerServer) ResetLoadBalancerStats(context.Context, *ResetLoadBalancerStatsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetLoadBalancerStats not implemented")
+}
 func (UnimplementedProcessManagerServer) mustEmbedUnimplementedProcessManagerServer() {}
 func (UnimplementedProcessManagerServer) testEmbeddedByValue()                        {}
 
@@ -441,6 +552,24 @@ func _ProcessManager_ScaleProcess_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProcessManager_QueryMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).QueryMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessManager_QueryMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).QueryMetrics(ctx, req.(*QueryMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProcessManager_UpdateAllProcesses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateAllRequest)
 	if err := dec(in); err != nil {
@@ -542,6 +671,17 @@ func _ProcessManager_WatchUpdate_Handler(srv interface{}, stream grpc.ServerStre
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ProcessManager_WatchUpdateServer = grpc.ServerStreamingServer[UpdateStatus]
 
+func _ProcessManager_StreamMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProcessManagerServer).StreamMetrics(m, &grpc.GenericServerStream[StreamMetricsRequest, MetricsSnapshot]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProcessManager_StreamMetricsServer = grpc.ServerStreamingServer[MetricsSnapshot]
+
 func _ProcessManager_ListRepositories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListRepositoriesRequest)
 	if err := dec(in); err != nil {
@@ -560,6 +700,78 @@ func _ProcessManager_ListRepositories_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProcessManager_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessManager_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessManager_ListLoadBalancers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoadBalancersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).ListLoadBalancers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessManager_ListLoadBalancers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).ListLoadBalancers(ctx, req.(*ListLoadBalancersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessManager_SetBackendDraining_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBackendDrainingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).SetBackendDraining(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessManager_SetBackendDraining_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).SetBackendDraining(ctx, req.(*SetBackendDrainingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessManager_ResetLoadBalancerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetLoadBalancerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessManagerServer).ResetLoadBalancerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessManager_ResetLoadBalancerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessManagerServer).ResetLoadBalancerStats(ctx, req.(*ResetLoadBalancerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProcessManager_ServiceDesc is the grpc.ServiceDesc for ProcessManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -595,6 +807,10 @@ var ProcessManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ScaleProcess",
 			Handler:    _ProcessManager_ScaleProcess_Handler,
 		},
+		{
+			MethodName: "QueryMetrics",
+			Handler:    _ProcessManager_QueryMetrics_Handler,
+		},
 		{
 			MethodName: "UpdateAllProcesses",
 			Handler:    _ProcessManager_UpdateAllProcesses_Handler,
@@ -619,6 +835,22 @@ var ProcessManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListRepositories",
 			Handler:    _ProcessManager_ListRepositories_Handler,
 		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _ProcessManager_ListAlerts_Handler,
+		},
+		{
+			MethodName: "ListLoadBalancers",
+			Handler:    _ProcessManager_ListLoadBalancers_Handler,
+		},
+		{
+			MethodName: "SetBackendDraining",
+			Handler:    _ProcessManager_SetBackendDraining_Handler,
+		},
+		{
+			MethodName: "ResetLoadBalancerStats",
+			Handler:    _ProcessManager_ResetLoadBalancerStats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -626,6 +858,11 @@ var ProcessManager_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ProcessManager_WatchUpdate_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamMetrics",
+			Handler:       _ProcessManager_StreamMetrics_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "src/internal/proto/process_manager.proto",
 }
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Resolution tiers kept for every series. Raw samples are downsampled into
+// 1-minute and 1-hour averages as they arrive.
+const (
+	minuteResolution = time.Minute
+	hourResolution   = time.Hour
+
+	rawRetention    = time.Hour
+	minuteRetention = 24 * time.Hour
+	hourRetention   = 30 * 24 * time.Hour
+)
+
+// AggregateInstance is the instance ID under which per-process totals are stored
+const AggregateInstance = "total"
+
+// Point is a single timestamped value
+type Point struct {
+	Timestamp int64   `json:"t"` // Unix seconds
+	Value     float64 `json:"v"`
+}
+
+// SeriesKey identifies a series
+type SeriesKey struct {
+	Process  string `json:"process"`
+	Instance string `json:"instance"`
+	Metric   string `json:"metric"`
+}
+
+// Series is a query result
+type Series struct {
+	SeriesKey
+	Points []Point `json:"points"`
+}
+
+// Query selects series and a time range. Empty Instance or Metrics match all.
+type Query struct {
+	Process  string
+	Instance string
+	Metrics  []string
+	Start    time.Time
+	End      time.Time
+	Step     time.Duration // 0 = native resolution of the selected tier
+}
+
+// ring is a fixed-capacity circular buffer of points
+type ring struct {
+	Points []Point `json:"points"`
+	Next   int     `json:"next"`
+	Full   bool    `json:"full"`
+}
+
+func newRing(capacity int) *ring {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ring{Points: make([]Point, capacity)}
+}
+
+func (r *ring) add(p Point) {
+	r.Points[r.Next] = p
+	r.Next = (r.Next + 1) % len(r.Points)
+	if r.Next == 0 {
+		r.Full = true
+	}
+}
+
+// between returns points with start <= t <= end in chronological order
+func (r *ring) between(start, end int64) []Point {
+	var out []Point
+	n := r.Next
+	if r.Full {
+		n = len(r.Points)
+	}
+	for i := 0; i < n; i++ {
+		idx := i
+		if r.Full {
+			idx = (r.Next + i) % len(r.Points)
+		}
+		p := r.Points[idx]
+		if p.Timestamp >= start && p.Timestamp <= end {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// rollup accumulates raw samples into a coarser tier
+type rollup struct {
+	Ring        *ring   `json:"ring"`
+	BucketStart int64   `json:"bucket_start"`
+	Sum         float64 `json:"sum"`
+	Count       int     `json:"count"`
+}
+
+func (r *rollup) add(p Point, resolution time.Duration) {
+	bucket := p.Timestamp - p.Timestamp%int64(resolution.Seconds())
+	if r.Count > 0 && bucket != r.BucketStart {
+		r.flush()
+	}
+	r.BucketStart = bucket
+	r.Sum += p.Value
+	r.Count++
+}
+
+func (r *rollup) flush() {
+	if r.Count == 0 {
+		return
+	}
+	r.Ring.add(Point{Timestamp: r.BucketStart, Value: r.Sum / float64(r.Count)})
+	r.Sum = 0
+	r.Count = 0
+}
+
+// points returns the stored points plus the partially filled current bucket
+func (r *rollup) points(start, end int64) []Point {
+	out := r.Ring.between(start, end)
+	if r.Count > 0 && r.BucketStart >= start && r.BucketStart <= end {
+		out = append(out, Point{Timestamp: r.BucketStart, Value: r.Sum / float64(r.Count)})
+	}
+	return out
+}
+
+// seriesData holds all tiers of a series
+type seriesData struct {
+	Raw    *ring   `json:"raw"`
+	Minute *rollup `json:"minute"`
+	Hour   *rollup `json:"hour"`
+}
+
+// newest returns the start of the minute of the series' latest point
+func (d *seriesData) newest() int64 {
+	return max(d.Minute.BucketStart, d.Hour.BucketStart)
+}
+
+// TimeSeriesStore is an in-memory metrics history with optional on-disk persistence
+type TimeSeriesStore struct {
+	rawInterval time.Duration
+	path        string
+	series      map[SeriesKey]*seriesData
+	mu          sync.RWMutex
+}
+
+// NewTimeSeriesStore creates a store for samples taken every rawInterval.
+// If dataDir is not empty, history is persisted to dataDir/metrics_history.json.
+func NewTimeSeriesStore(rawInterval time.Duration, dataDir string) *TimeSeriesStore {
+	s := &TimeSeriesStore{
+		rawInterval: rawInterval,
+		series:      make(map[SeriesKey]*seriesData),
+	}
+	if dataDir != "" {
+		s.path = filepath.Join(dataDir, "metrics_history.json")
+	}
+	return s
+}
+
+// newSeries creates the tiers for a new series
+func (s *TimeSeriesStore) newSeries() *seriesData {
+	return &seriesData{
+		Raw:    newRing(int(rawRetention / s.rawInterval)),
+		Minute: &rollup{Ring: newRing(int(minuteRetention / minuteResolution))},
+		Hour:   &rollup{Ring: newRing(int(hourRetention / hourResolution))},
+	}
+}
+
+// Append adds a sample to a series
+func (s *TimeSeriesStore) Append(key SeriesKey, t time.Time, value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.series[key]
+	if !ok {
+		data = s.newSeries()
+		s.series[key] = data
+	}
+
+	p := Point{Timestamp: t.Unix(), Value: value}
+	data.Raw.add(p)
+	data.Minute.add(p, minuteResolution)
+	data.Hour.add(p, hourResolution)
+}
+
+// Prune drops series whose latest point is older than the hour tier's
+// retention, such as those of instances that were replaced or removed
+func (s *TimeSeriesStore) Prune(now time.Time) {
+	cutoff := now.Add(-hourRetention).Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, data := range s.series {
+		if data.newest() < cutoff {
+			delete(s.series, key)
+		}
+	}
+}
+
+// Query returns matching series within the time range, resampled to the step
+func (s *TimeSeriesStore) Query(q Query) []Series {
+	if q.End.IsZero() {
+		q.End = time.Now()
+	}
+	if q.Start.IsZero() {
+		q.Start = q.End.Add(-rawRetention)
+	}
+
+	wanted := make(map[string]bool, len(q.Metrics))
+	for _, m := range q.Metrics {
+		wanted[m] = true
+	}
+
+	// Pick the finest tier that covers the range and is not finer than the step.
+	// A minute of slack keeps the default one-hour query on raw samples.
+	age := time.Since(q.Start) - time.Minute
+	tier := "raw"
+	switch {
+	case q.Step >= hourResolution || age > minuteRetention:
+		tier = "hour"
+	case q.Step >= minuteResolution || age > rawRetention:
+		tier = "minute"
+	}
+
+	start, end := q.Start.Unix(), q.End.Unix()
+
+	s.mu.RLock()
+	var result []Series
+	for key, data := range s.series {
+		if key.Process != q.Process && q.Process != "" {
+			continue
+		}
+		if q.Instance != "" && key.Instance != q.Instance {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[key.Metric] {
+			continue
+		}
+
+		var points []Point
+		switch tier {
+		case "hour":
+			points = data.Hour.points(start, end)
+		case "minute":
+			points = data.Minute.points(start, end)
+		default:
+			points = data.Raw.between(start, end)
+		}
+		result = append(result, Series{SeriesKey: key, Points: resample(points, q.Step)})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].SeriesKey, result[j].SeriesKey
+		if a.Process != b.Process {
+			return a.Process < b.Process
+		}
+		if a.Instance != b.Instance {
+			return a.Instance < b.Instance
+		}
+		return a.Metric < b.Metric
+	})
+	return result
+}
+
+// resample averages points into step-aligned buckets
+func resample(points []Point, step time.Duration) []Point {
+	stepSec := int64(step.Seconds())
+	if stepSec <= 0 || len(points) == 0 {
+		return points
+	}
+
+	var out []Point
+	var bucket int64
+	var sum float64
+	var count int
+	for _, p := range points {
+		b := p.Timestamp - p.Timestamp%stepSec
+		if count > 0 && b != bucket {
+			out = append(out, Point{Timestamp: bucket, Value: sum / float64(count)})
+			sum, count = 0, 0
+		}
+		bucket = b
+		sum += p.Value
+		count++
+	}
+	return append(out, Point{Timestamp: bucket, Value: sum / float64(count)})
+}
+
+// persistedSeries is the on-disk form of a series
+type persistedSeries struct {
+	Key  SeriesKey   `json:"key"`
+	Data *seriesData `json:"data"`
+}
+
+// persistedStore is the on-disk form of the store
+type persistedStore struct {
+	RawInterval time.Duration     `json:"raw_interval"`
+	Series      []persistedSeries `json:"series"`
+}
+
+// Save writes the history to disk (no-op if persistence is disabled)
+func (s *TimeSeriesStore) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	out := persistedStore{RawInterval: s.rawInterval}
+	for key, data := range s.series {
+		out.Series = append(out.Series, persistedSeries{Key: key, Data: data})
+	}
+	data, err := json.Marshal(out)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics history: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metrics history: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace metrics history: %w", err)
+	}
+	return nil
+}
+
+// Load reads the history from disk. A missing file is not an error.
+func (s *TimeSeriesStore) Load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metrics history: %w", err)
+	}
+
+	var in persistedStore
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("failed to parse metrics history: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-hourRetention).Unix()
+	for _, ps := range in.Series {
+		if ps.Data == nil || ps.Data.Raw == nil || ps.Data.Minute == nil || ps.Data.Hour == nil {
+			continue
+		}
+		if ps.Data.newest() < cutoff {
+			continue
+		}
+		// Raw samples at a different interval don't fit the ring; keep only the rollups
+		if in.RawInterval != s.rawInterval {
+			ps.Data.Raw = s.newSeries().Raw
+		}
+		s.series[ps.Key] = ps.Data
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPruneDropsStaleSeries(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+
+	s := NewTimeSeriesStore(10*time.Second, dir)
+	stale := SeriesKey{Process: "api", Instance: "api-1", Metric: "cpu_percent"}
+	live := SeriesKey{Process: "api", Instance: "api-2", Metric: "cpu_percent"}
+	s.Append(stale, now.Add(-hourRetention-2*time.Hour), 1)
+	s.Append(live, now.Add(-hourRetention+2*time.Hour), 2)
+	s.Append(live, now, 3)
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	s.Prune(now)
+	if got := s.Query(Query{Instance: stale.Instance}); len(got) != 0 {
+		t.Errorf("stale series kept after Prune: %+v", got)
+	}
+	if got := s.Query(Query{Instance: live.Instance}); len(got) != 1 {
+		t.Errorf("live series: got %d series, want 1", len(got))
+	}
+
+	// The saved file still has the stale series; Load drops it
+	loaded := NewTimeSeriesStore(10*time.Second, dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if n := len(loaded.series); n != 1 {
+		t.Fatalf("loaded %d series, want 1", n)
+	}
+	if _, ok := loaded.series[live]; !ok {
+		t.Errorf("live series not loaded")
+	}
+}
@@ -0,0 +1,176 @@
+package update
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// blueGreenDeploy is a blue/green update waiting out its bake time
+type blueGreenDeploy struct {
+	old, new []*models.ProcessInstance
+
+	// Binary deployed before the update, restored on switch-back
+	prevBinary  string
+	prevVersion string
+
+	switchBack chan struct{} // closed by SwitchBack
+}
+
+// deployBlueGreen starts a full set of new instances without traffic, switches
+// traffic to them once all are ready and keeps the old instances running idle
+// for the bake time. Reports whether the update can complete; if the new
+// instances fail or traffic is switched back, status says why.
+func (m *Manager) deployBlueGreen(processName string, status *models.UpdateStatus, binaryPath, version string, cfg models.UpdateConfig) bool {
+	var old []*models.ProcessInstance
+	instances, _ := m.processManager.GetProcessStatus(processName)
+	for _, inst := range instances {
+		if inst.GetStatus() == models.StatusRunning {
+			old = append(old, inst)
+		}
+	}
+	count := max(1, len(old))
+
+	status.Stage = "starting_new"
+	status.DesiredInstances = count
+	status.UpdatedInstances = 0
+	status.OldInstances = len(old)
+	status.Message = fmt.Sprintf("Starting %d instance(s) of version %s without traffic", count, version)
+	status.Progress = 75
+	m.setUpdateStatus(processName, status)
+	log.Printf("[Update] Blue/green update of %s: starting %d new instance(s) alongside %d old", processName, count, len(old))
+
+	newInstances, err := m.processManager.StartReadyInstances(processName, count, process.StartOptions{
+		BinaryPath: binaryPath,
+		Version:    version,
+		Standby:    true,
+	})
+	if err != nil {
+		// Traffic never left the old instances
+		log.Printf("[Update] ERROR: New instances of %s failed to become ready: %v", processName, err)
+		status.Stage = "failed"
+		status.Error = fmt.Sprintf("New instances failed to become ready: %v", err)
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		return false
+	}
+
+	d := &blueGreenDeploy{
+		old:        old,
+		new:        newInstances,
+		switchBack: make(chan struct{}),
+	}
+	d.prevBinary, d.prevVersion = m.processManager.GetDeployedBinary(processName)
+
+	if err := m.processManager.SwitchTraffic(processName, newInstances, old); err != nil {
+		log.Printf("[Update] ERROR: Failed to switch traffic of %s: %v", processName, err)
+		m.processManager.StopInstances(processName, newInstances, cfg.StopTimeout)
+		status.Stage = "failed"
+		status.Error = fmt.Sprintf("Failed to switch traffic: %v", err)
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		return false
+	}
+
+	// Restarts and scale-ups during the bake time run the new binary
+	if err := m.processManager.SetDeployedBinary(processName, binaryPath, version); err != nil {
+		log.Printf("[Update] Warning: failed to set deployed binary for %s: %v", processName, err)
+	}
+
+	m.mu.Lock()
+	m.blueGreen[processName] = d
+	m.mu.Unlock()
+
+	until := time.Now().Add(cfg.BakeTime)
+	status.Stage = "baking"
+	status.UpdatedInstances = len(newInstances)
+	status.SwitchBackUntil = &until
+	status.Message = fmt.Sprintf("Traffic switched to version %s; old instances kept for switch-back until %s",
+		version, until.Format(time.RFC3339))
+	status.Progress = 85
+	m.setUpdateStatus(processName, status)
+	log.Printf("[Update] Traffic of %s switched to version %s; baking for %v", processName, version, cfg.BakeTime)
+
+	timer := time.NewTimer(cfg.BakeTime)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-d.switchBack:
+	}
+
+	// A switch-back racing the end of the bake time wins if it got in first
+	m.mu.Lock()
+	baked := m.blueGreen[processName] == d
+	if baked {
+		delete(m.blueGreen, processName)
+	}
+	m.mu.Unlock()
+	status.SwitchBackUntil = nil
+
+	if !baked {
+		// SwitchBack has already routed traffic back to the old instances
+		status.Stage = "stopping_new"
+		status.Message = fmt.Sprintf("Switched back from version %s; stopping new instances", version)
+		m.setUpdateStatus(processName, status)
+
+		m.processManager.StopInstances(processName, newInstances, cfg.StopTimeout)
+
+		status.Stage = "switched_back"
+		status.Message = fmt.Sprintf("Switched back from version %s; new instances stopped", version)
+		status.UpdatedInstances = 0
+		status.Progress = 100
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		log.Printf("[Update] Blue/green update of %s to %s switched back", processName, version)
+		return false
+	}
+
+	status.Stage = "stopping_old"
+	status.Message = fmt.Sprintf("Bake time over; stopping %d old instance(s)", len(old))
+	status.Progress = 90
+	m.setUpdateStatus(processName, status)
+	log.Printf("[Update] Bake time of %s over; stopping old instances", processName)
+
+	m.processManager.StopInstances(processName, old, cfg.StopTimeout)
+	status.OldInstances = 0
+	m.setUpdateStatus(processName, status)
+	return true
+}
+
+// SwitchBack routes traffic of a process back to the old instances of a
+// blue/green update during its bake time. The new instances are then stopped
+// and the update ends without changing the current version.
+func (m *Manager) SwitchBack(processName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d := m.blueGreen[processName]
+	if d == nil {
+		return fmt.Errorf("no blue/green update of %s is waiting for switch-back", processName)
+	}
+
+	var running []*models.ProcessInstance
+	for _, inst := range d.old {
+		if inst.GetStatus() == models.StatusRunning {
+			running = append(running, inst)
+		}
+	}
+	if len(running) == 0 {
+		return fmt.Errorf("old instances of %s are no longer running", processName)
+	}
+
+	if err := m.processManager.SwitchTraffic(processName, running, d.new); err != nil {
+		return err
+	}
+	if err := m.processManager.SetDeployedBinary(processName, d.prevBinary, d.prevVersion); err != nil {
+		log.Printf("[Update] Warning: failed to restore deployed binary for %s: %v", processName, err)
+	}
+
+	delete(m.blueGreen, processName)
+	close(d.switchBack)
+	log.Printf("[Update] Traffic of %s switched back to %d old instance(s)", processName, len(running))
+	return nil
+}
@@ -0,0 +1,350 @@
+package update
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/loadbalancer"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+// canaryCheckInterval is how often a step checks for crashed canary instances
+// before its end
+const canaryCheckInterval = 5 * time.Second
+
+// Results of a canary analysis
+const (
+	canaryPromoted   = "promoted"
+	canaryRolledBack = "rolled_back"
+)
+
+// canaryDeploy is a canary update being analyzed
+type canaryDeploy struct {
+	instances []*models.ProcessInstance
+	rollback  chan struct{} // closed by endCanary
+}
+
+// deployCanary starts canary instances of the new version, sends them the
+// configured shares of traffic step by step and compares them with the old
+// instances after each step. If every step passes, the canary is promoted and
+// the old instances are replaced with a rolling update; otherwise the update
+// is rolled back through RollbackProcess. Reports whether the update can
+// complete; the analysis is kept in the update history either way.
+func (m *Manager) deployCanary(processName string, status *models.UpdateStatus, binaryPath, version string, force bool, cfg models.UpdateConfig) bool {
+	var baseline []*models.ProcessInstance
+	instances, _ := m.processManager.GetProcessStatus(processName)
+	for _, inst := range instances {
+		if inst.GetStatus() != models.StatusRunning {
+			continue
+		}
+		if inst.Version == version && !force {
+			// A promotion was interrupted; finish it without another analysis
+			log.Printf("[Update] %s already runs instances of %s; resuming the promotion", processName, version)
+			return m.deployRolling(processName, status, binaryPath, version, force, cfg)
+		}
+		baseline = append(baseline, inst)
+	}
+	if len(baseline) == 0 {
+		log.Printf("[Update] %s has no running instances to compare a canary with; updating without analysis", processName)
+		return m.deployRolling(processName, status, binaryPath, version, force, cfg)
+	}
+
+	c := cfg.Canary
+	analysis := &models.CanaryAnalysis{
+		Version:  version,
+		Baseline: baseline[0].Version,
+		Started:  time.Now(),
+		Steps:    []models.CanaryStep{},
+	}
+	status.Canary = analysis
+	status.Stage = "starting_canary"
+	status.DesiredInstances = len(baseline)
+	status.UpdatedInstances = 0
+	status.OldInstances = len(baseline)
+	status.Message = fmt.Sprintf("Starting %d canary instance(s) of version %s", c.Instances, version)
+	status.Progress = 75
+	m.setUpdateStatus(processName, status)
+	log.Printf("[Update] Canary update of %s: starting %d instance(s) of %s next to %d baseline instance(s)",
+		processName, c.Instances, version, len(baseline))
+
+	canary, err := m.processManager.StartReadyInstances(processName, c.Instances, process.StartOptions{
+		BinaryPath: binaryPath,
+		Version:    version,
+		Standby:    true,
+	})
+	if err != nil {
+		reason := fmt.Sprintf("canary instances failed to become ready: %v", err)
+		log.Printf("[Update] ERROR: Canary update of %s: %s", processName, reason)
+		m.finishCanary(processName, analysis, canaryRolledBack, reason)
+		status.Stage = "rolled_back"
+		status.Error = reason
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		return false
+	}
+
+	d := &canaryDeploy{instances: canary, rollback: make(chan struct{})}
+	m.mu.Lock()
+	m.canaries[processName] = d
+	m.mu.Unlock()
+
+	// Set the first share before the canary leaves standby
+	m.processManager.SetCanary(processName, canary, c.Steps[0])
+	m.processManager.SwitchTraffic(processName, canary, nil)
+	status.UpdatedInstances = len(canary)
+
+	reason := ""
+	rolledBack := false
+	for i, weight := range c.Steps {
+		m.processManager.SetCanary(processName, canary, weight)
+		status.Stage = "canary_analysis"
+		status.CanaryWeight = weight
+		status.Message = fmt.Sprintf("Step %d/%d: %d%% of traffic to version %s", i+1, len(c.Steps), weight, version)
+		status.Progress = 75 + 15*float64(i)/float64(len(c.Steps))
+		m.setUpdateStatus(processName, status)
+		log.Printf("[Update] Canary of %s step %d/%d: %d%% of traffic", processName, i+1, len(c.Steps), weight)
+
+		step, interrupted := m.analyzeStep(processName, canary, baseline, weight, c, d.rollback)
+		analysis.Steps = append(analysis.Steps, step)
+		m.setUpdateStatus(processName, status)
+
+		if interrupted {
+			rolledBack, reason = true, "rollback requested"
+			break
+		}
+		if !step.Passed {
+			reason = fmt.Sprintf("step %d (%d%%): %s", i+1, weight, step.Reason)
+			break
+		}
+		log.Printf("[Update] Canary of %s passed step %d/%d", processName, i+1, len(c.Steps))
+	}
+
+	// A rollback requested after the last step still wins over the promotion
+	if reason == "" {
+		m.mu.Lock()
+		if m.canaries[processName] == d {
+			delete(m.canaries, processName)
+		} else {
+			rolledBack, reason = true, "rollback requested"
+		}
+		m.mu.Unlock()
+	}
+
+	if reason != "" {
+		log.Printf("[Update] Canary of %s failed: %s", processName, reason)
+		if !rolledBack {
+			// Ends the canary: its instances stop receiving traffic at once
+			if err := m.RollbackProcess(processName, ""); err != nil {
+				log.Printf("[Update] Warning: failed to roll back canary of %s: %v", processName, err)
+			}
+		}
+
+		status.Stage = "stopping_canary"
+		status.CanaryWeight = 0
+		status.Message = fmt.Sprintf("Canary of version %s rolled back; stopping canary instances", version)
+		m.setUpdateStatus(processName, status)
+		m.processManager.StopInstances(processName, canary, cfg.StopTimeout)
+
+		m.finishCanary(processName, analysis, canaryRolledBack, reason)
+		status.Stage = "rolled_back"
+		status.Error = fmt.Sprintf("Canary rolled back: %s", reason)
+		status.UpdatedInstances = 0
+		status.Progress = 100
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		return false
+	}
+
+	// Promote: the canary instances take their full share and replace as
+	// many old instances, the rest are replaced batch by batch. Restarts and
+	// scale-ups from now on run the new binary.
+	m.processManager.SetCanary(processName, nil, 0)
+	if err := m.processManager.SetDeployedBinary(processName, binaryPath, version); err != nil {
+		log.Printf("[Update] Warning: failed to set deployed binary for %s: %v", processName, err)
+	}
+	m.finishCanary(processName, analysis, canaryPromoted, "")
+	status.Stage = "promoting"
+	status.CanaryWeight = 0
+	status.Message = fmt.Sprintf("Canary passed; promoting version %s", version)
+	status.Progress = 90
+	m.setUpdateStatus(processName, status)
+	log.Printf("[Update] Canary of %s passed all steps; promoting %s", processName, version)
+
+	n := min(len(canary), len(baseline))
+	m.processManager.StopInstances(processName, baseline[:n], cfg.StopTimeout)
+	var rest []*models.ProcessInstance
+	for _, inst := range baseline[n:] {
+		if inst.GetStatus() == models.StatusRunning {
+			rest = append(rest, inst)
+		}
+	}
+
+	if _, err := m.processManager.RollingReplace(processName, process.Rollout{
+		Old:     rest,
+		Updated: len(canary),
+		Start: process.StartOptions{
+			BinaryPath: binaryPath,
+			Version:    version,
+		},
+		Progress: m.rolloutProgress(processName, status),
+	}); err != nil {
+		// Updating to the same version again resumes without another analysis
+		log.Printf("[Update] ERROR: Promotion of %s paused: %v", processName, err)
+		status.Stage = "paused"
+		status.Paused = true
+		status.Error = fmt.Sprintf("Promotion paused: %v", err)
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		return false
+	}
+	return true
+}
+
+// analyzeStep lets a step run for its duration and compares the canary with
+// the baseline over it. The step ends early if the canary crashes too often
+// or a rollback is requested, which is reported.
+func (m *Manager) analyzeStep(processName string, canary, baseline []*models.ProcessInstance, weight int, c models.CanaryConfig, rollback <-chan struct{}) (models.CanaryStep, bool) {
+	step := models.CanaryStep{Weight: weight, Started: time.Now()}
+	before := m.instanceCalls(processName)
+
+	ticker := time.NewTicker(canaryCheckInterval)
+	defer ticker.Stop()
+	timer := time.NewTimer(c.StepDuration)
+	defer timer.Stop()
+
+	interrupted := false
+wait:
+	for {
+		select {
+		case <-rollback:
+			interrupted = true
+			break wait
+		case <-timer.C:
+			break wait
+		case <-ticker.C:
+			if crashes(canary)-crashes(baseline) > c.MaxCrashes {
+				break wait
+			}
+		}
+	}
+
+	after := m.instanceCalls(processName)
+	step.Duration = time.Since(step.Started).Seconds()
+	step.Canary = groupMetrics(canary, before, after)
+	step.Baseline = groupMetrics(baseline, before, after)
+	step.Passed, step.Reason = judgeStep(step, c)
+	return step, interrupted
+}
+
+// judgeStep decides whether the canary did well enough over a step
+func judgeStep(step models.CanaryStep, c models.CanaryConfig) (bool, string) {
+	canary, baseline := step.Canary, step.Baseline
+	switch {
+	case canary.Crashes-baseline.Crashes > c.MaxCrashes:
+		return false, fmt.Sprintf("canary crashed %d time(s), baseline %d", canary.Crashes, baseline.Crashes)
+	case canary.Instances == 0:
+		return false, "no canary instance is running"
+	case canary.Requests < uint64(c.MinRequests) && c.AllowLowTraffic:
+		return true, fmt.Sprintf("only %d canary call(s), error rate and latency not compared (min_requests %d)",
+			canary.Requests, c.MinRequests)
+	case canary.Requests < uint64(c.MinRequests):
+		return false, fmt.Sprintf("only %d canary call(s), fewer than min_requests %d", canary.Requests, c.MinRequests)
+	case canary.ErrorRate-baseline.ErrorRate > c.MaxErrorRateIncrease:
+		return false, fmt.Sprintf("canary error rate %.2f%% exceeds baseline %.2f%% by more than %.2f points",
+			canary.ErrorRate, baseline.ErrorRate, c.MaxErrorRateIncrease)
+	case baseline.LatencyAvgMs > 0 && canary.LatencyAvgMs > baseline.LatencyAvgMs*c.MaxLatencyRatio:
+		return false, fmt.Sprintf("canary latency %.1fms exceeds %.1f times baseline %.1fms",
+			canary.LatencyAvgMs, c.MaxLatencyRatio, baseline.LatencyAvgMs)
+	}
+	return true, ""
+}
+
+// groupMetrics sums the calls load balancers proxied to a group of instances
+// between two snapshots
+func groupMetrics(instances []*models.ProcessInstance, before, after map[string]loadbalancer.InstanceCalls) models.CanaryMetrics {
+	var g models.CanaryMetrics
+	var latency time.Duration
+	for _, inst := range instances {
+		switch inst.GetStatus() {
+		case models.StatusRunning:
+			g.Instances++
+		case models.StatusFailed:
+			g.Crashes++
+		}
+
+		b, a := before[inst.ID], after[inst.ID]
+		if a.Requests < b.Requests {
+			// The balancer's statistics were reset during the step
+			b = loadbalancer.InstanceCalls{}
+		}
+		g.Requests += a.Requests - b.Requests
+		g.Errors += a.Errors - b.Errors
+		latency += a.LatencySum - b.LatencySum
+	}
+	if g.Requests > 0 {
+		g.ErrorRate = 100 * float64(g.Errors) / float64(g.Requests)
+		g.LatencyAvgMs = float64(latency) / float64(time.Millisecond) / float64(g.Requests)
+	}
+	return g
+}
+
+// crashes counts the instances of a group that have crashed
+func crashes(instances []*models.ProcessInstance) int {
+	n := 0
+	for _, inst := range instances {
+		if inst.GetStatus() == models.StatusFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// instanceCalls snapshots the calls load balancers proxied to each instance of
+// a process (nil without load balancers)
+func (m *Manager) instanceCalls(processName string) map[string]loadbalancer.InstanceCalls {
+	if m.lbManager == nil {
+		return nil
+	}
+	return m.lbManager.InstanceCalls(processName)
+}
+
+// endCanary stops sending traffic to the canary instances of a process if a
+// canary analysis is running; its update then stops them. Reports whether
+// there was one.
+func (m *Manager) endCanary(processName string) bool {
+	m.mu.Lock()
+	d := m.canaries[processName]
+	delete(m.canaries, processName)
+	m.mu.Unlock()
+
+	if d == nil {
+		return false
+	}
+	m.processManager.SwitchTraffic(processName, nil, d.instances)
+	m.processManager.SetCanary(processName, nil, 0)
+	close(d.rollback)
+	return true
+}
+
+// finishCanary records the result of a canary analysis in the update history
+func (m *Manager) finishCanary(processName string, analysis *models.CanaryAnalysis, result, reason string) {
+	now := time.Now()
+	analysis.Finished = &now
+	analysis.Result = result
+	analysis.Reason = reason
+	if err := m.versionManager.RecordCanaryAnalysis(processName, *analysis); err != nil {
+		log.Printf("[Update] Warning: failed to record canary analysis of %s: %v", processName, err)
+	}
+}
+
+// CanaryAnalyses returns the recorded canary analyses of a process, newest
+// first
+func (m *Manager) CanaryAnalyses(processName string) []models.CanaryAnalysis {
+	info, err := m.versionManager.LoadVersionInfo(processName)
+	if err != nil {
+		return nil
+	}
+	return info.CanaryAnalyses
+}
@@ -0,0 +1,37 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
+)
+
+func TestJudgeStep(t *testing.T) {
+	c := models.CanaryConfig{MinRequests: 20, MaxErrorRateIncrease: 1, MaxLatencyRatio: 1.5}
+	lowTraffic := c
+	lowTraffic.AllowLowTraffic = true
+	baseline := models.CanaryMetrics{Instances: 2, Requests: 1000, ErrorRate: 0.5, LatencyAvgMs: 10}
+
+	tests := []struct {
+		name   string
+		canary models.CanaryMetrics
+		cfg    models.CanaryConfig
+		want   bool
+	}{
+		{"healthy", models.CanaryMetrics{Instances: 1, Requests: 100, ErrorRate: 1, LatencyAvgMs: 12}, c, true},
+		{"no traffic", models.CanaryMetrics{Instances: 1}, c, false},
+		{"too few calls", models.CanaryMetrics{Instances: 1, Requests: 19}, c, false},
+		{"too few calls allowed", models.CanaryMetrics{Instances: 1, Requests: 19}, lowTraffic, true},
+		{"too few calls but crashed", models.CanaryMetrics{Crashes: 1}, lowTraffic, false},
+		{"error rate", models.CanaryMetrics{Instances: 1, Requests: 100, ErrorRate: 2}, c, false},
+		{"latency", models.CanaryMetrics{Instances: 1, Requests: 100, LatencyAvgMs: 16}, c, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, reason := judgeStep(models.CanaryStep{Canary: tt.canary, Baseline: baseline}, tt.cfg)
+			if passed != tt.want {
+				t.Errorf("judgeStep = %v (%s), want %v", passed, reason, tt.want)
+			}
+		})
+	}
+}
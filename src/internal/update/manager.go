@@ -6,8 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
-	"time"
 
+	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/loadbalancer"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/process"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/internal/version"
 	"github.com/yhonda-ohishi-pub-dev/gowinproc/src/pkg/models"
@@ -23,6 +23,13 @@ type Manager struct {
 	// Repository-level locks to prevent concurrent downloads of the same binary
 	repoLocks      map[string]*sync.Mutex
 	repoLocksMu    sync.Mutex
+	// Blue/green updates waiting out their bake time, guarded by mu
+	blueGreen      map[string]*blueGreenDeploy
+	// Canary updates being analyzed, guarded by mu
+	canaries       map[string]*canaryDeploy
+
+	// Load balancers whose call statistics canary analysis compares (optional)
+	lbManager *loadbalancer.Manager
 }
 
 // NewManager creates a new update manager
@@ -37,9 +44,17 @@ func NewManager(processMgr *process.Manager, versionMgr *version.Manager, binari
 		binariesDir:    binariesDir,
 		updates:        make(map[string]*models.UpdateStatus),
 		repoLocks:      make(map[string]*sync.Mutex),
+		blueGreen:      make(map[string]*blueGreenDeploy),
+		canaries:       make(map[string]*canaryDeploy),
 	}, nil
 }
 
+// SetLoadBalancerManager sets the load balancers whose call statistics canary
+// analysis compares
+func (m *Manager) SetLoadBalancerManager(mgr *loadbalancer.Manager) {
+	m.lbManager = mgr
+}
+
 // UpdateProcess updates a process to a specific version (or latest if version is empty)
 func (m *Manager) UpdateProcess(processName, targetVersion string, force bool) error {
 	// Check if update is already in progress
@@ -177,74 +192,47 @@ func (m *Manager) performUpdate(processName, targetVersion string, force bool) {
 		log.Printf("[Update] Download completed for %s, binary path: %s", processName, binaryPath)
 	}
 
-	// Stage 3: Start new instance (Hot Deploy)
-	status.Stage = "starting_new"
-	status.Message = "Starting new instance"
-	status.Progress = 75
-	m.setUpdateStatus(processName, status)
-	log.Printf("[Update] Starting new instance for %s (allowing max_instances+1 temporarily)", processName)
-
-	// TODO: Update process config with new binary path
-	// This requires modifying the process manager to support binary path updates
-
-	// Start new instance with allowExceedMax=true for zero-downtime hot restart
-	newInstance, err := m.processManager.StartProcessWithOptions(processName, true)
+	// Stage 3: Replace running instances with the new version (Hot Deploy)
+	procConfig, err := m.processManager.GetProcessConfig(processName)
 	if err != nil {
-		log.Printf("[Update] ERROR: Failed to start new instance for %s: %v", processName, err)
+		log.Printf("[Update] ERROR: Failed to get config for %s: %v", processName, err)
 		status.Stage = "failed"
-		status.Error = fmt.Sprintf("Failed to start new instance: %v", err)
+		status.Error = fmt.Sprintf("Failed to get process config: %v", err)
 		status.Completed = true
 		m.setUpdateStatus(processName, status)
 		return
 	}
-	log.Printf("[Update] New instance started for %s: ID=%s, PID=%d", processName, newInstance.ID, newInstance.PID)
 
-	// Wait for new instance to be healthy
-	log.Printf("[Update] Waiting 5 seconds for %s new instance to be healthy...", processName)
-	time.Sleep(5 * time.Second)
-
-	// Stage 4: Stop old instances gracefully
-	status.Stage = "stopping_old"
-	status.Message = "Gracefully stopping old instances"
-	status.Progress = 85
-	m.setUpdateStatus(processName, status)
-	log.Printf("[Update] Gracefully stopping old instances for %s", processName)
-
-	instances, _ := m.processManager.GetProcessStatus(processName)
-	log.Printf("[Update] Found %d total instances for %s", len(instances), processName)
-	stoppedCount := 0
-	gracefulTimeout := 30 * time.Second // 30 seconds timeout for graceful shutdown
+	status.Strategy = procConfig.Update.Strategy
+	var deployed bool
+	switch procConfig.Update.Strategy {
+	case "blue_green":
+		deployed = m.deployBlueGreen(processName, status, binaryPath, targetVersionInfo.Tag, procConfig.Update)
+	case "canary":
+		deployed = m.deployCanary(processName, status, binaryPath, targetVersionInfo.Tag, force, procConfig.Update)
+	default:
+		deployed = m.deployRolling(processName, status, binaryPath, targetVersionInfo.Tag, force, procConfig.Update)
+	}
+	if !deployed {
+		return
+	}
 
-	for _, inst := range instances {
-		log.Printf("[Update] Checking instance %s (status: %s, new instance: %s)", inst.ID, inst.GetStatus(), newInstance.ID)
-		if inst.ID != newInstance.ID && inst.GetStatus() == models.StatusRunning {
-			log.Printf("[Update] Gracefully stopping old instance %s for %s (timeout: %v)", inst.ID, processName, gracefulTimeout)
-
-			// Use graceful shutdown instead of immediate kill
-			if err := m.processManager.StopProcessGracefully(processName, inst.ID, gracefulTimeout); err != nil {
-				// Log error but continue
-				log.Printf("[Update] Warning: failed to gracefully stop old instance %s: %v", inst.ID, err)
-			} else {
-				stoppedCount++
-				log.Printf("[Update] Successfully stopped old instance %s", inst.ID)
-			}
-		}
+	// Restarts and scale-ups from now on run the new binary
+	if err := m.processManager.SetDeployedBinary(processName, binaryPath, targetVersionInfo.Tag); err != nil {
+		log.Printf("[Update] Warning: failed to set deployed binary for %s: %v", processName, err)
 	}
-	log.Printf("[Update] Stopped %d old instances from tracking list for %s", stoppedCount, processName)
 
-	// Stage 4.5: Fallback cleanup - Stop any old processes by binary name that aren't in the tracking list
+	// Stage 4: Fallback cleanup - Stop any old processes by binary name that aren't in the tracking list
 	// This catches orphaned processes that were removed from the tracking list before Hot Deploy
-	log.Printf("[Update] Running fallback cleanup to stop any orphaned %s processes (excluding PID %d)", processName, newInstance.PID)
-	additionalStopped, err := m.processManager.StopProcessesByBinaryName(repository, newInstance.PID, gracefulTimeout)
+	log.Printf("[Update] Running fallback cleanup to stop any orphaned %s processes", processName)
+	additionalStopped, err := m.processManager.StopProcessesByBinaryName(repository, procConfig.Update.StopTimeout)
 	if err != nil {
 		log.Printf("[Update] Warning: fallback cleanup failed: %v", err)
 	} else if additionalStopped > 0 {
 		log.Printf("[Update] ✅ Fallback cleanup stopped %d additional orphaned processes", additionalStopped)
-		stoppedCount += additionalStopped
 	} else {
 		log.Printf("[Update] Fallback cleanup found no additional processes to stop")
 	}
-	log.Printf("[Update] Total stopped: %d old instances for %s", stoppedCount, processName)
 
 	// Stage 5: Update version tracking
 	status.Stage = "updating_version"
@@ -266,8 +254,149 @@ func (m *Manager) performUpdate(processName, targetVersion string, force bool) {
 	log.Printf("[Update] ✅ Update completed for %s: v%s", processName, targetVersionInfo.Tag)
 }
 
+// deployRolling replaces the running instances with the new version in
+// batches. Reports whether the update can complete; if not, the rollout is
+// paused and status says why.
+func (m *Manager) deployRolling(processName string, status *models.UpdateStatus, binaryPath, version string, force bool, cfg models.UpdateConfig) bool {
+	// Instances already on the target version were started by a paused
+	// rollout; they count as updated so the rollout resumes
+	var oldInstances, updatedInstances []*models.ProcessInstance
+	instances, _ := m.processManager.GetProcessStatus(processName)
+	for _, inst := range instances {
+		if inst.GetStatus() != models.StatusRunning {
+			continue
+		}
+		if inst.Version == version && !force {
+			updatedInstances = append(updatedInstances, inst)
+		} else {
+			oldInstances = append(oldInstances, inst)
+		}
+	}
+
+	status.Stage = "rolling_update"
+	status.Paused = false
+	status.Message = fmt.Sprintf("Replacing %d instance(s) with version %s", len(oldInstances), version)
+	status.Progress = 75
+	m.setUpdateStatus(processName, status)
+	log.Printf("[Update] Rolling update of %s: %d old, %d already updated (max_surge=%d, max_unavailable=%d)",
+		processName, len(oldInstances), len(updatedInstances), cfg.MaxSurge, cfg.MaxUnavailable)
+
+	// Once new instances are ready, restarts and scale-ups run the new binary
+	prevBinary, prevVersion := m.processManager.GetDeployedBinary(processName)
+	deployed := false
+	report := m.rolloutProgress(processName, status)
+	progress := func(p process.RolloutProgress) {
+		if p.Updated > 0 && !deployed {
+			deployed = true
+			if err := m.processManager.SetDeployedBinary(processName, binaryPath, version); err != nil {
+				log.Printf("[Update] Warning: failed to set deployed binary for %s: %v", processName, err)
+			}
+		}
+		report(p)
+	}
+
+	started, err := m.processManager.RollingReplace(processName, process.Rollout{
+		Old:     oldInstances,
+		Updated: len(updatedInstances),
+		Start: process.StartOptions{
+			BinaryPath: binaryPath,
+			Version:    version,
+		},
+		Progress: progress,
+	})
+	if err != nil {
+		// Without new instances left the rollout is abandoned; otherwise
+		// updating again resumes it
+		if deployed && m.runningVersion(processName, version) == 0 {
+			if err := m.processManager.SetDeployedBinary(processName, prevBinary, prevVersion); err != nil {
+				log.Printf("[Update] Warning: failed to restore deployed binary for %s: %v", processName, err)
+			}
+		}
+
+		// The old instances still running keep serving
+		log.Printf("[Update] ERROR: Rolling update of %s paused: %v", processName, err)
+		status.Stage = "paused"
+		status.Paused = true
+		status.Error = fmt.Sprintf("Rolling update paused: %v", err)
+		status.Completed = true
+		m.setUpdateStatus(processName, status)
+		return false
+	}
+	log.Printf("[Update] Rolling update of %s replaced %d instance(s) with %d new instance(s)", processName, len(oldInstances), len(started))
+
+	return true
+}
+
+// runningVersion counts the running instances of a process on a version
+func (m *Manager) runningVersion(processName, version string) int {
+	n := 0
+	instances, _ := m.processManager.GetProcessStatus(processName)
+	for _, inst := range instances {
+		if inst.GetStatus() == models.StatusRunning && inst.Version == version {
+			n++
+		}
+	}
+	return n
+}
+
+// rolloutProgress returns a callback reporting the batches of a rollout in the
+// update status
+func (m *Manager) rolloutProgress(processName string, status *models.UpdateStatus) func(process.RolloutProgress) {
+	return func(p process.RolloutProgress) {
+		status.Batch = p.Batch
+		status.DesiredInstances = p.Desired
+		status.UpdatedInstances = p.Updated
+		status.OldInstances = p.Old
+		status.Progress = 75 + 20*float64(p.Updated)/float64(p.Desired)
+		status.Message = fmt.Sprintf("Batch %d: %d/%d instances updated, %d old remaining", p.Batch, p.Updated, p.Desired, p.Old)
+		m.setUpdateStatus(processName, status)
+	}
+}
+
+// RestoreDeployments makes processes run the binaries installed by earlier
+// updates, as recorded by version tracking. Call before starting processes.
+func (m *Manager) RestoreDeployments() {
+	for _, processName := range m.processManager.ListProcesses() {
+		repository := m.processManager.GetProcessRepository(processName)
+		if repository == "" {
+			continue
+		}
+		current, err := m.versionManager.GetCurrentVersion(processName)
+		if err != nil {
+			continue
+		}
+
+		binaryPath := m.getBinaryPathForRepository(repository, current.Tag)
+		if _, err := os.Stat(binaryPath); err != nil {
+			continue
+		}
+		if err := m.processManager.SetDeployedBinary(processName, binaryPath, current.Tag); err != nil {
+			log.Printf("[Update] Warning: failed to restore deployed binary for %s: %v", processName, err)
+			continue
+		}
+		log.Printf("[Update] %s runs deployed version %s (%s)", processName, current.Tag, binaryPath)
+	}
+}
+
+// GetCurrentVersion returns the version a process was last deployed or
+// started with ("" if unknown)
+func (m *Manager) GetCurrentVersion(processName string) string {
+	current, err := m.versionManager.GetCurrentVersion(processName)
+	if err != nil {
+		return ""
+	}
+	return current.Tag
+}
+
 // RollbackProcess rolls back a process to a previous version
 func (m *Manager) RollbackProcess(processName, targetVersion string) error {
+	// During a canary analysis, rolling back ends the canary; the old
+	// instances never stopped serving
+	if targetVersion == "" && m.endCanary(processName) {
+		log.Printf("[Update] Rolled back canary of %s", processName)
+		return nil
+	}
+
 	var versionToRollback *models.Version
 	var err error
 
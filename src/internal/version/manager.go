@@ -115,6 +115,23 @@ func (m *Manager) SetCurrentVersion(processName string, version *models.Version)
 	return m.SaveVersionInfo(info)
 }
 
+// RecordCanaryAnalysis adds the result of a canary update to the history of a
+// process
+func (m *Manager) RecordCanaryAnalysis(processName string, analysis models.CanaryAnalysis) error {
+	info, err := m.LoadVersionInfo(processName)
+	if err != nil {
+		return err
+	}
+
+	info.CanaryAnalyses = append([]models.CanaryAnalysis{analysis}, info.CanaryAnalyses...)
+	// Keep only the last 10 analyses
+	if len(info.CanaryAnalyses) > 10 {
+		info.CanaryAnalyses = info.CanaryAnalyses[:10]
+	}
+
+	return m.SaveVersionInfo(info)
+}
+
 // CheckForUpdates checks if there's a newer version available
 func (m *Manager) CheckForUpdates(processName, repository string) (*models.VersionInfo, error) {
 	info, err := m.LoadVersionInfo(processName)
@@ -10,6 +10,15 @@ type Config struct {
 	Secrets        SecretsConfig        `yaml:"secrets"`
 	GitHub         GitHubConfig         `yaml:"github"`
 	Tunnel         *TunnelConfig        `yaml:"tunnel,omitempty"`
+
+	// Metrics history sampling
+	Metrics MetricsConfig `yaml:"metrics"`
+
+	// Alert rules and notifiers
+	Alerting *AlertingConfig `yaml:"alerting,omitempty"`
+
+	// Access log of calls through load balancers and /proxy/ (optional)
+	AccessLog *AccessLogConfig `yaml:"access_log,omitempty"`
 }
 
 // ServerConfig contains the server configuration
@@ -34,6 +43,70 @@ type ProcessConfig struct {
 	AutoRestart  bool              `yaml:"auto_restart"`
 	MaxInstances int               `yaml:"max_instances"`
 	SecretsKeys  []string          `yaml:"secrets_keys,omitempty"` // Cloudflare secret keys to fetch
+
+	// Scale-to-zero: stop idle instances and start them again on incoming requests
+	ScaleToZero *ScaleToZeroConfig `yaml:"scale_to_zero,omitempty"`
+
+	// Time-based scaling schedules evaluated by the supervisor
+	Scaling *ScalingConfig `yaml:"scaling,omitempty"`
+
+	// How updates and hot restarts replace running instances
+	Update UpdateConfig `yaml:"update,omitempty"`
+}
+
+// UpdateConfig controls how updates and hot restarts replace instances. A
+// rolling update replaces them in batches: each batch starts new instances,
+// waits until they are ready and then stops as many old ones. A blue/green
+// update starts a full set of new instances, switches all traffic to them at
+// once and keeps the old set running idle for bake_time so traffic can be
+// switched back. A canary update starts a few new instances, sends them a
+// growing share of traffic and promotes the new version with a rolling update
+// only if their error rate, latency and crashes stay close to the old
+// instances'. Hot restarts are always rolling.
+type UpdateConfig struct {
+	Strategy       string        `yaml:"strategy"`        // "rolling" (default), "blue_green" or "canary"
+	MaxSurge       int           `yaml:"max_surge"`       // Instances started above the current count (default: 1)
+	MaxUnavailable int           `yaml:"max_unavailable"` // Instances that may be missing below the current count (default: 0)
+	ReadyTimeout   time.Duration `yaml:"ready_timeout"`   // Time for a new instance to pass its health check (default: 60s)
+	MinReadyTime   time.Duration `yaml:"min_ready_time"`  // Time a new instance must keep running before it counts as ready (default: 5s)
+	StopTimeout    time.Duration `yaml:"stop_timeout"`    // Graceful stop timeout of replaced instances (default: 30s)
+	BakeTime       time.Duration `yaml:"bake_time"`       // blue_green: Time the old set stays available for switch-back (default: 10m)
+	Canary         CanaryConfig  `yaml:"canary,omitempty"` // canary: Traffic steps and analysis thresholds
+}
+
+// CanaryConfig controls the traffic steps of a canary update and when the
+// canary counts as worse than the baseline (the old instances)
+type CanaryConfig struct {
+	Instances            int           `yaml:"instances"`               // Canary instances started (default: 1)
+	Steps                []int         `yaml:"steps"`                   // Percentages of traffic sent to the canary, in order (default: [10, 50])
+	StepDuration         time.Duration `yaml:"step_duration"`           // Time each step is analyzed (default: 5m)
+	MinRequests          int           `yaml:"min_requests"`            // Canary calls a step needs to pass (default: 20)
+	AllowLowTraffic      bool          `yaml:"allow_low_traffic"`       // Judge steps with fewer calls by crashes only instead of failing them
+	MaxErrorRateIncrease float64       `yaml:"max_error_rate_increase"` // Percentage points the canary error rate may exceed the baseline's (default: 1)
+	MaxLatencyRatio      float64       `yaml:"max_latency_ratio"`       // Max ratio of canary to baseline average latency (default: 1.5)
+	MaxCrashes           int           `yaml:"max_crashes"`             // Canary crashes allowed beyond the baseline's (default: 0)
+}
+
+// ScaleToZeroConfig contains idle shutdown and wake-on-request configuration
+type ScaleToZeroConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	IdleTimeout time.Duration `yaml:"idle_timeout"`  // Stop all instances after this long without requests
+	MaxWakeWait time.Duration `yaml:"max_wake_wait"` // Max time a request is held while an instance starts
+}
+
+// ScalingConfig contains time-based scaling configuration
+type ScalingConfig struct {
+	Timezone         string            `yaml:"timezone,omitempty"` // IANA name, e.g. "Asia/Tokyo" (default: local time)
+	DefaultInstances int               `yaml:"default_instances"`  // Instances when no schedule is active
+	Schedules        []ScalingSchedule `yaml:"schedules"`
+}
+
+// ScalingSchedule sets the instance count between two cron boundaries
+type ScalingSchedule struct {
+	Name      string `yaml:"name,omitempty"`
+	Start     string `yaml:"start"` // Cron expression (minute hour dom month dow) when the schedule begins
+	End       string `yaml:"end"`   // Cron expression when the schedule ends
+	Instances int    `yaml:"instances"`
 }
 
 // HealthCheckConfig contains health check configuration
@@ -89,6 +162,68 @@ type WebhookConfig struct {
 	Path    string `yaml:"path"`
 }
 
+// MetricsConfig contains metrics history configuration
+type MetricsConfig struct {
+	SampleInterval time.Duration `yaml:"sample_interval"` // Interval of the background sampler (default: 10s)
+	Persist        bool          `yaml:"persist"`         // Persist history to the data directory across restarts
+}
+
+// AccessLogConfig writes one JSON line per proxied call to a rotating file
+type AccessLogConfig struct {
+	Path         string   `yaml:"path"`                    // Log file (default: logs/access.log)
+	MaxSizeMB    int      `yaml:"max_size_mb"`             // Rotate when the file reaches this size (default: 100)
+	MaxBackups   int      `yaml:"max_backups"`             // Rotated files to keep (default: 5)
+	SampleRate   float64  `yaml:"sample_rate"`             // Fraction of successful calls to log (default: 1); failed calls are always logged
+	MetadataKeys []string `yaml:"metadata_keys,omitempty"` // Request metadata (HTTP headers) to record
+	Recent       int      `yaml:"recent"`                  // Entries kept in memory for the API (default: 1000)
+}
+
+// AlertingConfig contains alert rules, notifiers and silences
+type AlertingConfig struct {
+	RepeatInterval time.Duration    `yaml:"repeat_interval"` // Re-notify firing alerts after this interval (default: 4h)
+	Rules          []AlertRule      `yaml:"rules"`
+	Notifiers      []NotifierConfig `yaml:"notifiers"`
+	Silences       []SilenceConfig  `yaml:"silences,omitempty"`
+}
+
+// AlertRule is a condition that fires an alert once it has held for the For duration
+type AlertRule struct {
+	Name      string        `yaml:"name"`
+	Expr      string        `yaml:"expr"`                // e.g. status == "failed" || restarts_1h > 3
+	For       time.Duration `yaml:"for,omitempty"`       // How long the condition must hold before firing
+	Severity  string        `yaml:"severity"`            // "info", "warning" or "critical" (default: "warning")
+	Processes []string      `yaml:"processes,omitempty"` // Limit process rules to these processes (default: all)
+	Summary   string        `yaml:"summary,omitempty"`   // Human-readable description; {{process}} is replaced
+	Notifiers []string      `yaml:"notifiers,omitempty"` // Notifier names (default: all)
+}
+
+// NotifierConfig configures an alert notification channel
+type NotifierConfig struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`              // "webhook", "slack" or "email"
+	URL     string            `yaml:"url,omitempty"`     // webhook / slack
+	Headers map[string]string `yaml:"headers,omitempty"` // webhook
+	SMTP    *SMTPConfig       `yaml:"smtp,omitempty"`    // email
+}
+
+// SMTPConfig contains SMTP settings for email notifications
+type SMTPConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"` // default: 587
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// SilenceConfig suppresses notifications of matching alerts
+type SilenceConfig struct {
+	Matchers map[string]string `yaml:"matchers"` // Label matchers: alertname, process, severity
+	StartsAt time.Time         `yaml:"starts_at,omitempty"`
+	EndsAt   time.Time         `yaml:"ends_at,omitempty"` // Zero = never expires
+	Comment  string            `yaml:"comment,omitempty"`
+}
+
 // TunnelConfig contains Cloudflare Tunnel configuration
 type TunnelConfig struct {
 	Enabled        bool   `yaml:"enabled"`
@@ -106,11 +241,178 @@ type LoadBalancerConfig struct {
 	ListenPort int                 `yaml:"listen_port"`
 	Protocol   string              `yaml:"protocol"` // "grpc" or "http"
 	Routes     []LoadBalancerRoute `yaml:"routes"`
+
+	// Passive health checking and per-backend limits (optional)
+	OutlierDetection *OutlierDetectionConfig `yaml:"outlier_detection,omitempty"`
+	CircuitBreaker   *CircuitBreakerConfig   `yaml:"circuit_breaker,omitempty"`
+
+	// HTTP mode: speak HTTP/2 to backends instead of HTTP/1.1 (h2c without backend TLS)
+	BackendHTTP2 bool `yaml:"backend_http2,omitempty"`
+
+	// TLS termination on the listener and TLS to backends (optional)
+	TLS        *ListenerTLSConfig `yaml:"tls,omitempty"`
+	BackendTLS *BackendTLSConfig  `yaml:"backend_tls,omitempty"`
+
+	// gRPC mode: also accept browser clients (gRPC-Web, Connect) on the listener (optional)
+	Web *WebConfig `yaml:"web,omitempty"`
+}
+
+// WebConfig accepts browser protocols over HTTP/1.1 on a grpc load balancer
+// listener next to native gRPC. Calls are routed like native gRPC calls.
+type WebConfig struct {
+	GRPCWeb         bool     `yaml:"grpc_web,omitempty"`          // gRPC-Web (application/grpc-web and grpc-web-text)
+	Connect         bool     `yaml:"connect,omitempty"`           // Connect protocol with binary protobuf messages
+	AllowedOrigins  []string `yaml:"allowed_origins,omitempty"`   // CORS origins ("*" allows any; empty: same-origin only)
+	AllowedHeaders  []string `yaml:"allowed_headers,omitempty"`   // Request headers allowed for cross-origin calls in addition to the protocol's own
+	MaxMessageBytes int      `yaml:"max_message_bytes,omitempty"` // Largest Connect message (default 4MiB)
+}
+
+// ListenerTLSConfig terminates TLS on a load balancer listener. Certificate
+// files are re-read when they change without restarting the listener.
+type ListenerTLSConfig struct {
+	CertFile     string   `yaml:"cert_file,omitempty"`      // PEM certificate (default: generated as "lb-<name>" in the certs directory)
+	KeyFile      string   `yaml:"key_file,omitempty"`       // PEM private key
+	Hosts        []string `yaml:"hosts,omitempty"`          // Names of the generated certificate (default: localhost, 127.0.0.1)
+	ClientCAFile string   `yaml:"client_ca_file,omitempty"` // CA bundle to verify client certificates (mTLS)
+	ClientAuth   string   `yaml:"client_auth,omitempty"`    // "require" (default with client_ca_file) or "optional"
+}
+
+// BackendTLSConfig connects to backends over TLS. By default each backend is
+// verified against the certificate generated for its process.
+type BackendTLSConfig struct {
+	CAFile         string `yaml:"ca_file,omitempty"`          // CA bundle for backend certificates instead of the generated ones
+	ServerName     string `yaml:"server_name,omitempty"`      // Name to verify (default: localhost)
+	ClientCertFile string `yaml:"client_cert_file,omitempty"` // Certificate presented to backends (mTLS)
+	ClientKeyFile  string `yaml:"client_key_file,omitempty"`
+	Mutual         bool   `yaml:"mutual,omitempty"` // Present the generated "lb-<name>" certificate when no client cert is set
+}
+
+// OutlierDetectionConfig ejects backends that keep failing. Only backend
+// failures count (UNAVAILABLE, INTERNAL, UNKNOWN, DATA_LOSS, DEADLINE_EXCEEDED).
+type OutlierDetectionConfig struct {
+	ConsecutiveErrors  int           `yaml:"consecutive_errors"`   // Eject after this many failures in a row (default: 5 unless error_rate is set)
+	ErrorRate          float64       `yaml:"error_rate"`           // Eject when the failure ratio in a window reaches this (0-1, 0 = off)
+	Window             time.Duration `yaml:"window"`               // Error rate window (default: 30s)
+	MinRequests        int           `yaml:"min_requests"`         // Requests needed in a window before the error rate applies (default: 20)
+	BaseEjectionTime   time.Duration `yaml:"base_ejection_time"`   // Ejection time, multiplied by the number of ejections (default: 30s)
+	MaxEjectionTime    time.Duration `yaml:"max_ejection_time"`    // Upper bound for one ejection (default: 5m)
+	MaxEjectionPercent int           `yaml:"max_ejection_percent"` // Max share of a process's backends ejected at once (default: 50)
+}
+
+// CircuitBreakerConfig limits the load on each backend
+type CircuitBreakerConfig struct {
+	MaxConcurrentRequests int `yaml:"max_concurrent_requests"` // Per backend; calls beyond it go elsewhere or fail (0 = unlimited)
 }
 
 // LoadBalancerRoute defines routing rules for load balancer
 type LoadBalancerRoute struct {
-	Methods         []string `yaml:"methods"`          // Regex patterns for method names
+	Name            string   `yaml:"name,omitempty"`   // Route name used in metrics (default: route_<index>)
+	Methods         []string `yaml:"methods"`          // Regex patterns for method names (grpc, default: all)
 	TargetProcesses []string `yaml:"target_processes"` // Process names to route to
-	Strategy        string   `yaml:"strategy"`         // "primary", "round_robin", "least_connections"
+	Strategy        string   `yaml:"strategy"`         // "primary", "round_robin", "least_connections", "least_latency", "hash"
+
+	// Metadata (grpc) or header (http) conditions; all must match in
+	// addition to methods/hosts/path_prefix
+	Headers []HeaderMatch `yaml:"headers,omitempty"`
+
+	// Used for calls that match no other route, regardless of its position
+	// and match conditions. At most one route may be the default.
+	Default bool `yaml:"default,omitempty"`
+
+	// Relative traffic share per target process (e.g. 95 / 5). When set, a
+	// target is chosen by weight first and the strategy picks an instance of it.
+	Weights map[string]int `yaml:"weights,omitempty"`
+
+	// Metadata key hashed by the "hash" strategy (e.g. "x-session-id").
+	// Calls without it, or routes without a key, hash the client IP address.
+	HashKey string `yaml:"hash_key,omitempty"`
+
+	// Deadline applied to calls that arrive without one (0 = none)
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Retry and hedging policies (optional, grpc only)
+	Retry *RetryPolicy `yaml:"retry,omitempty"`
+	Hedge *HedgePolicy `yaml:"hedge,omitempty"`
+
+	// Copy sampled calls to a shadow process (optional, grpc only)
+	Mirror *MirrorPolicy `yaml:"mirror,omitempty"`
+
+	// Rate and concurrency limits (optional)
+	RateLimit *RateLimitPolicy `yaml:"rate_limit,omitempty"`
+
+	// HTTP matching: all given conditions must match (none = match everything)
+	Hosts       []string `yaml:"hosts,omitempty"`        // Host names, "*.example.com" matches subdomains
+	PathPrefix  string   `yaml:"path_prefix,omitempty"`  // e.g. "/api/"
+	StripPrefix bool     `yaml:"strip_prefix,omitempty"` // Remove path_prefix before forwarding
+
+	// Header rewriting. Request headers also apply to gRPC metadata;
+	// response headers are http only.
+	RequestHeaders  *HeaderRewrite `yaml:"request_headers,omitempty"`
+	ResponseHeaders *HeaderRewrite `yaml:"response_headers,omitempty"`
+}
+
+// HeaderMatch is a condition on a metadata key or HTTP header. Exactly one of
+// Exact, Prefix, Regex and Present must be set; value conditions match if any
+// of the values sent for the name matches.
+type HeaderMatch struct {
+	Name    string `yaml:"name"`
+	Exact   string `yaml:"exact,omitempty"`
+	Prefix  string `yaml:"prefix,omitempty"`
+	Regex   string `yaml:"regex,omitempty"`
+	Present *bool  `yaml:"present,omitempty"` // true: must be sent, false: must not be sent
+}
+
+// HeaderRewrite modifies HTTP headers
+type HeaderRewrite struct {
+	Set    map[string]string `yaml:"set,omitempty"`    // Replace or create
+	Add    map[string]string `yaml:"add,omitempty"`    // Append a value
+	Remove []string          `yaml:"remove,omitempty"` // Delete
+}
+
+// RetryPolicy retries failed calls on another backend. A call is only retried
+// while no response has been sent to the client and the request messages
+// received so far fit in the replay buffer.
+type RetryPolicy struct {
+	MaxAttempts    int           `yaml:"max_attempts"`     // Total attempts including the first (default: 3)
+	InitialBackoff time.Duration `yaml:"initial_backoff"`  // Backoff before the first retry (default: 50ms)
+	MaxBackoff     time.Duration `yaml:"max_backoff"`      // Upper bound for the backoff (default: 1s)
+	RetryableCodes []string      `yaml:"retryable_codes"`  // gRPC status codes, e.g. "UNAVAILABLE" (default)
+	MaxBufferBytes int           `yaml:"max_buffer_bytes"` // Request bytes kept for replay (default: 64KiB)
+}
+
+// MirrorPolicy asynchronously copies unary calls to a shadow process whose
+// responses are discarded. Only calls with a single request message are
+// mirrored, after the primary call has finished.
+type MirrorPolicy struct {
+	TargetProcess  string        `yaml:"target_process"`   // Shadow process
+	Percent        float64       `yaml:"percent"`          // Share of calls to copy (default: 100)
+	Timeout        time.Duration `yaml:"timeout"`          // Deadline of shadow calls (default: 10s)
+	MaxBufferBytes int           `yaml:"max_buffer_bytes"` // Larger requests are not mirrored (default: 64KiB)
+}
+
+// RateLimitPolicy limits the calls a route accepts, for the route as a whole
+// and optionally for each client. Calls over a limit fail with
+// RESOURCE_EXHAUSTED (429 in HTTP mode) and a retry-after hint.
+type RateLimitPolicy struct {
+	RequestsPerSecond float64          `yaml:"requests_per_second,omitempty" json:"requests_per_second,omitempty"` // Token refill rate (0 = no rate limit)
+	Burst             int              `yaml:"burst,omitempty" json:"burst,omitempty"`                             // Bucket size (default: requests_per_second rounded up)
+	MaxConcurrent     int              `yaml:"max_concurrent,omitempty" json:"max_concurrent,omitempty"`           // Calls in flight (0 = unlimited)
+	PerClient         *ClientRateLimit `yaml:"per_client,omitempty" json:"per_client,omitempty"`                   // Separate limits for each client
+}
+
+// ClientRateLimit applies limits to each client separately
+type ClientRateLimit struct {
+	Key               string  `yaml:"key,omitempty" json:"key,omitempty"` // Metadata key (header in HTTP mode) naming the client; client IP if empty or not sent
+	RequestsPerSecond float64 `yaml:"requests_per_second,omitempty" json:"requests_per_second,omitempty"`
+	Burst             int     `yaml:"burst,omitempty" json:"burst,omitempty"`
+	MaxConcurrent     int     `yaml:"max_concurrent,omitempty" json:"max_concurrent,omitempty"`
+}
+
+// HedgePolicy sends additional copies of idempotent calls when the first one
+// is slow; the first response wins and the other attempts are cancelled.
+type HedgePolicy struct {
+	Methods        []string      `yaml:"methods"`          // Regex patterns of idempotent methods
+	Delay          time.Duration `yaml:"delay"`            // Wait before sending the next copy (default: 100ms)
+	MaxAttempts    int           `yaml:"max_attempts"`     // Total copies including the first (default: 2)
+	MaxBufferBytes int           `yaml:"max_buffer_bytes"` // Request bytes kept for replay (default: 64KiB)
 }
@@ -28,7 +28,8 @@ type ProcessInstance struct {
 	StartTime   time.Time
 	PID         int
 	Port        int
-	Version     string
+	Version     string // Version of the binary the instance runs ("" if unknown)
+	BinaryPath  string // Binary the instance was started from
 	EnvFilePath string
 	StderrBuf   *bytes.Buffer  // Capture stderr for error logging
 	mu          sync.RWMutex
@@ -52,7 +53,51 @@ func (p *ProcessInstance) SetStatus(status ProcessStatus) {
 type ManagedProcess struct {
 	Config    ProcessConfig
 	Instances []*ProcessInstance
+	restarts  int
 	mu        sync.RWMutex
+
+	// Binary installed by the last deploy; new instances run it instead of
+	// the configured or detected binary
+	deployedBinary  string
+	deployedVersion string
+
+	// Instances that do not receive traffic from load balancers and /proxy/
+	// (blue/green deploys)
+	standby map[string]bool
+
+	// Canary instances and the percentage of traffic they receive (canary
+	// updates); the map is replaced, never modified
+	canary        map[string]bool
+	canaryPercent int
+}
+
+// IncrementRestarts records an automatic restart of a failed instance
+func (m *ManagedProcess) IncrementRestarts() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.restarts++
+}
+
+// GetRestartCount returns the number of automatic restarts since startup
+func (m *ManagedProcess) GetRestartCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.restarts
+}
+
+// SetDeployedBinary sets the binary that new instances run
+func (m *ManagedProcess) SetDeployedBinary(path, version string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deployedBinary = path
+	m.deployedVersion = version
+}
+
+// GetDeployedBinary returns the binary installed by the last deploy ("" if none)
+func (m *ManagedProcess) GetDeployedBinary() (path, version string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.deployedBinary, m.deployedVersion
 }
 
 // AddInstance adds a new instance to the managed process
@@ -73,6 +118,56 @@ func (m *ManagedProcess) RemoveInstance(instanceID string) {
 			break
 		}
 	}
+	delete(m.standby, instanceID)
+}
+
+// SetStandby makes the active instances receive traffic and the standby ones
+// stop receiving it, in one step
+func (m *ManagedProcess) SetStandby(active, standby []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.standby == nil {
+		m.standby = make(map[string]bool)
+	}
+	for _, id := range active {
+		delete(m.standby, id)
+	}
+	for _, id := range standby {
+		m.standby[id] = true
+	}
+}
+
+// SetCanary sends percent of the traffic to the canary instances and the rest
+// to the others; no instances end the split
+func (m *ManagedProcess) SetCanary(instanceIDs []string, percent int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(instanceIDs) == 0 {
+		m.canary, m.canaryPercent = nil, 0
+		return
+	}
+	canary := make(map[string]bool, len(instanceIDs))
+	for _, id := range instanceIDs {
+		canary[id] = true
+	}
+	m.canary, m.canaryPercent = canary, percent
+}
+
+// GetCanary returns the canary instances and their share of traffic (nil if
+// there is no canary). The map must not be modified.
+func (m *ManagedProcess) GetCanary() (map[string]bool, int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.canary, m.canaryPercent
+}
+
+// IsStandby reports whether an instance is kept out of traffic
+func (m *ManagedProcess) IsStandby(instanceID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.standby[instanceID]
 }
 
 // GetInstances returns all instances
@@ -98,3 +193,17 @@ func (m *ManagedProcess) GetRunningInstances() []*ProcessInstance {
 	}
 	return running
 }
+
+// GetRoutableInstances returns the running instances that receive traffic
+func (m *ManagedProcess) GetRoutableInstances() []*ProcessInstance {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var routable []*ProcessInstance
+	for _, inst := range m.Instances {
+		if inst.GetStatus() == StatusRunning && !m.standby[inst.ID] {
+			routable = append(routable, inst)
+		}
+	}
+	return routable
+}
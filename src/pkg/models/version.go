@@ -21,6 +21,7 @@ type VersionInfo struct {
 	LatestVersion  *Version  `json:"latest_version"`
 	UpdateAvailable bool     `json:"update_available"`
 	History        []Version `json:"history,omitempty"`
+	CanaryAnalyses []CanaryAnalysis `json:"canary_analyses,omitempty"` // Newest first
 }
 
 // UpdateRequest represents a request to update a process
@@ -38,6 +39,53 @@ type UpdateStatus struct {
 	Message     string  `json:"message"`
 	Error       string  `json:"error,omitempty"`
 	Completed   bool    `json:"completed"`
+
+	// Rollout progress while instances are replaced
+	Strategy         string `json:"strategy,omitempty"`
+	Batch            int    `json:"batch,omitempty"`             // Current batch, starting at 1
+	DesiredInstances int    `json:"desired_instances,omitempty"` // Instances running when the rollout is done
+	UpdatedInstances int    `json:"updated_instances"`           // Ready instances of the new version
+	OldInstances     int    `json:"old_instances"`               // Old instances still running
+	Paused           bool   `json:"paused,omitempty"`            // Stopped after a batch failed; updating again resumes
+
+	// Blue/green: until when traffic can be switched back to the old instances
+	SwitchBackUntil *time.Time `json:"switch_back_until,omitempty"`
+
+	// Canary: share of traffic sent to the canary and the analysis so far
+	CanaryWeight int             `json:"canary_weight,omitempty"`
+	Canary       *CanaryAnalysis `json:"canary,omitempty"`
+}
+
+// CanaryAnalysis is the result of a canary update
+type CanaryAnalysis struct {
+	Version  string       `json:"version"`  // Version of the canary instances
+	Baseline string       `json:"baseline"` // Version of the old instances ("" if unknown)
+	Started  time.Time    `json:"started"`
+	Finished *time.Time   `json:"finished,omitempty"`
+	Result   string       `json:"result,omitempty"` // "promoted" or "rolled_back" once finished
+	Reason   string       `json:"reason,omitempty"`
+	Steps    []CanaryStep `json:"steps"`
+}
+
+// CanaryStep compares the canary with the baseline over one traffic step
+type CanaryStep struct {
+	Weight   int           `json:"weight"` // Percentage of traffic sent to the canary
+	Started  time.Time     `json:"started"`
+	Duration float64       `json:"duration_seconds"`
+	Canary   CanaryMetrics `json:"canary"`
+	Baseline CanaryMetrics `json:"baseline"`
+	Passed   bool          `json:"passed"`
+	Reason   string        `json:"reason,omitempty"` // Why the step failed, or why a comparison was skipped
+}
+
+// CanaryMetrics are the calls and crashes of a group of instances during a step
+type CanaryMetrics struct {
+	Instances    int     `json:"instances"`
+	Requests     uint64  `json:"requests"`
+	Errors       uint64  `json:"errors"`
+	ErrorRate    float64 `json:"error_rate_percent"`
+	LatencyAvgMs float64 `json:"latency_avg_ms"`
+	Crashes      int     `json:"crashes"` // Instances of the group that have crashed since the canary started
 }
 
 // RollbackRequest represents a request to rollback a process